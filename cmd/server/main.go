@@ -1,11 +1,20 @@
 package main
 
 import (
+	"RESTCryptoServer/internal/alert"
 	"RESTCryptoServer/internal/auth"
+	"RESTCryptoServer/internal/catalog"
 	"RESTCryptoServer/internal/crypto"
 	"RESTCryptoServer/internal/db"
+	"RESTCryptoServer/internal/grpcapi"
+	"RESTCryptoServer/internal/history"
+	"RESTCryptoServer/internal/portfolio"
+	"RESTCryptoServer/internal/provider"
 	"RESTCryptoServer/internal/redis"
+	"RESTCryptoServer/internal/stream"
 	"RESTCryptoServer/internal/updater"
+	"RESTCryptoServer/internal/watchlist"
+	"RESTCryptoServer/internal/webhook"
 	"RESTCryptoServer/monitoring"
 	"context"
 	"log"
@@ -46,6 +55,48 @@ func main() {
 	}
 	defer cryptodb.Close()
 
+	catalogdb, err := db.NewCatalogDB()
+	if err != nil {
+		log.Println("error during opening/creation coin catalog postgres db: ", err)
+		return
+	}
+	defer catalogdb.Close()
+
+	historydb, err := db.NewHistoryDB()
+	if err != nil {
+		log.Println("error during opening/creation price history postgres db: ", err)
+		return
+	}
+	defer historydb.Close()
+
+	watchlistdb, err := db.NewWatchlistDB()
+	if err != nil {
+		log.Println("error during opening/creation watchlist postgres db: ", err)
+		return
+	}
+	defer watchlistdb.Close()
+
+	portfoliodb, err := db.NewPortfolioDB()
+	if err != nil {
+		log.Println("error during opening/creation portfolio postgres db: ", err)
+		return
+	}
+	defer portfoliodb.Close()
+
+	alertdb, err := db.NewAlertDB()
+	if err != nil {
+		log.Println("error during opening/creation alert postgres db: ", err)
+		return
+	}
+	defer alertdb.Close()
+
+	webhookdb, err := db.NewWebhookDB()
+	if err != nil {
+		log.Println("error during opening/creation webhook postgres db: ", err)
+		return
+	}
+	defer webhookdb.Close()
+
 	cache, err := redis.NewRedisClient()
 	if err != nil {
 		log.Println("error during cache redis db: ", err)
@@ -55,9 +106,51 @@ func main() {
 
 	monitoring.Logger.Info().Msg("All database connections established")
 
+	priceProvider, err := provider.NewPriceProvider()
+	if err != nil {
+		log.Println("error during price provider creation: ", err)
+		return
+	}
+
+	monitoring.Logger.Info().Str("provider", priceProvider.Name()).Msg("Price provider selected")
+
+	breaker := provider.NewCircuitBreaker(priceProvider)
+
+	coinCatalog := catalog.NewCoinCatalog(breaker, catalogdb)
+	if err := coinCatalog.Load(context.Background()); err != nil {
+		monitoring.Logger.Warn().Err(err).Msg("Coin catalog unavailable, will retry on first lookup")
+	}
+	coinCatalog.StartRefreshing()
+
 	authService := auth.NewAuthService(userdb)
-	cryptoService := crypto.NewCryptoService(cryptodb, cache)
+	cryptoService := crypto.NewCryptoService(cryptodb, historydb, cache, coinCatalog)
 	updaterService := updater.NewUpdater(cryptoService, 30)
+	watchlistService := watchlist.NewWatchlistService(watchlistdb, cryptoService)
+	portfolioService := portfolio.NewPortfolioService(portfoliodb, cryptoService)
+	alertService := alert.NewAlertService(alertdb, cryptoService)
+
+	webhookDispatcher := webhook.NewDispatcher(webhookdb)
+	webhookService := webhook.NewWebhookService(webhookdb, webhookDispatcher)
+
+	alertEvaluator := alert.NewEvaluator(alertdb, cryptoService)
+	alertEvaluator.OnFire(webhookDispatcher.AlertFired)
+	cryptoService.OnPriceUpdate(alertEvaluator.Notify)
+	cryptoService.OnPriceUpdate(webhookDispatcher.PriceUpdated)
+	alertEvaluator.Start()
+	webhookDispatcher.Start()
+
+	streamHub := stream.NewHub(cache)
+	cryptoService.OnPriceUpdate(streamHub.Publish)
+	streamHub.Start()
+
+	historyMaintainer := history.NewMaintainer(historydb)
+	historyMaintainer.Start()
+
+	if filled, err := cryptoService.BackfillCoinIDs(context.Background()); err != nil {
+		monitoring.Logger.Warn().Err(err).Msg("Failed to backfill coin IDs")
+	} else if filled > 0 {
+		monitoring.Logger.Info().Int("count", filled).Msg("Backfilled coin IDs for tracked cryptocurrencies")
+	}
 
 	router := chi.NewRouter()
 
@@ -67,13 +160,14 @@ func main() {
 	router.Use(monitoring.TracingMiddleware)
 	router.Use(monitoring.MetricsMiddleware)
 
-	router.Use(middleware.Throttle(100))
+	router.Use(stream.ExceptStreams(middleware.Throttle(100)))
 
 	router.Use(middleware.SetHeader("Access-Control-Allow-Origin", "*"))
 	router.Use(middleware.SetHeader("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS"))
 	router.Use(middleware.SetHeader("Access-Control-Allow-Headers", "Authorization, Content-Type"))
 
-	router.Get("/health", monitoring.HealthHandler(userdb, cryptodb, cache))
+	router.Get("/health", monitoring.HealthHandler(userdb, cryptodb, cache,
+		monitoring.NamedCheck{Name: "price_provider", Check: breaker.HealthCheck}))
 	router.Get("/ready", monitoring.ReadinessHandler(userdb, cryptodb, cache))
 	router.Get("/live", monitoring.LivenessHandler())
 
@@ -111,16 +205,68 @@ func main() {
 	router.Post("/auth/register", auth.RegisterHandler(authService))
 	router.Post("/auth/login", auth.LoginHandler(authService))
 
+	router.With(auth.StreamAuthMiddleware).Get("/ws", stream.WSHandler(streamHub))
+	router.With(auth.StreamAuthMiddleware).Get("/crypto/stream", stream.SSEHandler(streamHub))
+
 	router.Group(func(r chi.Router) {
 		r.Use(auth.AuthMiddleware)
 		
 		r.Get("/crypto", crypto.GETCryptosHandler(cryptoService))
 		r.Post("/crypto", crypto.POSTCryptoHandler(cryptoService))
+		r.Get("/crypto/compare", crypto.GETCryptoCompareHandler(cryptoService))
 		r.Get("/crypto/{symbol}", crypto.GETCryptoSymbolHandler(cryptoService))
 		r.Put("/crypto/{symbol}/refresh", crypto.PUTCryptoSymbolRefreshHandler(cryptoService))
 		r.Get("/crypto/{symbol}/history", crypto.GETCryptoHistoryHandler(cryptoService))
+		r.Get("/crypto/{symbol}/candles", crypto.GETCryptoCandlesHandler(cryptoService))
+		r.Get("/crypto/{symbol}/indicators", crypto.GETCryptoIndicatorsHandler(cryptoService))
 		r.Get("/crypto/{symbol}/stats", crypto.GETCryptoStatsHandler(cryptoService))
+		r.Post("/crypto/{symbol}/backfill", crypto.POSTCryptoBackfillHandler(cryptoService))
+		r.Get("/crypto/{symbol}/backfill", crypto.GETCryptoBackfillHandler(cryptoService))
 		r.Delete("/crypto/{symbol}", crypto.DELETECryptoSymbolHandler(cryptoService))
+
+		r.Get("/convert", crypto.GETConvertHandler(cryptoService))
+
+		r.Get("/watchlists", watchlist.GETWatchlistsHandler(watchlistService))
+		r.Post("/watchlists", watchlist.POSTWatchlistHandler(watchlistService))
+		r.Get("/watchlists/{id}", watchlist.GETWatchlistHandler(watchlistService))
+		r.Put("/watchlists/{id}", watchlist.PUTWatchlistHandler(watchlistService))
+		r.Delete("/watchlists/{id}", watchlist.DELETEWatchlistHandler(watchlistService))
+		r.Post("/watchlists/{id}/symbols", watchlist.POSTWatchlistSymbolHandler(watchlistService))
+		r.Delete("/watchlists/{id}/symbols/{symbol}", watchlist.DELETEWatchlistSymbolHandler(watchlistService))
+
+		r.Get("/portfolios", portfolio.GETPortfoliosHandler(portfolioService))
+		r.Post("/portfolios", portfolio.POSTPortfolioHandler(portfolioService))
+		r.Get("/portfolios/{id}", portfolio.GETPortfolioHandler(portfolioService))
+		r.Put("/portfolios/{id}", portfolio.PUTPortfolioHandler(portfolioService))
+		r.Delete("/portfolios/{id}", portfolio.DELETEPortfolioHandler(portfolioService))
+		r.Post("/portfolios/{id}/positions", portfolio.POSTPositionHandler(portfolioService))
+		r.Put("/portfolios/{id}/positions/{symbol}", portfolio.PUTPositionHandler(portfolioService))
+		r.Delete("/portfolios/{id}/positions/{symbol}", portfolio.DELETEPositionHandler(portfolioService))
+		r.Get("/portfolios/{id}/transactions", portfolio.GETTransactionsHandler(portfolioService))
+		r.Post("/portfolios/{id}/transactions", portfolio.POSTTransactionHandler(portfolioService))
+		r.Post("/portfolios/{id}/transactions/import", portfolio.POSTTransactionImportHandler(portfolioService))
+		r.Delete("/portfolios/{id}/transactions/{transactionID}", portfolio.DELETETransactionHandler(portfolioService))
+		r.Get("/portfolios/{id}/gains", portfolio.GETGainsHandler(portfolioService))
+
+		r.Get("/alerts", alert.GETAlertsHandler(alertService))
+		r.Post("/alerts", alert.POSTAlertHandler(alertService))
+		r.Get("/alerts/inbox", alert.GETAlertInboxHandler(alertService))
+		r.Post("/alerts/inbox/read", alert.POSTAlertInboxReadHandler(alertService))
+		r.Get("/alerts/{id}", alert.GETAlertHandler(alertService))
+		r.Put("/alerts/{id}", alert.PUTAlertHandler(alertService))
+		r.Delete("/alerts/{id}", alert.DELETEAlertHandler(alertService))
+		r.Get("/alerts/{id}/history", alert.GETAlertHistoryHandler(alertService))
+
+		r.Get("/webhooks", webhook.GETWebhooksHandler(webhookService))
+		r.Post("/webhooks", webhook.POSTWebhookHandler(webhookService))
+		r.Get("/webhooks/{id}", webhook.GETWebhookHandler(webhookService))
+		r.Put("/webhooks/{id}", webhook.PUTWebhookHandler(webhookService))
+		r.Delete("/webhooks/{id}", webhook.DELETEWebhookHandler(webhookService))
+		r.Post("/webhooks/{id}/test", webhook.POSTWebhookTestHandler(webhookService))
+		r.Get("/webhooks/{id}/deliveries", webhook.GETWebhookDeliveriesHandler(webhookService))
+		r.Post("/webhooks/{id}/deliveries/{deliveryID}/retry", webhook.POSTWebhookRedeliverHandler(webhookService))
+
+		r.Get("/coins/search", catalog.GETCoinSearchHandler(coinCatalog))
 		
 		r.Get("/schedule", updater.GETScheduleParamsHandler(updaterService))
 		r.Put("/schedule", updater.PUTScheduleParamsHandler(updaterService))
@@ -135,6 +281,11 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	grpcServer := grpcapi.NewServer(cryptoService, updaterService, streamHub)
+	if err := grpcServer.Start(); err != nil {
+		monitoring.Logger.Fatal().Err(err).Msg("Failed to start gRPC server")
+	}
+
 	go func() {
 		monitoring.Logger.Info().Str("addr", srv.Addr).Msg("Starting HTTP server")
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -153,6 +304,13 @@ func main() {
 
 	monitoring.Logger.Info().Msg("Stopping updater service...")
 	updaterService.EndUpdating()
+	coinCatalog.StopRefreshing()
+	cryptoService.StopBackfills()
+	historyMaintainer.Stop()
+	alertEvaluator.Stop()
+	webhookDispatcher.Stop()
+	streamHub.Stop()
+	grpcServer.Stop(ctx)
 
 	if err := srv.Shutdown(ctx); err != nil {
 		monitoring.Logger.Error().Err(err).Msg("Server forced to shutdown")
@@ -0,0 +1,237 @@
+package alert
+
+import (
+	"RESTCryptoServer/internal/crypto"
+	"RESTCryptoServer/internal/db"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pendingBatches bounds how many price writes may wait for evaluation. A full
+// queue drops the newest batch; the next write re-evaluates the same rules.
+const pendingBatches = 64
+
+// FireListener receives every recorded firing with its alert. It runs on the
+// evaluator goroutine.
+type FireListener func(alert db.Alert, event db.AlertEvent)
+
+// Evaluator checks the enabled alerts of every symbol whose price was written
+// and records the ones that fire. It runs on its own goroutine so price
+// writes are not slowed down by alert queries.
+type Evaluator struct {
+	alertDB       *db.AlertDB
+	cryptoService *crypto.CryptoService
+	updates       chan []crypto.PriceUpdate
+	listeners     []FireListener
+	stopChan      chan struct{}
+}
+
+func NewEvaluator(alertDB *db.AlertDB, cryptoService *crypto.CryptoService) *Evaluator {
+	return &Evaluator{
+		alertDB:       alertDB,
+		cryptoService: cryptoService,
+		updates:       make(chan []crypto.PriceUpdate, pendingBatches),
+		stopChan:      make(chan struct{}),
+	}
+}
+
+// Notify queues a batch of written prices. It is a crypto.PriceListener.
+func (e *Evaluator) Notify(updates []crypto.PriceUpdate) {
+	select {
+	case e.updates <- updates:
+	default:
+		log.Printf("Alert evaluator: queue full, skipping %d price updates", len(updates))
+	}
+}
+
+// OnFire registers listener to be called after every recorded firing.
+// Listeners are registered before Start.
+func (e *Evaluator) OnFire(listener FireListener) {
+	e.listeners = append(e.listeners, listener)
+}
+
+func (e *Evaluator) Start() {
+	log.Println("Alert evaluator started")
+
+	go func() {
+		for {
+			select {
+			case updates := <-e.updates:
+				if err := e.Evaluate(updates); err != nil {
+					log.Printf("Alert evaluation failed: %v", err)
+				}
+			case <-e.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+func (e *Evaluator) Stop() {
+	select {
+	case <-e.stopChan:
+	default:
+		close(e.stopChan)
+		log.Println("Alert evaluator stopped")
+	}
+}
+
+// rangeKey identifies the price range a change rule looks at.
+type rangeKey struct {
+	symbol   string
+	currency string
+	window   int
+}
+
+type priceRange struct {
+	low  float64
+	high float64
+	ok   bool
+}
+
+// Evaluate checks the alerts on the symbols of updates. Armed alerts whose
+// rule holds fire unless they are cooling down; disarmed alerts whose rule
+// no longer holds are armed again.
+func (e *Evaluator) Evaluate(updates []crypto.PriceUpdate) error {
+	prices := make(map[string]crypto.PriceUpdate, len(updates))
+	symbols := make([]string, 0, len(updates))
+	for _, update := range updates {
+		if _, exists := prices[update.Symbol]; !exists {
+			symbols = append(symbols, update.Symbol)
+		}
+		prices[update.Symbol] = update
+	}
+
+	alerts, err := e.alertDB.EnabledForSymbols(symbols)
+	if err != nil {
+		return err
+	}
+
+	ranges := make(map[rangeKey]priceRange)
+	var rearm []int64
+	for _, alert := range alerts {
+		update := prices[alert.Symbol]
+		price, exists := update.Prices[alert.Currency]
+		if !exists {
+			continue
+		}
+
+		message, holds, err := e.check(alert, price, update.Timestamp, ranges)
+		if err != nil {
+			log.Printf("Alert %d: failed to check rule: %v", alert.ID, err)
+			continue
+		}
+
+		if !holds {
+			if !alert.Armed {
+				rearm = append(rearm, alert.ID)
+			}
+			continue
+		}
+		if !alert.Armed {
+			continue
+		}
+		cooldown := time.Duration(alert.CooldownSeconds) * time.Second
+		if alert.LastFiredAt != nil && update.Timestamp.Sub(*alert.LastFiredAt) < cooldown {
+			continue
+		}
+
+		event, fired, err := e.alertDB.Fire(alert, db.AlertEvent{
+			Symbol:   alert.Symbol,
+			Currency: alert.Currency,
+			Price:    price,
+			Message:  message,
+			FiredAt:  update.Timestamp,
+		})
+		if err != nil {
+			log.Printf("Alert %d: failed to record firing: %v", alert.ID, err)
+			continue
+		}
+		if !fired {
+			continue
+		}
+
+		log.Printf("Alert %d fired for %s: %s", alert.ID, alert.Owner, message)
+		for _, listener := range e.listeners {
+			listener(alert, event)
+		}
+	}
+
+	if len(rearm) > 0 {
+		return e.alertDB.Rearm(rearm)
+	}
+	return nil
+}
+
+// check reports whether the rule of alert holds at price and describes it.
+func (e *Evaluator) check(alert db.Alert, price float64, at time.Time, ranges map[rangeKey]priceRange) (string, bool, error) {
+	symbol := strings.ToUpper(alert.Symbol)
+	currency := strings.ToUpper(alert.Currency)
+
+	switch alert.Type {
+	case db.AlertAbove:
+		message := fmt.Sprintf("%s is above %s %s at %s", symbol, formatPrice(alert.Threshold), currency, formatPrice(price))
+		return message, price >= alert.Threshold, nil
+
+	case db.AlertBelow:
+		message := fmt.Sprintf("%s is below %s %s at %s", symbol, formatPrice(alert.Threshold), currency, formatPrice(price))
+		return message, price <= alert.Threshold, nil
+
+	case db.AlertDeviation:
+		deviation := math.Abs(price-alert.Threshold) / alert.Threshold * 100
+		message := fmt.Sprintf("%s deviates %.2f%% from %s %s at %s", symbol, deviation, formatPrice(alert.Threshold), currency, formatPrice(price))
+		return message, deviation > alert.Percent, nil
+
+	case db.AlertChange:
+		key := rangeKey{alert.Symbol, alert.Currency, alert.WindowSeconds}
+		window, exists := ranges[key]
+		if !exists {
+			since := at.Add(-time.Duration(alert.WindowSeconds) * time.Second)
+			low, high, ok, err := e.cryptoService.PriceRange(alert.Symbol, alert.Currency, since)
+			if err != nil {
+				return "", false, err
+			}
+			window = priceRange{low: low, high: high, ok: ok}
+			ranges[key] = window
+		}
+		if !window.ok || window.low <= 0 {
+			return "", false, nil
+		}
+
+		// Moves are measured from the extreme of the window, so a drop is
+		// the fall from its high and a rise the climb from its low.
+		drop := (window.high - price) / window.high * 100
+		rise := (price - window.low) / window.low * 100
+		span := formatWindow(alert.WindowSeconds)
+
+		if alert.Direction != DirectionUp && drop >= alert.Percent {
+			return fmt.Sprintf("%s dropped %.2f%% within %s to %s %s", symbol, drop, span, formatPrice(price), currency), true, nil
+		}
+		if alert.Direction != DirectionDown && rise >= alert.Percent {
+			return fmt.Sprintf("%s rose %.2f%% within %s to %s %s", symbol, rise, span, formatPrice(price), currency), true, nil
+		}
+		return "", false, nil
+	}
+
+	return "", false, fmt.Errorf("unknown alert type %q", alert.Type)
+}
+
+func formatPrice(price float64) string {
+	return strconv.FormatFloat(price, 'f', -1, 64)
+}
+
+// formatWindow writes a window in the largest whole unit, e.g. 1h or 15m.
+func formatWindow(seconds int) string {
+	switch {
+	case seconds%3600 == 0:
+		return strconv.Itoa(seconds/3600) + "h"
+	case seconds%60 == 0:
+		return strconv.Itoa(seconds/60) + "m"
+	default:
+		return strconv.Itoa(seconds) + "s"
+	}
+}
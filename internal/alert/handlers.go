@@ -0,0 +1,270 @@
+package alert
+
+import (
+	"RESTCryptoServer/internal/auth"
+	"RESTCryptoServer/internal/crypto"
+	"RESTCryptoServer/internal/db"
+	"RESTCryptoServer/internal/provider"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type MarkReadJSON struct {
+	IDs []int64 `json:"ids"`
+}
+
+func alertID(r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	return id, err == nil && id > 0
+}
+
+// eventLimit reads the limit query parameter of the firing lists.
+func eventLimit(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return DefaultEventLimit, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > MaxEventLimit {
+		return 0, ErrInvalidLimit
+	}
+	return limit, nil
+}
+
+// writeAlertError maps the alert errors shared by every handler and reports
+// whether err was handled.
+func writeAlertError(w http.ResponseWriter, err error) bool {
+	switch err {
+	case nil:
+		return false
+	case db.ErrUnknownAlert:
+		log.Println(err)
+		http.Error(w, `Not Found`, http.StatusNotFound)
+	case db.ErrTooManyAlerts:
+		log.Println(err)
+		http.Error(w, `Bad Request - at most 100 alerts per user`, http.StatusBadRequest)
+	case ErrInvalidType, ErrInvalidThreshold, ErrInvalidPercent, ErrInvalidWindow, ErrInvalidDirection,
+		ErrInvalidCooldown, ErrInvalidNote, ErrInvalidSymbol, ErrInvalidLimit:
+		log.Println(err)
+		http.Error(w, `Bad Request - `+err.Error(), http.StatusBadRequest)
+	case crypto.ErrUnsupportedCurrency:
+		log.Println(err)
+		http.Error(w, `Bad Request - unsupported currency`, http.StatusBadRequest)
+	case crypto.ErrUnknownCoinID, crypto.ErrSymbolMismatch:
+		log.Println(err)
+		http.Error(w, `Bad Request`, http.StatusBadRequest)
+	default:
+		if errors.Is(err, provider.ErrCoinNotFound) {
+			log.Println(err)
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return true
+		}
+		return provider.WriteHTTPError(w, err)
+	}
+	return true
+}
+
+func GETAlertsHandler(as *AlertService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		alerts, err := as.List(auth.Username(r.Context()))
+		if err != nil {
+			log.Println("error during listing alerts: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(alerts)
+	}
+}
+
+func POSTAlertHandler(as *AlertService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var alertJSON AlertJSON
+		if err := json.NewDecoder(r.Body).Decode(&alertJSON); err != nil {
+			log.Println("Error during JSON parsing: ", err)
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		alert, err := as.Create(r.Context(), auth.Username(r.Context()), alertJSON)
+		if writeAlertError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during creating alert: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		json.NewEncoder(w).Encode(alert)
+	}
+}
+
+func GETAlertHandler(as *AlertService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := alertID(r)
+		if !ok {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		alert, err := as.Get(auth.Username(r.Context()), id)
+		if writeAlertError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during getting alert: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(alert)
+	}
+}
+
+func PUTAlertHandler(as *AlertService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := alertID(r)
+		if !ok {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		var alertJSON AlertJSON
+		if err := json.NewDecoder(r.Body).Decode(&alertJSON); err != nil {
+			log.Println("Error during JSON parsing: ", err)
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		alert, err := as.Update(r.Context(), auth.Username(r.Context()), id, alertJSON)
+		if writeAlertError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during updating alert: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(alert)
+	}
+}
+
+func DELETEAlertHandler(as *AlertService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := alertID(r)
+		if !ok {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		err := as.Delete(auth.Username(r.Context()), id)
+		if writeAlertError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during deleting alert: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	}
+}
+
+func GETAlertHistoryHandler(as *AlertService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := alertID(r)
+		if !ok {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		limit, err := eventLimit(r)
+		if writeAlertError(w, err) {
+			return
+		}
+
+		events, err := as.History(auth.Username(r.Context()), id, limit)
+		if writeAlertError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during getting alert history: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(events)
+	}
+}
+
+func GETAlertInboxHandler(as *AlertService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, err := eventLimit(r)
+		if writeAlertError(w, err) {
+			return
+		}
+		unread := r.URL.Query().Get("unread") == "true"
+
+		events, err := as.Inbox(auth.Username(r.Context()), unread, limit)
+		if err != nil {
+			log.Println("error during getting alert inbox: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(events)
+	}
+}
+
+func POSTAlertInboxReadHandler(as *AlertService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var markJSON MarkReadJSON
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&markJSON); err != nil {
+				log.Println("Error during JSON parsing: ", err)
+				http.Error(w, `Bad Request`, http.StatusBadRequest)
+				return
+			}
+		}
+
+		marked, err := as.MarkRead(auth.Username(r.Context()), markJSON.IDs)
+		if err != nil {
+			log.Println("error during marking alert events read: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(map[string]int64{"marked": marked})
+	}
+}
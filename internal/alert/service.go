@@ -0,0 +1,231 @@
+package alert
+
+import (
+	"RESTCryptoServer/internal/crypto"
+	"RESTCryptoServer/internal/db"
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	MaxAlertsPerUser       = 100
+	DefaultCooldownSeconds = 3600
+	DefaultEventLimit      = 50
+	MaxEventLimit          = 500
+
+	minWindowSeconds = 60
+	maxWindowSeconds = 24 * 60 * 60
+	maxCooldown      = 7 * 24 * 60 * 60
+	maxNoteLength    = 200
+)
+
+const (
+	DirectionUp   = "up"
+	DirectionDown = "down"
+	DirectionAny  = "any"
+)
+
+var (
+	ErrInvalidType      = errors.New("type must be above, below, change or deviation")
+	ErrInvalidThreshold = errors.New("threshold must be a positive price")
+	ErrInvalidPercent   = errors.New("percent must be between 0 and 1000")
+	ErrInvalidWindow    = errors.New("window_seconds must be between 60 and 86400")
+	ErrInvalidDirection = errors.New("direction must be up, down or any")
+	ErrInvalidCooldown  = errors.New("cooldown_seconds must be between 0 and 604800")
+	ErrInvalidNote      = errors.New("note must be at most 200 characters")
+	ErrInvalidSymbol    = errors.New("symbol is required")
+	ErrInvalidLimit     = errors.New("limit must be between 1 and 500")
+)
+
+var alertTypes = []string{db.AlertAbove, db.AlertBelow, db.AlertChange, db.AlertDeviation}
+
+// AlertJSON is an alert rule as entered. Fields a rule type does not use must
+// be left out. CooldownSeconds and Enabled default to an hour and true.
+type AlertJSON struct {
+	Symbol          string  `json:"symbol"`
+	CoinID          string  `json:"coin_id"`
+	Currency        string  `json:"currency"`
+	Type            string  `json:"type"`
+	Threshold       float64 `json:"threshold"`
+	Percent         float64 `json:"percent"`
+	WindowSeconds   int     `json:"window_seconds"`
+	Direction       string  `json:"direction"`
+	CooldownSeconds *int    `json:"cooldown_seconds"`
+	Note            string  `json:"note"`
+	Enabled         *bool   `json:"enabled"`
+}
+
+type AlertService struct {
+	alertDB       *db.AlertDB
+	cryptoService *crypto.CryptoService
+}
+
+func NewAlertService(alertDB *db.AlertDB, cryptoService *crypto.CryptoService) *AlertService {
+	return &AlertService{
+		alertDB:       alertDB,
+		cryptoService: cryptoService,
+	}
+}
+
+func validPositive(value float64) bool {
+	return value > 0 && !math.IsInf(value, 0)
+}
+
+// validAlert checks a rule and turns it into an alert of owner.
+func (as *AlertService) validAlert(owner string, input AlertJSON) (db.Alert, error) {
+	alert := db.Alert{
+		Owner:           owner,
+		Symbol:          strings.ToLower(strings.TrimSpace(input.Symbol)),
+		Currency:        strings.ToLower(strings.TrimSpace(input.Currency)),
+		Type:            strings.ToLower(strings.TrimSpace(input.Type)),
+		Threshold:       input.Threshold,
+		Percent:         input.Percent,
+		WindowSeconds:   input.WindowSeconds,
+		Direction:       strings.ToLower(strings.TrimSpace(input.Direction)),
+		CooldownSeconds: DefaultCooldownSeconds,
+		Note:            strings.TrimSpace(input.Note),
+		Enabled:         true,
+	}
+	if input.CooldownSeconds != nil {
+		alert.CooldownSeconds = *input.CooldownSeconds
+	}
+	if input.Enabled != nil {
+		alert.Enabled = *input.Enabled
+	}
+
+	if alert.Symbol == "" {
+		return db.Alert{}, ErrInvalidSymbol
+	}
+
+	currencies := as.cryptoService.QuoteCurrencies()
+	if alert.Currency == "" {
+		alert.Currency = currencies[0]
+	}
+	if !slices.Contains(currencies, alert.Currency) {
+		return db.Alert{}, crypto.ErrUnsupportedCurrency
+	}
+
+	if !slices.Contains(alertTypes, alert.Type) {
+		return db.Alert{}, ErrInvalidType
+	}
+
+	usesThreshold := alert.Type != db.AlertChange
+	usesPercent := alert.Type == db.AlertChange || alert.Type == db.AlertDeviation
+	if usesThreshold != validPositive(alert.Threshold) {
+		return db.Alert{}, ErrInvalidThreshold
+	}
+	if usesPercent != (validPositive(alert.Percent) && alert.Percent <= 1000) {
+		return db.Alert{}, ErrInvalidPercent
+	}
+
+	if alert.Type == db.AlertChange {
+		if alert.WindowSeconds < minWindowSeconds || alert.WindowSeconds > maxWindowSeconds {
+			return db.Alert{}, ErrInvalidWindow
+		}
+		if alert.Direction == "" {
+			alert.Direction = DirectionAny
+		}
+		if alert.Direction != DirectionUp && alert.Direction != DirectionDown && alert.Direction != DirectionAny {
+			return db.Alert{}, ErrInvalidDirection
+		}
+	} else {
+		if alert.WindowSeconds != 0 {
+			return db.Alert{}, ErrInvalidWindow
+		}
+		if alert.Direction != "" {
+			return db.Alert{}, ErrInvalidDirection
+		}
+	}
+
+	if alert.CooldownSeconds < 0 || alert.CooldownSeconds > maxCooldown {
+		return db.Alert{}, ErrInvalidCooldown
+	}
+	if utf8.RuneCountInString(alert.Note) > maxNoteLength {
+		return db.Alert{}, ErrInvalidNote
+	}
+
+	return alert, nil
+}
+
+func (as *AlertService) List(owner string) ([]db.Alert, error) {
+	return as.alertDB.List(owner)
+}
+
+func (as *AlertService) Get(owner string, id int64) (*db.Alert, error) {
+	alert, err := as.alertDB.Get(owner, id)
+	if err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// Create stores an armed alert, starting to track its symbol first if needed.
+func (as *AlertService) Create(ctx context.Context, owner string, input AlertJSON) (*db.Alert, error) {
+	alert, err := as.validAlert(owner, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := as.cryptoService.EnsureTracked(ctx, alert.Symbol, input.CoinID); err != nil {
+		return nil, err
+	}
+
+	alert, err = as.alertDB.Create(alert, MaxAlertsPerUser)
+	if err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// Update replaces the rule of an alert and re-arms it. Its firing history and
+// last firing time are kept, so the cooldown still applies.
+func (as *AlertService) Update(ctx context.Context, owner string, id int64, input AlertJSON) (*db.Alert, error) {
+	alert, err := as.validAlert(owner, input)
+	if err != nil {
+		return nil, err
+	}
+	alert.ID = id
+
+	current, err := as.alertDB.Get(owner, id)
+	if err != nil {
+		return nil, err
+	}
+	if current.Symbol != alert.Symbol {
+		if err := as.cryptoService.EnsureTracked(ctx, alert.Symbol, input.CoinID); err != nil {
+			return nil, err
+		}
+	}
+
+	alert, err = as.alertDB.Update(alert)
+	if err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+func (as *AlertService) Delete(owner string, id int64) error {
+	return as.alertDB.Delete(owner, id)
+}
+
+// History returns the latest firings of one alert, newest first.
+func (as *AlertService) History(owner string, id int64, limit int) ([]db.AlertEvent, error) {
+	if _, err := as.alertDB.Get(owner, id); err != nil {
+		return nil, err
+	}
+	return as.alertDB.Events(owner, id, false, limit)
+}
+
+// Inbox returns the latest firings of every alert of owner, newest first,
+// including those of deleted alerts.
+func (as *AlertService) Inbox(owner string, unread bool, limit int) ([]db.AlertEvent, error) {
+	return as.alertDB.Events(owner, 0, unread, limit)
+}
+
+// MarkRead marks inbox entries as read, every unread one when ids is empty.
+func (as *AlertService) MarkRead(owner string, ids []int64) (int64, error) {
+	return as.alertDB.MarkRead(owner, ids)
+}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"net/http"
 	"encoding/json"
 	"log"
@@ -81,6 +82,22 @@ func LoginHandler(authService *AuthService) http.HandlerFunc {
 	}	
 }
 
+type contextKey string
+
+const usernameKey contextKey = "username"
+
+// Username returns the user authenticated by AuthMiddleware.
+func Username(ctx context.Context) string {
+	username, _ := ctx.Value(usernameKey).(string)
+	return username
+}
+
+// WithUsername returns ctx carrying the authenticated user, for servers that
+// authenticate outside AuthMiddleware.
+func WithUsername(ctx context.Context, username string) context.Context {
+	return context.WithValue(ctx, usernameKey, username)
+}
+
 func AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
@@ -97,12 +114,33 @@ func AuthMiddleware(next http.Handler) http.Handler {
 
 		tokenString := parts[1]
 
-		_, err := ValidateToken(tokenString)
+		token, err := ValidateToken(tokenString)
+		if err != nil {
+			http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+			return
+		}
+
+		username, err := UsernameFromToken(token)
 		if err != nil {
 			http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
 			return
 		}
 
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(w, r.WithContext(WithUsername(r.Context(), username)))
 	})
-}
\ No newline at end of file
+}
+
+// StreamAuthMiddleware is AuthMiddleware for streaming routes. Browsers cannot
+// set headers on WebSocket and EventSource requests, so the token may also be
+// passed in the access_token query parameter.
+func StreamAuthMiddleware(next http.Handler) http.Handler {
+	authenticated := AuthMiddleware(next)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token := r.URL.Query().Get("access_token"); token != "" && r.Header.Get("Authorization") == "" {
+			r = r.Clone(r.Context())
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		authenticated.ServeHTTP(w, r)
+	})
+}
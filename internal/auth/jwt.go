@@ -45,4 +45,18 @@ func ValidateToken(tokenString string) (*jwt.Token, error) {
 	}
 
 	return token, nil
+}
+
+func UsernameFromToken(token *jwt.Token) (string, error) {
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", ErrInvalidToken
+	}
+
+	username, ok := claims["username"].(string)
+	if !ok || username == "" {
+		return "", ErrInvalidToken
+	}
+
+	return username, nil
 }
\ No newline at end of file
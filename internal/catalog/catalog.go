@@ -0,0 +1,204 @@
+package catalog
+
+import (
+	"RESTCryptoServer/internal/db"
+	"RESTCryptoServer/internal/provider"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CoinCatalog keeps the provider coin list in memory, indexed by ID and symbol,
+// and persists every downloaded list so the server can start without the provider.
+// It wraps the provider, so symbol resolution is served from memory while quotes
+// still go upstream.
+type CoinCatalog struct {
+	provider.PriceProvider
+
+	catalogDB       *db.CatalogDB
+	refreshInterval time.Duration
+
+	mu        sync.RWMutex
+	coins     []provider.CoinInfo
+	byID      map[string]provider.CoinInfo
+	bySymbol  map[string][]provider.CoinInfo
+	updatedAt time.Time
+
+	// refreshCtx is cancelled by StopRefreshing, aborting a running refresh.
+	refreshCtx  context.Context
+	stopRefresh context.CancelFunc
+}
+
+func NewCoinCatalog(priceProvider provider.PriceProvider, catalogDB *db.CatalogDB) *CoinCatalog {
+	interval := 24 * time.Hour
+	if hours, err := strconv.Atoi(os.Getenv("COIN_CATALOG_REFRESH_HOURS")); err == nil && hours > 0 {
+		interval = time.Duration(hours) * time.Hour
+	}
+
+	refreshCtx, stopRefresh := context.WithCancel(context.Background())
+
+	return &CoinCatalog{
+		PriceProvider:   priceProvider,
+		catalogDB:       catalogDB,
+		refreshInterval: interval,
+		byID:            make(map[string]provider.CoinInfo),
+		bySymbol:        make(map[string][]provider.CoinInfo),
+		refreshCtx:      refreshCtx,
+		stopRefresh:     stopRefresh,
+	}
+}
+
+// Load fills the catalog at startup, preferring a fresh download and falling
+// back to the last persisted snapshot.
+func (c *CoinCatalog) Load(ctx context.Context) error {
+	err := c.Refresh(ctx)
+	if err == nil {
+		return nil
+	}
+	log.Printf("Catalog: failed to download coin list from %s: %v", c.PriceProvider.Name(), err)
+
+	entries, updatedAt, dbErr := c.catalogDB.GetAll(c.PriceProvider.Name())
+	if dbErr != nil {
+		return fmt.Errorf("no coin catalog available: %w", dbErr)
+	}
+
+	coins := make([]provider.CoinInfo, len(entries))
+	for i, entry := range entries {
+		coins[i] = provider.CoinInfo{ID: entry.ID, Symbol: entry.Symbol, Name: entry.Name}
+	}
+	c.set(coins, updatedAt)
+
+	log.Printf("Catalog: loaded %d coins from snapshot taken at %s", len(coins), updatedAt.Format(time.RFC3339))
+	return nil
+}
+
+func (c *CoinCatalog) Refresh(ctx context.Context) error {
+	coins, err := c.PriceProvider.ListCoins(ctx)
+	if err != nil {
+		return err
+	}
+	if len(coins) == 0 {
+		return fmt.Errorf("%s returned an empty coin list", c.PriceProvider.Name())
+	}
+
+	updatedAt := time.Now().UTC()
+	c.set(coins, updatedAt)
+
+	entries := make([]db.CatalogEntry, len(coins))
+	for i, coin := range coins {
+		entries[i] = db.CatalogEntry{ID: coin.ID, Symbol: coin.Symbol, Name: coin.Name}
+	}
+	if err := c.catalogDB.Replace(c.PriceProvider.Name(), entries, updatedAt); err != nil {
+		log.Printf("Catalog: warning: failed to persist coin list snapshot: %v", err)
+	}
+
+	log.Printf("Catalog: refreshed %d coins from %s", len(coins), c.PriceProvider.Name())
+	return nil
+}
+
+func (c *CoinCatalog) StartRefreshing() {
+	log.Printf("Catalog: background refresh every %s", c.refreshInterval)
+
+	go func() {
+		ticker := time.NewTicker(c.refreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := c.Refresh(c.refreshCtx); err != nil {
+					log.Printf("Catalog: background refresh failed: %v", err)
+				}
+			case <-c.refreshCtx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (c *CoinCatalog) StopRefreshing() {
+	c.stopRefresh()
+}
+
+func (c *CoinCatalog) set(coins []provider.CoinInfo, updatedAt time.Time) {
+	byID := make(map[string]provider.CoinInfo, len(coins))
+	bySymbol := make(map[string][]provider.CoinInfo)
+	for _, coin := range coins {
+		byID[coin.ID] = coin
+		symbol := strings.ToLower(coin.Symbol)
+		bySymbol[symbol] = append(bySymbol[symbol], coin)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.coins = coins
+	c.byID = byID
+	c.bySymbol = bySymbol
+	c.updatedAt = updatedAt
+}
+
+func (c *CoinCatalog) snapshot(ctx context.Context) ([]provider.CoinInfo, error) {
+	c.mu.RLock()
+	coins := c.coins
+	c.mu.RUnlock()
+
+	if len(coins) > 0 {
+		return coins, nil
+	}
+
+	if err := c.Refresh(ctx); err != nil {
+		return nil, fmt.Errorf("coin catalog is empty and refresh failed: %w", err)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.coins, nil
+}
+
+func (c *CoinCatalog) GetByID(id string) (provider.CoinInfo, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	coin, exists := c.byID[id]
+	return coin, exists
+}
+
+func (c *CoinCatalog) GetBySymbol(symbol string) []provider.CoinInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.bySymbol[strings.ToLower(symbol)]
+}
+
+func (c *CoinCatalog) UpdatedAt() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.updatedAt
+}
+
+func (c *CoinCatalog) ListCoins(ctx context.Context) ([]provider.CoinInfo, error) {
+	return c.snapshot(ctx)
+}
+
+func (c *CoinCatalog) ResolveSymbol(ctx context.Context, symbol string) (provider.CoinInfo, error) {
+	coins, err := c.snapshot(ctx)
+	if err != nil {
+		return provider.CoinInfo{}, err
+	}
+	return c.PriceProvider.MatchSymbol(symbol, coins)
+}
+
+func (c *CoinCatalog) ResolveSymbols(ctx context.Context, symbols []string) (map[string]provider.CoinInfo, error) {
+	coins, err := c.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return provider.MatchSymbols(c.PriceProvider, coins, symbols), nil
+}
@@ -0,0 +1,45 @@
+package catalog
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func GETCoinSearchHandler(c *CoinCatalog) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := strings.TrimSpace(r.URL.Query().Get("q"))
+		if query == "" {
+			http.Error(w, `Bad Request - q is required`, http.StatusBadRequest)
+			return
+		}
+
+		limit := DefaultSearchLimit
+		if rawLimit := r.URL.Query().Get("limit"); rawLimit != "" {
+			parsed, err := strconv.Atoi(rawLimit)
+			if err != nil || parsed <= 0 {
+				http.Error(w, `Bad Request - invalid limit`, http.StatusBadRequest)
+				return
+			}
+			limit = parsed
+		}
+
+		results, err := c.Search(r.Context(), query, limit)
+		if err != nil {
+			log.Println("error during coin search: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		if results == nil {
+			results = []SearchResult{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(SearchResponse{Query: query, Results: results})
+	}
+}
@@ -0,0 +1,172 @@
+package catalog
+
+import (
+	"RESTCryptoServer/internal/provider"
+	"context"
+	"errors"
+	"sort"
+	"strings"
+)
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+// ErrEmptyQuery is returned for a query that is empty once trimmed, which
+// would match every coin by prefix.
+var ErrEmptyQuery = errors.New("search query is empty")
+
+type SearchResult struct {
+	ID               string `json:"id"`
+	Symbol           string `json:"symbol"`
+	Name             string `json:"name"`
+	Score            int    `json:"score"`
+	Match            string `json:"match"`
+	Popular          bool   `json:"popular"`
+	SymbolCollisions int    `json:"symbol_collisions"`
+}
+
+type SearchResponse struct {
+	Query   string         `json:"query"`
+	Results []SearchResult `json:"results"`
+}
+
+// Search ranks catalog coins against query by symbol and name, tolerating small typos.
+func (c *CoinCatalog) Search(ctx context.Context, query string, limit int) ([]SearchResult, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil, ErrEmptyQuery
+	}
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	if limit > MaxSearchLimit {
+		limit = MaxSearchLimit
+	}
+
+	coins, err := c.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []SearchResult
+	for _, coin := range coins {
+		score, match := matchScore(query, coin)
+		if score == 0 {
+			continue
+		}
+
+		popular := provider.IsPopularCoin(coin.ID)
+		if popular {
+			score += 15
+		}
+
+		results = append(results, SearchResult{
+			ID:               coin.ID,
+			Symbol:           coin.Symbol,
+			Name:             coin.Name,
+			Score:            score,
+			Match:            match,
+			Popular:          popular,
+			SymbolCollisions: len(c.GetBySymbol(coin.Symbol)),
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if len(results[i].Name) != len(results[j].Name) {
+			return len(results[i].Name) < len(results[j].Name)
+		}
+		return results[i].ID < results[j].ID
+	})
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+func matchScore(query string, coin provider.CoinInfo) (int, string) {
+	symbol := strings.ToLower(coin.Symbol)
+	name := strings.ToLower(coin.Name)
+
+	switch {
+	case symbol == query:
+		return 100, "exact_symbol"
+	case name == query:
+		return 90, "exact_name"
+	case strings.HasPrefix(symbol, query):
+		return 70, "symbol_prefix"
+	case strings.HasPrefix(name, query):
+		return 60, "name_prefix"
+	case hasWordPrefix(name, query):
+		return 50, "name_word_prefix"
+	case strings.Contains(symbol, query) || strings.Contains(name, query):
+		return 40, "contains"
+	}
+
+	maxDistance := 1
+	if len(query) >= 6 {
+		maxDistance = 2
+	}
+	if len(query) < 3 {
+		return 0, ""
+	}
+
+	best := maxDistance + 1
+	for _, candidate := range []string{symbol, name} {
+		if d := abs(len(candidate) - len(query)); d > maxDistance {
+			continue
+		}
+		if d := levenshtein(query, candidate); d < best {
+			best = d
+		}
+	}
+	if best > maxDistance {
+		return 0, ""
+	}
+	return 30 - best*5, "fuzzy"
+}
+
+func hasWordPrefix(name, query string) bool {
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool {
+		return r == ' ' || r == '-' || r == '.' || r == '(' || r == ')'
+	}) {
+		if strings.HasPrefix(word, query) {
+			return true
+		}
+	}
+	return false
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
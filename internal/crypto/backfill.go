@@ -0,0 +1,196 @@
+package crypto
+
+import (
+	"RESTCryptoServer/internal/db"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const maxBackfillDays = 365
+
+var (
+	ErrInvalidBackfillPeriod = errors.New("invalid backfill period")
+	ErrBackfillNotFound      = errors.New("no backfill job for cryptocurrency")
+)
+
+type BackfillStatus string
+
+const (
+	BackfillPending   BackfillStatus = "pending"
+	BackfillRunning   BackfillStatus = "running"
+	BackfillCompleted BackfillStatus = "completed"
+	BackfillFailed    BackfillStatus = "failed"
+)
+
+type BackfillJob struct {
+	ID             string         `json:"id"`
+	Symbol         string         `json:"symbol"`
+	CoinID         string         `json:"coin_id"`
+	Days           int            `json:"days"`
+	Status         BackfillStatus `json:"status"`
+	PointsFetched  int            `json:"points_fetched"`
+	PointsInserted int            `json:"points_inserted"`
+	Error          string         `json:"error,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+func (job *BackfillJob) done() bool {
+	return job.Status == BackfillCompleted || job.Status == BackfillFailed
+}
+
+// backfillJobs keeps the latest backfill job of every symbol.
+type backfillJobs struct {
+	mu       sync.Mutex
+	bySymbol map[string]*BackfillJob
+}
+
+func newBackfillJobs() *backfillJobs {
+	return &backfillJobs{bySymbol: make(map[string]*BackfillJob)}
+}
+
+func (b *backfillJobs) get(symbol string) (BackfillJob, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	job, exists := b.bySymbol[symbol]
+	if !exists {
+		return BackfillJob{}, false
+	}
+	return *job, true
+}
+
+func (b *backfillJobs) update(symbol string, apply func(job *BackfillJob)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if job, exists := b.bySymbol[symbol]; exists {
+		apply(job)
+		job.UpdatedAt = time.Now().UTC()
+	}
+}
+
+// ParseBackfillPeriod accepts a number of days written as "30d" or "30".
+func ParseBackfillPeriod(value string) (int, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return 0, nil
+	}
+
+	days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+	if err != nil || days <= 0 || days > maxBackfillDays {
+		return 0, ErrInvalidBackfillPeriod
+	}
+	return days, nil
+}
+
+// StartBackfill loads up to days of provider history for a tracked coin in the
+// background. While a job for the symbol is still running it is returned instead
+// of starting another one.
+func (cs *CryptoService) StartBackfill(ctx context.Context, symbol string, days int) (*BackfillJob, error) {
+	symbol = strings.ToLower(symbol)
+	if days <= 0 || days > maxBackfillDays {
+		return nil, ErrInvalidBackfillPeriod
+	}
+
+	coinData, err := cs.cryptoDB.Get(symbol)
+	if err != nil {
+		if err == db.ErrUnknownCoin {
+			return nil, ErrCryptoNotFound
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	coin, err := cs.storedCoin(ctx, symbol, coinData)
+	if err != nil {
+		return nil, err
+	}
+
+	cs.backfills.mu.Lock()
+	if job, exists := cs.backfills.bySymbol[symbol]; exists && !job.done() {
+		current := *job
+		cs.backfills.mu.Unlock()
+		return &current, nil
+	}
+
+	now := time.Now().UTC()
+	job := &BackfillJob{
+		ID:        fmt.Sprintf("%s-%d", symbol, now.UnixNano()),
+		Symbol:    symbol,
+		CoinID:    coin.ID,
+		Days:      days,
+		Status:    BackfillPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	cs.backfills.bySymbol[symbol] = job
+	started := *job
+	cs.backfills.mu.Unlock()
+
+	go cs.runBackfill(symbol, coin.ID, days)
+
+	return &started, nil
+}
+
+// StopBackfills cancels the running backfill jobs, which then fail.
+func (cs *CryptoService) StopBackfills() {
+	cs.stopBackfills()
+}
+
+func (cs *CryptoService) GetBackfillJob(symbol string) (*BackfillJob, error) {
+	job, exists := cs.backfills.get(strings.ToLower(symbol))
+	if !exists {
+		return nil, ErrBackfillNotFound
+	}
+	return &job, nil
+}
+
+func (cs *CryptoService) runBackfill(symbol string, coinID string, days int) {
+	cs.backfills.update(symbol, func(job *BackfillJob) { job.Status = BackfillRunning })
+
+	inserted, err := cs.backfill(cs.backfillCtx, symbol, coinID, days)
+	if err != nil {
+		log.Printf("Backfill for %s failed: %v", symbol, err)
+		cs.backfills.update(symbol, func(job *BackfillJob) {
+			job.Status = BackfillFailed
+			job.Error = err.Error()
+		})
+		return
+	}
+
+	log.Printf("Backfill for %s completed: %d entries added", symbol, inserted)
+	cs.backfills.update(symbol, func(job *BackfillJob) {
+		job.Status = BackfillCompleted
+		job.PointsInserted = inserted
+	})
+}
+
+func (cs *CryptoService) backfill(ctx context.Context, symbol string, coinID string, days int) (int, error) {
+	points, err := cs.provider.GetMarketChart(ctx, coinID, days)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get market chart from %s: %w", cs.provider.Name(), err)
+	}
+	cs.backfills.update(symbol, func(job *BackfillJob) { job.PointsFetched = len(points) })
+
+	if _, err := cs.cryptoDB.Get(symbol); err != nil {
+		return 0, fmt.Errorf("cryptocurrency removed during backfill: %w", err)
+	}
+
+	history := make([]db.HistoryPoint, 0, len(points))
+	for _, point := range points {
+		history = append(history, db.HistoryPoint{
+			Price:      point.Price,
+			Prices:     map[string]float64{baseCurrency: point.Price},
+			MarketData: db.MarketData{MarketCap: point.MarketCap, Volume24h: point.Volume24h},
+			Timestamp:  point.Timestamp,
+		})
+	}
+
+	return cs.historyDB.InsertOlder(symbol, history)
+}
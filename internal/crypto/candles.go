@@ -0,0 +1,293 @@
+package crypto
+
+import (
+	"RESTCryptoServer/internal/db"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultCandleInterval = "1h"
+	DefaultCandleCount    = 100
+	MaxCandles            = 1000
+)
+
+var (
+	ErrInvalidTimezone = errors.New("invalid time zone")
+	ErrTooManyCandles  = errors.New("time range spans too many candles")
+)
+
+type candleInterval struct {
+	width time.Duration
+	unit  string
+	base  db.Resolution
+}
+
+// candleIntervals maps the interval query values to a bucket spec. Intraday
+// buckets are aligned to the unix epoch; day and week buckets start at local
+// midnight (weeks on Monday) in the requested time zone.
+var candleIntervals = map[string]candleInterval{
+	"1m":  {time.Minute, "", db.ResolutionMinute},
+	"5m":  {5 * time.Minute, "", db.ResolutionMinute},
+	"15m": {15 * time.Minute, "", db.ResolutionMinute},
+	"30m": {30 * time.Minute, "", db.ResolutionMinute},
+	"1h":  {time.Hour, "", db.ResolutionHour},
+	"4h":  {4 * time.Hour, "", db.ResolutionHour},
+	"1d":  {24 * time.Hour, "day", db.ResolutionDay},
+	"1w":  {7 * 24 * time.Hour, "week", db.ResolutionDay},
+}
+
+type Candle struct {
+	Time  time.Time `json:"time"`
+	Open  float64   `json:"open"`
+	High  float64   `json:"high"`
+	Low   float64   `json:"low"`
+	Close float64   `json:"close"`
+	Ticks int       `json:"ticks"`
+}
+
+type CryptoCandlesResponse struct {
+	Symbol   string   `json:"symbol"`
+	Currency string   `json:"currency"`
+	Interval string   `json:"interval"`
+	Timezone string   `json:"timezone"`
+	Candles  []Candle `json:"candles"`
+}
+
+// CandleQuery selects candles in [From, To). A zero To means now and a zero
+// From means DefaultCandleCount intervals before To.
+type CandleQuery struct {
+	From     time.Time
+	To       time.Time
+	Interval string
+	Location *time.Location
+}
+
+// ParseCandleQuery reads interval, from, to and tz. Times are RFC 3339 or unix
+// seconds, tz is an IANA zone name.
+func ParseCandleQuery(values url.Values) (CandleQuery, error) {
+	query := CandleQuery{
+		Interval: strings.ToLower(values.Get("interval")),
+		Location: time.UTC,
+	}
+
+	var err error
+	if query.From, err = parseHistoryTime(values.Get("from")); err != nil {
+		return CandleQuery{}, err
+	}
+	if query.To, err = parseHistoryTime(values.Get("to")); err != nil {
+		return CandleQuery{}, err
+	}
+
+	if tz := values.Get("tz"); tz != "" {
+		query.Location, err = time.LoadLocation(tz)
+		if err != nil {
+			return CandleQuery{}, ErrInvalidTimezone
+		}
+	}
+
+	return query, nil
+}
+
+// bucketStart returns the start of the bucket containing t.
+func (ci candleInterval) bucketStart(t time.Time, loc *time.Location) time.Time {
+	if ci.unit == "" {
+		return t.Truncate(ci.width).UTC()
+	}
+
+	local := t.In(loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	if ci.unit == "week" {
+		start = start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+	}
+	return start
+}
+
+// next returns the start of the bucket following start. Calendar buckets step in
+// local days, so they stay on midnight across daylight saving changes.
+func (ci candleInterval) next(start time.Time, loc *time.Location) time.Time {
+	switch ci.unit {
+	case "day":
+		return start.In(loc).AddDate(0, 0, 1)
+	case "week":
+		return start.In(loc).AddDate(0, 0, 7)
+	default:
+		return start.Add(ci.width)
+	}
+}
+
+// back returns the start of the bucket n buckets before start.
+func (ci candleInterval) back(start time.Time, loc *time.Location, n int) time.Time {
+	switch ci.unit {
+	case "day":
+		return start.In(loc).AddDate(0, 0, -n)
+	case "week":
+		return start.In(loc).AddDate(0, 0, -7*n)
+	default:
+		return start.Add(-time.Duration(n) * ci.width)
+	}
+}
+
+// normalize validates the query and aligns the range to whole buckets.
+func (q CandleQuery) normalize() (CandleQuery, candleInterval, error) {
+	if q.Interval == "" {
+		q.Interval = DefaultCandleInterval
+	}
+	interval, exists := candleIntervals[q.Interval]
+	if !exists {
+		return q, interval, ErrInvalidInterval
+	}
+	if q.Location == nil {
+		q.Location = time.UTC
+	}
+
+	if q.To.IsZero() {
+		q.To = time.Now().UTC()
+	}
+	if q.From.IsZero() {
+		q.From = q.To.Add(-DefaultCandleCount * interval.width)
+	}
+	if !q.From.Before(q.To) {
+		return q, interval, ErrInvalidTimeRange
+	}
+	if q.To.Sub(q.From) > MaxCandles*interval.width {
+		return q, interval, ErrTooManyCandles
+	}
+
+	q.From = interval.bucketStart(q.From, q.Location)
+	if end := interval.bucketStart(q.To, q.Location); !end.Equal(q.To) {
+		q.To = interval.next(end, q.Location)
+	}
+
+	return q, interval, nil
+}
+
+// hourAligned reports whether every bucket of a normalized query starts on a
+// UTC hour. Local midnight is not on one in zones such as Asia/Kolkata.
+func (q CandleQuery) hourAligned(interval candleInterval) bool {
+	for start := q.From; !start.After(q.To); start = interval.next(start, q.Location) {
+		if _, offset := start.Zone(); offset%3600 != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// GetCryptoCandles aggregates stored history into OHLC candles, oldest first.
+// Buckets without ticks between the first candle and now are returned flat at
+// the previous close with zero ticks; buckets before the first tick are omitted.
+func (cs *CryptoService) GetCryptoCandles(symbol string, vs string, query CandleQuery) (*CryptoCandlesResponse, error) {
+	symbol = strings.ToLower(symbol)
+
+	vs, err := cs.quoteCurrency(vs)
+	if err != nil {
+		return nil, err
+	}
+
+	query, interval, err := query.normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := cs.cryptoDB.Get(symbol); err != nil {
+		if err == db.ErrUnknownCoin {
+			return nil, ErrCryptoNotFound
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	candles, err := cs.loadCandles(symbol, vs, interval, query, MaxCandles+1)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CryptoCandlesResponse{
+		Symbol:   symbol,
+		Currency: vs,
+		Interval: query.Interval,
+		Timezone: query.Location.String(),
+		Candles:  candles,
+	}, nil
+}
+
+// loadCandles reads at most limit buckets of a normalized query and fills the gaps.
+func (cs *CryptoService) loadCandles(symbol string, vs string, interval candleInterval, query CandleQuery, limit int) ([]Candle, error) {
+	spec := db.BucketSpec{Width: interval.width, Unit: interval.unit, Location: query.Location.String(), Base: interval.base}
+	if interval.unit != "" && query.Location != time.UTC {
+		// Daily rollups are cut at UTC midnight, so local days are built from
+		// hours, or from minutes where local midnight is not on a UTC hour.
+		spec.Base = db.ResolutionHour
+		if !query.hourAligned(interval) {
+			spec.Base = db.ResolutionMinute
+		}
+	}
+
+	buckets, err := cs.historyDB.Buckets(symbol, spec, query.From, query.To, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price history: %w", err)
+	}
+	return fillCandles(buckets, vs, interval, query), nil
+}
+
+// candleIn converts a USD bucket to vs. Buckets only keep the closing price in
+// each quote currency, so open, high and low are scaled by the exchange rate
+// at the close; moves of the exchange rate within the bucket are not reflected.
+func candleIn(bucket db.Rollup, vs string) (Candle, bool) {
+	candle := Candle{
+		Open:  bucket.Open,
+		High:  bucket.High,
+		Low:   bucket.Low,
+		Close: bucket.Close,
+		Ticks: bucket.Samples,
+	}
+	if vs == baseCurrency {
+		return candle, true
+	}
+
+	price, exists := bucket.Prices[vs]
+	if !exists || bucket.Close == 0 {
+		return Candle{}, false
+	}
+
+	ratio := price / bucket.Close
+	candle.Open *= ratio
+	candle.High *= ratio
+	candle.Low *= ratio
+	candle.Close = price
+	return candle, true
+}
+
+// fillCandles walks every bucket of the range, oldest first, and fills the gaps
+// between stored buckets.
+func fillCandles(buckets []db.Rollup, vs string, interval candleInterval, query CandleQuery) []Candle {
+	byStart := make(map[int64]Candle, len(buckets))
+	for _, bucket := range buckets {
+		if candle, ok := candleIn(bucket, vs); ok {
+			byStart[bucket.Bucket.Unix()] = candle
+		}
+	}
+
+	end := query.To
+	if now := time.Now(); now.Before(end) {
+		end = now
+	}
+
+	candles := []Candle{}
+	for start := query.From; start.Before(end); start = interval.next(start, query.Location) {
+		candle, exists := byStart[start.Unix()]
+		if !exists {
+			if len(candles) == 0 {
+				continue
+			}
+			last := candles[len(candles)-1].Close
+			candle = Candle{Open: last, High: last, Low: last, Close: last}
+		}
+		candle.Time = start.In(query.Location)
+
+		candles = append(candles, candle)
+	}
+	return candles
+}
@@ -0,0 +1,45 @@
+package crypto
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCandleQueryHourAligned(t *testing.T) {
+	tests := []struct {
+		zone     string
+		interval string
+		want     bool
+	}{
+		{"UTC", "1d", true},
+		{"Europe/Berlin", "1d", true},
+		{"America/New_York", "1w", true},
+		{"Asia/Kolkata", "1d", false},
+		{"Asia/Kathmandu", "1w", false},
+		{"Australia/Adelaide", "1d", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.zone+"/"+tt.interval, func(t *testing.T) {
+			loc, err := time.LoadLocation(tt.zone)
+			if err != nil {
+				t.Skipf("time zone data unavailable: %v", err)
+			}
+
+			// The range spans the March and October daylight saving changes.
+			query, interval, err := CandleQuery{
+				From:     time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+				To:       time.Date(2024, time.November, 1, 0, 0, 0, 0, time.UTC),
+				Interval: tt.interval,
+				Location: loc,
+			}.normalize()
+			if err != nil {
+				t.Fatalf("normalize: %v", err)
+			}
+
+			if got := query.hourAligned(interval); got != tt.want {
+				t.Errorf("hourAligned = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
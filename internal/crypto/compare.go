@@ -0,0 +1,185 @@
+package crypto
+
+import (
+	"RESTCryptoServer/internal/db"
+	"RESTCryptoServer/internal/indicators"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultCompareWindow = "7d"
+	MaxCompareSymbols    = 10
+)
+
+var (
+	ErrInvalidCompareWindow  = errors.New("invalid compare window")
+	ErrInvalidCompareSymbols = errors.New("compare needs 2 to 10 distinct symbols")
+)
+
+// compareWindows maps the window query values to the candle interval the
+// performance series are sampled at.
+var compareWindows = map[string]string{
+	"1h":  "1m",
+	"24h": "15m",
+	"7d":  "1h",
+	"30d": "4h",
+}
+
+type ComparedCrypto struct {
+	Symbol        string     `json:"symbol"`
+	ReturnPercent *float64   `json:"return_percent"`
+	Performance   []*float64 `json:"performance"`
+}
+
+type CryptoCompareResponse struct {
+	Currency    string                         `json:"currency"`
+	Window      string                         `json:"window"`
+	Interval    string                         `json:"interval"`
+	Time        []time.Time                    `json:"time"`
+	Cryptos     []ComparedCrypto               `json:"cryptos"`
+	Correlation map[string]map[string]*float64 `json:"correlation"`
+}
+
+// ParseCompareSymbols splits a comma separated symbol list, dropping blanks and
+// duplicates.
+func ParseCompareSymbols(value string) []string {
+	var symbols []string
+	seen := make(map[string]bool)
+	for _, symbol := range strings.Split(strings.ToLower(value), ",") {
+		symbol = strings.TrimSpace(symbol)
+		if symbol == "" || seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+		symbols = append(symbols, symbol)
+	}
+	return symbols
+}
+
+// CompareCryptos returns the performance of every symbol over the window rebased
+// to 100, aligned to a common time axis that starts once all symbols with
+// history have data, and the Pearson correlation of their per-interval returns.
+func (cs *CryptoService) CompareCryptos(symbols []string, vs string, window string) (*CryptoCompareResponse, error) {
+	vs, err := cs.quoteCurrency(vs)
+	if err != nil {
+		return nil, err
+	}
+
+	if window == "" {
+		window = DefaultCompareWindow
+	}
+	intervalName, exists := compareWindows[window]
+	if !exists {
+		return nil, ErrInvalidCompareWindow
+	}
+
+	if len(symbols) < 2 || len(symbols) > MaxCompareSymbols {
+		return nil, ErrInvalidCompareSymbols
+	}
+
+	var missing []string
+	for _, symbol := range symbols {
+		if _, err := cs.cryptoDB.Get(symbol); err != nil {
+			if err == db.ErrUnknownCoin {
+				missing = append(missing, symbol)
+				continue
+			}
+			return nil, fmt.Errorf("database error: %w", err)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrCryptoNotFound, strings.Join(missing, ", "))
+	}
+
+	to := time.Now().UTC()
+	query, interval, err := CandleQuery{
+		From:     to.Add(-statsWindows[window].duration),
+		To:       to,
+		Interval: intervalName,
+		Location: time.UTC,
+	}.normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	closesBySymbol := make(map[string]map[int64]float64, len(symbols))
+	var timeline []time.Time
+	for _, symbol := range symbols {
+		candles, err := cs.loadCandles(symbol, vs, interval, query, MaxCandles+1)
+		if err != nil {
+			return nil, err
+		}
+		if len(candles) == 0 {
+			continue
+		}
+
+		closes := make(map[int64]float64, len(candles))
+		for _, candle := range candles {
+			closes[candle.Time.Unix()] = candle.Close
+		}
+		closesBySymbol[symbol] = closes
+
+		// Candles are gap filled up to now, so the shortest series is the common axis.
+		if timeline == nil || len(candles) < len(timeline) {
+			timeline = make([]time.Time, 0, len(candles))
+			for _, candle := range candles {
+				timeline = append(timeline, candle.Time)
+			}
+		}
+	}
+
+	resp := &CryptoCompareResponse{
+		Currency:    vs,
+		Window:      window,
+		Interval:    intervalName,
+		Time:        timeline,
+		Correlation: make(map[string]map[string]*float64, len(symbols)),
+	}
+	if resp.Time == nil {
+		resp.Time = []time.Time{}
+	}
+
+	returns := make(map[string][]float64, len(symbols))
+	for _, symbol := range symbols {
+		compared := ComparedCrypto{Symbol: symbol, Performance: make([]*float64, len(timeline))}
+
+		if closes, exists := closesBySymbol[symbol]; exists {
+			aligned := make([]float64, len(timeline))
+			for i, t := range timeline {
+				aligned[i] = closes[t.Unix()]
+			}
+
+			if base := aligned[0]; base > 0 {
+				rebased := make([]float64, len(aligned))
+				for i, price := range aligned {
+					rebased[i] = price / base * 100
+					compared.Performance[i] = &rebased[i]
+				}
+				change := (aligned[len(aligned)-1]/base - 1) * 100
+				compared.ReturnPercent = &change
+			}
+			returns[symbol] = indicators.Returns(aligned)
+		}
+
+		resp.Cryptos = append(resp.Cryptos, compared)
+	}
+
+	for _, a := range symbols {
+		resp.Correlation[a] = make(map[string]*float64, len(symbols))
+		for _, b := range symbols {
+			var correlation *float64
+			if returns[a] != nil && returns[b] != nil {
+				if value := indicators.Correlation(returns[a], returns[b]); !math.IsNaN(value) {
+					correlation = &value
+				}
+			}
+			resp.Correlation[a][b] = correlation
+		}
+	}
+
+	return resp, nil
+}
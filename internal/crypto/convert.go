@@ -0,0 +1,224 @@
+package crypto
+
+import (
+	"RESTCryptoServer/internal/db"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ConversionDirect = "direct"
+	ConversionViaUSD = "usd"
+)
+
+var (
+	ErrInvalidAmount   = errors.New("invalid amount")
+	ErrUnknownAsset    = errors.New("unknown cryptocurrency or currency")
+	ErrUnsupportedPair = errors.New("conversion between two fiat currencies is not supported")
+)
+
+// loadStaleAfter reads how old a stored price may get before it is reported as
+// stale. It defaults to five minutes.
+func loadStaleAfter() time.Duration {
+	if seconds, err := strconv.Atoi(os.Getenv("PRICE_STALE_SECONDS")); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return 5 * time.Minute
+}
+
+type ConversionSource struct {
+	Symbol      string    `json:"symbol"`
+	PriceUSD    float64   `json:"price_usd"`
+	LastUpdated time.Time `json:"last_updated"`
+	AgeSeconds  int64     `json:"age_seconds"`
+	Stale       bool      `json:"stale"`
+}
+
+type ConversionResponse struct {
+	From    string             `json:"from"`
+	To      string             `json:"to"`
+	Amount  float64            `json:"amount"`
+	Result  float64            `json:"result"`
+	Rate    float64            `json:"rate"`
+	Via     string             `json:"via"`
+	Stale   bool               `json:"stale"`
+	Sources []ConversionSource `json:"sources"`
+}
+
+// ParseAmount reads a positive, finite amount. An empty value means 1.
+func ParseAmount(value string) (float64, error) {
+	if value == "" {
+		return 1, nil
+	}
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil || amount <= 0 || math.IsInf(amount, 0) || math.IsNaN(amount) {
+		return 0, ErrInvalidAmount
+	}
+	return amount, nil
+}
+
+// asset is one side of a conversion: a tracked coin, or a quote currency
+// when coin is nil.
+type asset struct {
+	symbol string
+	coin   *db.CoinData
+}
+
+func (cs *CryptoService) resolveAsset(symbol string) (asset, error) {
+	symbol = strings.ToLower(strings.TrimSpace(symbol))
+
+	coinData, err := cs.cryptoDB.Get(symbol)
+	if err == nil {
+		return asset{symbol: symbol, coin: &coinData}, nil
+	}
+	if err != db.ErrUnknownCoin {
+		return asset{}, fmt.Errorf("database error: %w", err)
+	}
+
+	if containsCurrency(cs.currencies, symbol) {
+		return asset{symbol: symbol}, nil
+	}
+	return asset{}, fmt.Errorf("%w: %s", ErrUnknownAsset, symbol)
+}
+
+// Convert converts amount of from into to using the latest stored prices. Coins
+// may be converted into each other or into a configured quote currency and back.
+// A coin's stored price in the other side is used directly when present;
+// otherwise the rate is triangulated through USD, pricing a quote currency with
+// the stored prices of another tracked coin.
+func (cs *CryptoService) Convert(from string, to string, amount float64) (*ConversionResponse, error) {
+	source, err := cs.resolveAsset(from)
+	if err != nil {
+		return nil, err
+	}
+	target, err := cs.resolveAsset(to)
+	if err != nil {
+		return nil, err
+	}
+
+	rate, via, reference, err := cs.conversionRate(source, target)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ConversionResponse{
+		From:    source.symbol,
+		To:      target.symbol,
+		Amount:  amount,
+		Result:  amount * rate,
+		Rate:    rate,
+		Via:     via,
+		Sources: []ConversionSource{},
+	}
+	sides := []asset{source}
+	if target.symbol != source.symbol {
+		sides = append(sides, target)
+	}
+	if reference != nil {
+		sides = append(sides, *reference)
+	}
+	for _, side := range sides {
+		if side.coin == nil {
+			continue
+		}
+		stale := time.Since(side.coin.LastUpdate) > cs.staleAfter
+		resp.Sources = append(resp.Sources, ConversionSource{
+			Symbol:      side.symbol,
+			PriceUSD:    side.coin.CurrentPrice,
+			LastUpdated: side.coin.LastUpdate,
+			AgeSeconds:  ageSeconds(side.coin.LastUpdate),
+			Stale:       stale,
+		})
+		resp.Stale = resp.Stale || stale
+	}
+
+	return resp, nil
+}
+
+// conversionRate returns how many units of target one unit of source is worth.
+// When one side is a quote currency the coin has no stored price in, the
+// currency is priced in USD through another tracked coin, which is returned as
+// the reference.
+func (cs *CryptoService) conversionRate(source asset, target asset) (float64, string, *asset, error) {
+	if source.symbol == target.symbol {
+		return 1, ConversionDirect, nil, nil
+	}
+	if source.coin == nil && target.coin == nil {
+		return 0, "", nil, ErrUnsupportedPair
+	}
+
+	if source.coin != nil {
+		if price, exists := priceIn(source.coin.Prices, source.coin.CurrentPrice, target.symbol); exists && price > 0 {
+			return price, ConversionDirect, nil, nil
+		}
+	}
+	if target.coin != nil {
+		if price, exists := priceIn(target.coin.Prices, target.coin.CurrentPrice, source.symbol); exists && price > 0 {
+			return 1 / price, ConversionDirect, nil, nil
+		}
+	}
+
+	if source.coin != nil && target.coin != nil {
+		if target.coin.CurrentPrice <= 0 {
+			return 0, "", nil, ErrPriceUnavailable
+		}
+		return source.coin.CurrentPrice / target.coin.CurrentPrice, ConversionViaUSD, nil, nil
+	}
+
+	coin, currency, inverse := source.coin, target.symbol, false
+	if coin == nil {
+		coin, currency, inverse = target.coin, source.symbol, true
+	}
+	perUSD, reference, err := cs.currencyPerUSD(currency)
+	if err != nil {
+		return 0, "", nil, err
+	}
+	rate := coin.CurrentPrice * perUSD
+	if rate <= 0 {
+		return 0, "", nil, ErrPriceUnavailable
+	}
+	if inverse {
+		rate = 1 / rate
+	}
+	return rate, ConversionViaUSD, reference, nil
+}
+
+// currencyPerUSD derives how many units of currency one USD is worth from the
+// most recently updated tracked coin with a price in both.
+func (cs *CryptoService) currencyPerUSD(currency string) (float64, *asset, error) {
+	coins, err := cs.cryptoDB.GetAllSlice()
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get cryptocurrencies: %w", err)
+	}
+
+	var reference *db.CoinDataWithSymbol
+	for i := range coins {
+		coin := &coins[i]
+		if coin.Prices[currency] <= 0 || coin.CurrentPrice <= 0 {
+			continue
+		}
+		if reference == nil || coin.LastUpdate.After(reference.LastUpdate) {
+			reference = coin
+		}
+	}
+	if reference == nil {
+		return 0, nil, ErrPriceUnavailable
+	}
+
+	return reference.Prices[currency] / reference.CurrentPrice, &asset{
+		symbol: reference.Symbol,
+		coin: &db.CoinData{
+			CoinID:       reference.CoinID,
+			Name:         reference.Name,
+			CurrentPrice: reference.CurrentPrice,
+			Prices:       reference.Prices,
+			MarketData:   reference.MarketData,
+			LastUpdate:   reference.LastUpdate,
+		},
+	}, nil
+}
@@ -0,0 +1,95 @@
+package crypto
+
+import (
+	"RESTCryptoServer/internal/db"
+	"errors"
+	"os"
+	"strings"
+)
+
+const baseCurrency = "usd"
+
+var (
+	ErrUnsupportedCurrency = errors.New("unsupported quote currency")
+	ErrPriceUnavailable    = errors.New("price not available in requested currency")
+)
+
+func loadQuoteCurrencies() []string {
+	currencies := []string{baseCurrency}
+	for _, currency := range strings.Split(os.Getenv("QUOTE_CURRENCIES"), ",") {
+		currency = strings.ToLower(strings.TrimSpace(currency))
+		if currency == "" || containsCurrency(currencies, currency) {
+			continue
+		}
+		currencies = append(currencies, currency)
+	}
+	return currencies
+}
+
+func containsCurrency(currencies []string, currency string) bool {
+	for _, c := range currencies {
+		if c == currency {
+			return true
+		}
+	}
+	return false
+}
+
+func (cs *CryptoService) QuoteCurrencies() []string {
+	return cs.currencies
+}
+
+func (cs *CryptoService) quoteCurrency(vs string) (string, error) {
+	vs = strings.ToLower(strings.TrimSpace(vs))
+	if vs == "" {
+		return baseCurrency, nil
+	}
+	if !containsCurrency(cs.currencies, vs) {
+		return "", ErrUnsupportedCurrency
+	}
+	return vs, nil
+}
+
+// priceIn picks the price in vs, using the dedicated USD column for the base currency.
+func priceIn(prices map[string]float64, usdPrice float64, vs string) (float64, bool) {
+	if vs == baseCurrency {
+		return usdPrice, true
+	}
+	price, exists := prices[vs]
+	return price, exists
+}
+
+// marketIn converts USD market figures to the currency of price using the
+// ratio between price and usdPrice. The percent change needs no conversion.
+func marketIn(market db.MarketData, usdPrice float64, price float64) db.MarketData {
+	if usdPrice == 0 || price == usdPrice {
+		return market
+	}
+
+	ratio := price / usdPrice
+	market.MarketCap *= ratio
+	market.Volume24h *= ratio
+	market.High24h *= ratio
+	market.Low24h *= ratio
+	return market
+}
+
+// historyIn rewrites Price of every entry to vs, dropping entries recorded
+// before that currency was configured.
+func historyIn(history []db.HistoryPoint, vs string) []db.HistoryPoint {
+	if vs == baseCurrency {
+		return history
+	}
+
+	converted := make([]db.HistoryPoint, 0, len(history))
+	for _, entry := range history {
+		price, exists := entry.Prices[vs]
+		if !exists {
+			continue
+		}
+		entry.MarketData = marketIn(entry.MarketData, entry.Price, price)
+		entry.Price = price
+		converted = append(converted, entry)
+	}
+	return converted
+}
@@ -1,7 +1,9 @@
 package crypto
 
 import (
+	"RESTCryptoServer/internal/provider"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 
@@ -10,11 +12,23 @@ import (
 
 type SymbolJSON struct {
 	Symbol string `json:"symbol"`
+	CoinID string `json:"coin_id"`
 }
 
 func GETCryptosHandler(cs *CryptoService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		cryptos, err := cs.GetAllCryptos()
+		query := r.URL.Query()
+		cryptos, err := cs.GetAllCryptos(query.Get("vs"), query.Get("sort"), query.Get("order"))
+		if err == ErrUnsupportedCurrency {
+			log.Println(err)
+			http.Error(w, `Bad Request - unsupported currency`, http.StatusBadRequest)
+			return
+		}
+		if err == ErrInvalidSort {
+			log.Println(err)
+			http.Error(w, `Bad Request - invalid sort`, http.StatusBadRequest)
+			return
+		}
 		if err != nil {
 			log.Println("error during getting all cryptos: ", err)
 			http.Error(w, `Server error`, http.StatusInternalServerError)	
@@ -39,12 +53,32 @@ func POSTCryptoHandler(cs *CryptoService) http.HandlerFunc {
 			return
 		}
 
-		resp, err := cs.AddCrypto(symbolJSON.Symbol)
+		if symbolJSON.Symbol == "" && symbolJSON.CoinID == "" {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		backfillDays, err := ParseBackfillPeriod(r.URL.Query().Get("backfill"))
+		if err != nil {
+			log.Println(err)
+			http.Error(w, `Bad Request - invalid backfill period`, http.StatusBadRequest)
+			return
+		}
+
+		resp, err := cs.AddCrypto(r.Context(), symbolJSON.Symbol, symbolJSON.CoinID, backfillDays)
 		if err == ErrNameConflict {
 			log.Println(err)
 			http.Error(w, `Name conflict`, http.StatusConflict)
 			return
 		}
+		if err == ErrUnknownCoinID || err == ErrSymbolMismatch || errors.Is(err, provider.ErrCoinNotFound) {
+			log.Println(err)
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+		if provider.WriteHTTPError(w, err) {
+			return
+		}
 		if err != nil {
 			log.Println("adding cryptocurrency error: ", err)
 			http.Error(w, `Server error`, http.StatusInternalServerError)
@@ -62,7 +96,12 @@ func GETCryptoSymbolHandler(cs *CryptoService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		symbol := chi.URLParam(r, "symbol")
 
-		resp, err := cs.GetCrypto(symbol)
+		resp, err := cs.GetCrypto(symbol, r.URL.Query().Get("vs"))
+		if err == ErrUnsupportedCurrency || err == ErrPriceUnavailable {
+			log.Println(err)
+			http.Error(w, `Bad Request - unsupported currency`, http.StatusBadRequest)
+			return
+		}
 		if err != nil {
 			log.Println("error during getting crypto from postgres: ", err)
 			http.Error(w, `Bad Request`, http.StatusBadRequest)
@@ -80,12 +119,15 @@ func PUTCryptoSymbolRefreshHandler(cs *CryptoService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		symbol := chi.URLParam(r, "symbol")
 
-		resp, err := cs.RefreshCrypto(symbol)
+		resp, err := cs.RefreshCrypto(r.Context(), symbol)
 		if err == ErrCryptoNotFound {
 			log.Println(err)
 			http.Error(w, `Bad Request`, http.StatusBadRequest)
 			return
 		}
+		if provider.WriteHTTPError(w, err) {
+			return
+		}
 		if err != nil {
 			log.Println("updating cryptocurrency error: ", err)
 			http.Error(w, `Server error`, http.StatusInternalServerError)
@@ -99,11 +141,177 @@ func PUTCryptoSymbolRefreshHandler(cs *CryptoService) http.HandlerFunc {
 	}
 }
 
+func POSTCryptoBackfillHandler(cs *CryptoService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		symbol := chi.URLParam(r, "symbol")
+
+		days, err := ParseBackfillPeriod(r.URL.Query().Get("period"))
+		if err == nil && days == 0 {
+			err = ErrInvalidBackfillPeriod
+		}
+		if err != nil {
+			log.Println(err)
+			http.Error(w, `Bad Request - invalid backfill period`, http.StatusBadRequest)
+			return
+		}
+
+		job, err := cs.StartBackfill(r.Context(), symbol, days)
+		if err == ErrCryptoNotFound {
+			log.Println(err)
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+		if provider.WriteHTTPError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("starting backfill error: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+
+		json.NewEncoder(w).Encode(job)
+	}
+}
+
+func GETCryptoBackfillHandler(cs *CryptoService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		symbol := chi.URLParam(r, "symbol")
+
+		job, err := cs.GetBackfillJob(symbol)
+		if err == ErrBackfillNotFound {
+			log.Println(err)
+			http.Error(w, `Not Found`, http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Println("error during getting backfill job: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(job)
+	}
+}
+
 func GETCryptoHistoryHandler(cs *CryptoService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		symbol := chi.URLParam(r, "symbol")
 
-		resp, _ := cs.GetCryptoHistory(symbol)
+		query, err := ParseHistoryQuery(r.URL.Query())
+		if err != nil {
+			log.Println(err)
+			http.Error(w, `Bad Request - `+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		resp, err := cs.GetCryptoHistory(symbol, r.URL.Query().Get("vs"), query)
+		if err == ErrUnsupportedCurrency {
+			log.Println(err)
+			http.Error(w, `Bad Request - unsupported currency`, http.StatusBadRequest)
+			return
+		}
+		if err == ErrInvalidTimeRange || err == ErrInvalidInterval || err == ErrInvalidLimit || err == ErrInvalidCursor {
+			log.Println(err)
+			http.Error(w, `Bad Request - `+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err == ErrCryptoNotFound {
+			log.Println(err)
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			log.Println("error during getting crypto history: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+		
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+func GETCryptoCandlesHandler(cs *CryptoService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		symbol := chi.URLParam(r, "symbol")
+
+		query, err := ParseCandleQuery(r.URL.Query())
+		if err != nil {
+			log.Println(err)
+			http.Error(w, `Bad Request - `+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		resp, err := cs.GetCryptoCandles(symbol, r.URL.Query().Get("vs"), query)
+		if err == ErrUnsupportedCurrency {
+			log.Println(err)
+			http.Error(w, `Bad Request - unsupported currency`, http.StatusBadRequest)
+			return
+		}
+		if err == ErrInvalidTimeRange || err == ErrInvalidInterval || err == ErrTooManyCandles {
+			log.Println(err)
+			http.Error(w, `Bad Request - `+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err == ErrCryptoNotFound {
+			log.Println(err)
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			log.Println("error during getting crypto candles: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+		
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+func GETCryptoIndicatorsHandler(cs *CryptoService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		symbol := chi.URLParam(r, "symbol")
+
+		query, err := ParseIndicatorQuery(r.URL.Query())
+		if err != nil {
+			log.Println(err)
+			http.Error(w, `Bad Request - `+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		resp, err := cs.GetCryptoIndicators(symbol, r.URL.Query().Get("vs"), query)
+		if err == ErrUnsupportedCurrency {
+			log.Println(err)
+			http.Error(w, `Bad Request - unsupported currency`, http.StatusBadRequest)
+			return
+		}
+		if err == ErrInvalidTimeRange || err == ErrInvalidInterval || err == ErrTooManyCandles || err == ErrInvalidIndicatorPeriod {
+			log.Println(err)
+			http.Error(w, `Bad Request - `+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err == ErrCryptoNotFound {
+			log.Println(err)
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			log.Println("error during getting crypto indicators: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
 		
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
@@ -112,11 +320,89 @@ func GETCryptoHistoryHandler(cs *CryptoService) http.HandlerFunc {
 	}
 }
 
+func GETConvertHandler(cs *CryptoService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		amount, err := ParseAmount(query.Get("amount"))
+		if err != nil || query.Get("from") == "" || query.Get("to") == "" {
+			log.Println("invalid conversion request: ", err)
+			http.Error(w, `Bad Request - from, to and a positive amount are required`, http.StatusBadRequest)
+			return
+		}
+
+		resp, err := cs.Convert(query.Get("from"), query.Get("to"), amount)
+		if errors.Is(err, ErrUnknownAsset) || err == ErrUnsupportedPair || err == ErrPriceUnavailable {
+			log.Println(err)
+			http.Error(w, `Bad Request - `+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			log.Println("error during converting: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+func GETCryptoCompareHandler(cs *CryptoService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		symbols := ParseCompareSymbols(r.URL.Query().Get("symbols"))
+
+		resp, err := cs.CompareCryptos(symbols, r.URL.Query().Get("vs"), r.URL.Query().Get("window"))
+		if err == ErrUnsupportedCurrency {
+			log.Println(err)
+			http.Error(w, `Bad Request - unsupported currency`, http.StatusBadRequest)
+			return
+		}
+		if err == ErrInvalidCompareWindow || err == ErrInvalidCompareSymbols || errors.Is(err, ErrCryptoNotFound) {
+			log.Println(err)
+			http.Error(w, `Bad Request - `+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			log.Println("error during comparing cryptos: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
 func GETCryptoStatsHandler(cs *CryptoService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		symbol := chi.URLParam(r, "symbol")
 
-		resp, _ := cs.GetCryptoStats(symbol)
+		resp, err := cs.GetCryptoStats(symbol, r.URL.Query().Get("vs"), r.URL.Query().Get("window"))
+		if err == ErrUnsupportedCurrency || err == ErrPriceUnavailable {
+			log.Println(err)
+			http.Error(w, `Bad Request - unsupported currency`, http.StatusBadRequest)
+			return
+		}
+		if err == ErrInvalidStatsWindow {
+			log.Println(err)
+			http.Error(w, `Bad Request - `+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err == ErrCryptoNotFound {
+			log.Println(err)
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			log.Println("error during getting crypto stats: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
 
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
@@ -0,0 +1,179 @@
+package crypto
+
+import (
+	"RESTCryptoServer/internal/db"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultHistoryLimit = 100
+	MaxHistoryLimit     = 1000
+)
+
+var (
+	ErrInvalidTimeRange = errors.New("invalid history time range")
+	ErrInvalidInterval  = errors.New("invalid history interval")
+	ErrInvalidLimit     = errors.New("invalid history limit")
+	ErrInvalidCursor    = errors.New("invalid history cursor")
+)
+
+type historyInterval struct {
+	width time.Duration
+	base  db.Resolution
+}
+
+// historyIntervals maps the interval query values to a bucket width and the
+// stored rollup resolution used for buckets older than the raw points.
+var historyIntervals = map[string]historyInterval{
+	"1m": {time.Minute, db.ResolutionMinute},
+	"5m": {5 * time.Minute, db.ResolutionMinute},
+	"1h": {time.Hour, db.ResolutionHour},
+	"1d": {24 * time.Hour, db.ResolutionDay},
+}
+
+const rawInterval = "raw"
+
+// HistoryQuery selects a page of history. Zero From and To leave the range open.
+type HistoryQuery struct {
+	From     time.Time
+	To       time.Time
+	Limit    int
+	Cursor   string
+	Interval string
+}
+
+// ParseHistoryQuery reads from, to, limit, cursor and interval. Times are
+// RFC 3339 or unix seconds.
+func ParseHistoryQuery(values url.Values) (HistoryQuery, error) {
+	query := HistoryQuery{
+		Limit:    DefaultHistoryLimit,
+		Cursor:   values.Get("cursor"),
+		Interval: strings.ToLower(values.Get("interval")),
+	}
+
+	var err error
+	if query.From, err = parseHistoryTime(values.Get("from")); err != nil {
+		return HistoryQuery{}, err
+	}
+	if query.To, err = parseHistoryTime(values.Get("to")); err != nil {
+		return HistoryQuery{}, err
+	}
+
+	if rawLimit := values.Get("limit"); rawLimit != "" {
+		query.Limit, err = strconv.Atoi(rawLimit)
+		if err != nil {
+			return HistoryQuery{}, ErrInvalidLimit
+		}
+	}
+
+	return query, nil
+}
+
+func parseHistoryTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, ErrInvalidTimeRange
+	}
+	return t.UTC(), nil
+}
+
+func encodeHistoryCursor(t time.Time) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(t.UnixNano(), 10)))
+}
+
+func decodeHistoryCursor(cursor string) (time.Time, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, ErrInvalidCursor
+	}
+	nanos, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil {
+		return time.Time{}, ErrInvalidCursor
+	}
+	return time.Unix(0, nanos).UTC(), nil
+}
+
+// normalize validates the query and resolves open bounds and the cursor into
+// a concrete [from, to) range.
+func (q HistoryQuery) normalize() (HistoryQuery, error) {
+	if q.Interval == "" {
+		q.Interval = rawInterval
+	}
+	interval, bucketed := historyIntervals[q.Interval]
+	if !bucketed && q.Interval != rawInterval {
+		return q, ErrInvalidInterval
+	}
+
+	if q.Limit <= 0 || q.Limit > MaxHistoryLimit {
+		return q, ErrInvalidLimit
+	}
+
+	if q.From.IsZero() {
+		q.From = time.Unix(0, 0).UTC()
+	}
+	if q.To.IsZero() {
+		q.To = time.Now().UTC().Add(time.Second)
+	}
+	if !q.From.Before(q.To) {
+		return q, ErrInvalidTimeRange
+	}
+
+	if q.Cursor != "" {
+		cursor, err := decodeHistoryCursor(q.Cursor)
+		if err != nil {
+			return q, err
+		}
+		if cursor.Before(q.To) {
+			q.To = cursor
+		}
+	}
+
+	if bucketed {
+		q.From = q.From.Truncate(interval.width)
+		if !q.To.Equal(q.To.Truncate(interval.width)) {
+			q.To = q.To.Truncate(interval.width).Add(interval.width)
+		}
+	}
+
+	return q, nil
+}
+
+// isLatest reports whether the query asks for the newest raw points only.
+func (q HistoryQuery) isLatest() bool {
+	return q.From.IsZero() && q.To.IsZero() && q.Cursor == "" && (q.Interval == "" || q.Interval == rawInterval)
+}
+
+func (cs *CryptoService) queryHistory(symbol string, query HistoryQuery) ([]db.HistoryPoint, error) {
+	if query.Interval == rawInterval {
+		return cs.historyDB.Range(symbol, query.From, query.To, query.Limit)
+	}
+
+	interval := historyIntervals[query.Interval]
+	spec := db.BucketSpec{Width: interval.width, Base: interval.base}
+	buckets, err := cs.historyDB.Buckets(symbol, spec, query.From, query.To, query.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]db.HistoryPoint, 0, len(buckets))
+	for _, bucket := range buckets {
+		points = append(points, db.HistoryPoint{
+			Price:      bucket.Close,
+			Prices:     bucket.Prices,
+			MarketData: db.MarketData{MarketCap: bucket.MarketCap, Volume24h: bucket.Volume24h},
+			Timestamp:  bucket.Bucket,
+		})
+	}
+	return points, nil
+}
@@ -0,0 +1,189 @@
+package crypto
+
+import (
+	"RESTCryptoServer/internal/db"
+	"RESTCryptoServer/internal/indicators"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultIndicatorPeriod = 14
+	MaxIndicatorPeriod     = 200
+)
+
+var (
+	ErrInvalidIndicator       = errors.New("invalid indicator type")
+	ErrInvalidIndicatorPeriod = errors.New("invalid indicator period")
+)
+
+var indicatorTypes = []string{"sma", "ema", "rsi", "macd", "bollinger"}
+
+type CryptoIndicatorsResponse struct {
+	Symbol   string                `json:"symbol"`
+	Currency string                `json:"currency"`
+	Interval string                `json:"interval"`
+	Timezone string                `json:"timezone"`
+	Period   int                   `json:"period"`
+	Time     []time.Time           `json:"time"`
+	Close    []float64             `json:"close"`
+	Series   map[string][]*float64 `json:"series"`
+}
+
+// IndicatorQuery selects the candles like CandleQuery and the indicators
+// computed over their closes.
+type IndicatorQuery struct {
+	CandleQuery
+	Types  []string
+	Period int
+}
+
+// ParseIndicatorQuery reads the candle parameters plus type (comma separated,
+// all indicators by default) and period.
+func ParseIndicatorQuery(values url.Values) (IndicatorQuery, error) {
+	candleQuery, err := ParseCandleQuery(values)
+	if err != nil {
+		return IndicatorQuery{}, err
+	}
+
+	query := IndicatorQuery{CandleQuery: candleQuery, Period: DefaultIndicatorPeriod}
+
+	if rawTypes := values.Get("type"); rawTypes != "" {
+		for _, name := range strings.Split(strings.ToLower(rawTypes), ",") {
+			name = strings.TrimSpace(name)
+			if !slices.Contains(indicatorTypes, name) {
+				return IndicatorQuery{}, ErrInvalidIndicator
+			}
+			if !slices.Contains(query.Types, name) {
+				query.Types = append(query.Types, name)
+			}
+		}
+	} else {
+		query.Types = indicatorTypes
+	}
+
+	if rawPeriod := values.Get("period"); rawPeriod != "" {
+		query.Period, err = strconv.Atoi(rawPeriod)
+		if err != nil {
+			return IndicatorQuery{}, ErrInvalidIndicatorPeriod
+		}
+	}
+
+	return query, nil
+}
+
+// warmupBuckets is how many buckets before the requested range are loaded so
+// every series is defined, and EMAs have settled, from the first returned bucket.
+func warmupBuckets(period int) int {
+	macd := 3*indicators.MACDSlow + indicators.MACDSignal
+	if 3*period > macd {
+		return 3 * period
+	}
+	return macd
+}
+
+// GetCryptoIndicators computes the requested indicators over candle closes. All
+// series are aligned to Time and hold null where an indicator is not defined.
+func (cs *CryptoService) GetCryptoIndicators(symbol string, vs string, query IndicatorQuery) (*CryptoIndicatorsResponse, error) {
+	symbol = strings.ToLower(symbol)
+
+	vs, err := cs.quoteCurrency(vs)
+	if err != nil {
+		return nil, err
+	}
+
+	if query.Period < 2 || query.Period > MaxIndicatorPeriod {
+		return nil, ErrInvalidIndicatorPeriod
+	}
+	if len(query.Types) == 0 {
+		query.Types = indicatorTypes
+	}
+
+	candleQuery, interval, err := query.CandleQuery.normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := cs.cryptoDB.Get(symbol); err != nil {
+		if err == db.ErrUnknownCoin {
+			return nil, ErrCryptoNotFound
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	warmup := warmupBuckets(query.Period)
+	loadQuery := candleQuery
+	loadQuery.From = interval.back(candleQuery.From, candleQuery.Location, warmup)
+
+	candles, err := cs.loadCandles(symbol, vs, interval, loadQuery, MaxCandles+warmup+1)
+	if err != nil {
+		return nil, err
+	}
+
+	closes := make([]float64, len(candles))
+	for i, candle := range candles {
+		closes[i] = candle.Close
+	}
+
+	series := make(map[string][]float64)
+	for _, name := range query.Types {
+		switch name {
+		case "sma":
+			series["sma"], _ = indicators.SMA(closes, query.Period)
+		case "ema":
+			series["ema"], _ = indicators.EMA(closes, query.Period)
+		case "rsi":
+			series["rsi"], _ = indicators.RSI(closes, query.Period)
+		case "macd":
+			series["macd"], series["macd_signal"], series["macd_histogram"], _ = indicators.MACD(closes,
+				indicators.MACDFast, indicators.MACDSlow, indicators.MACDSignal)
+		case "bollinger":
+			series["bollinger_middle"], series["bollinger_upper"], series["bollinger_lower"], _ = indicators.Bollinger(closes,
+				query.Period, indicators.BollingerDeviations)
+		}
+	}
+
+	first := len(candles)
+	for i, candle := range candles {
+		if !candle.Time.Before(candleQuery.From) {
+			first = i
+			break
+		}
+	}
+
+	resp := &CryptoIndicatorsResponse{
+		Symbol:   symbol,
+		Currency: vs,
+		Interval: candleQuery.Interval,
+		Timezone: candleQuery.Location.String(),
+		Period:   query.Period,
+		Time:     []time.Time{},
+		Close:    closes[first:],
+		Series:   make(map[string][]*float64, len(series)),
+	}
+	for _, candle := range candles[first:] {
+		resp.Time = append(resp.Time, candle.Time)
+	}
+	for name, values := range series {
+		resp.Series[name] = nullable(values[first:])
+	}
+
+	return resp, nil
+}
+
+// nullable turns undefined (NaN) values into nil so they encode as JSON null.
+func nullable(values []float64) []*float64 {
+	out := make([]*float64, len(values))
+	for i := range values {
+		if !math.IsNaN(values[i]) {
+			out[i] = &values[i]
+		}
+	}
+	return out
+}
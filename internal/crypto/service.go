@@ -1,27 +1,41 @@
 package crypto
 
 import (
-	"RESTCryptoServer/internal/coingecko"
 	"RESTCryptoServer/internal/db"
+	"RESTCryptoServer/internal/provider"
 	"RESTCryptoServer/internal/redis"
+	"RESTCryptoServer/monitoring"
+	"context"
 	"fmt"
 	"log"
-	"math"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 	"errors"
 )
 
+const recentHistoryLimit = 100
+
 var (
 	ErrNameConflict error = errors.New("cryptocurrency already exists")
 	ErrCryptoNotFound error = errors.New("cryptocurrency not found")
+	ErrUnknownCoinID error = errors.New("unknown coin id")
+	ErrSymbolMismatch error = errors.New("coin id does not match symbol")
 )
 
 type CryptoResponse struct {
 	Symbol       string    `json:"symbol"`
+	CoinID       string    `json:"coin_id,omitempty"`
 	Name         string    `json:"name"`
 	CurrentPrice float64   `json:"current_price"`
+	Currency     string    `json:"currency"`
+	Prices       map[string]float64 `json:"prices,omitempty"`
+	db.MarketData
 	LastUpdated  time.Time `json:"last_updated"`
+	Stale        bool      `json:"stale"`
+	AgeSeconds   int64     `json:"age_seconds"`
+	BackfillJob  *BackfillJob `json:"backfill_job,omitempty"`
 }
 
 type CryptoResponseList struct {
@@ -29,92 +43,218 @@ type CryptoResponseList struct {
 }
 
 type CryptoHistoryResponse struct {
-	Symbol  string                    `json:"symbol"`
-	History []redis.PriceHistoryEntry `json:"history"`
+	Symbol     string            `json:"symbol"`
+	Currency   string            `json:"currency"`
+	Interval   string            `json:"interval"`
+	History    []db.HistoryPoint `json:"history"`
+	NextCursor string            `json:"next_cursor,omitempty"`
 }
 
 type CryptoStats struct {
-	MinPrice           float64 `json:"min_price"`
-	MaxPrice           float64 `json:"max_price"`
-	AvgPrice           float64 `json:"avg_price"`
-	PriceChange        float64 `json:"price_change"`
-	PriceChangePercent float64 `json:"price_change_percent"`
-	RecordsCount       int     `json:"records_count"`
+	MinPrice           float64          `json:"min_price"`
+	MaxPrice           float64          `json:"max_price"`
+	AvgPrice           float64          `json:"avg_price"`
+	MedianPrice        float64          `json:"median_price"`
+	StdDev             float64          `json:"std_dev"`
+	Volatility         float64          `json:"volatility"`
+	Percentiles        PricePercentiles `json:"percentiles"`
+	PriceChange        float64          `json:"price_change"`
+	PriceChangePercent float64          `json:"price_change_percent"`
+	RecordsCount       int              `json:"records_count"`
+	FirstTimestamp     *time.Time       `json:"first_timestamp,omitempty"`
+	LastTimestamp      *time.Time       `json:"last_timestamp,omitempty"`
+}
+
+type PricePercentiles struct {
+	P5  float64 `json:"p5"`
+	P25 float64 `json:"p25"`
+	P75 float64 `json:"p75"`
+	P95 float64 `json:"p95"`
 }
 
 type CryptoStatsResponse struct {
 	Symbol       string      `json:"symbol"`
+	Currency     string      `json:"currency"`
+	Window       string      `json:"window"`
 	CurrentPrice float64     `json:"current_price"`
 	Stats        CryptoStats `json:"stats"`
 }
 
 type CryptoService struct {
 	cryptoDB    *db.CryptoDB
+	historyDB   *db.HistoryDB
 	redisClient *redis.RedisClient
+	provider    provider.PriceProvider
+	currencies  []string
+	backfills   *backfillJobs
+	staleAfter  time.Duration
+
+	// backfillCtx outlives the request that starts a backfill job and is
+	// cancelled by StopBackfills on shutdown.
+	backfillCtx   context.Context
+	stopBackfills context.CancelFunc
+
+	listenersMu sync.RWMutex
+	listeners   []PriceListener
 }
 
-func NewCryptoService(cryptoDB *db.CryptoDB, redisClient *redis.RedisClient) *CryptoService {
+func NewCryptoService(cryptoDB *db.CryptoDB, historyDB *db.HistoryDB, redisClient *redis.RedisClient, priceProvider provider.PriceProvider) *CryptoService {
+	backfillCtx, stopBackfills := context.WithCancel(context.Background())
+
 	return &CryptoService{
 		cryptoDB:    cryptoDB,
+		historyDB:   historyDB,
 		redisClient: redisClient,
+		provider:    priceProvider,
+		currencies:  loadQuoteCurrencies(),
+		backfills:   newBackfillJobs(),
+		staleAfter:  loadStaleAfter(),
+
+		backfillCtx:   backfillCtx,
+		stopBackfills: stopBackfills,
 	}
 }
 
-func (cs *CryptoService) AddCrypto(symbol string) (*CryptoResponse, error) {
+// AddCrypto starts tracking a coin. With backfillDays > 0 it also starts a
+// background job loading that many days of history.
+func (cs *CryptoService) AddCrypto(ctx context.Context, symbol string, coinID string, backfillDays int) (*CryptoResponse, error) {
 	symbol = strings.ToLower(symbol)
 
-	cnt, err := cs.redisClient.GetHistoryCount(symbol)
-	if cnt != 0 {
+	var coin provider.CoinInfo
+	if coinID != "" {
+		var err error
+		coin, err = cs.findCoinByID(ctx, coinID)
+		if err != nil {
+			return nil, err
+		}
+		if symbol == "" {
+			symbol = strings.ToLower(coin.Symbol)
+		}
+		if !strings.EqualFold(coin.Symbol, symbol) {
+			return nil, ErrSymbolMismatch
+		}
+	}
+
+	_, err := cs.cryptoDB.Get(symbol)
+	if err == nil {
 		return nil, ErrNameConflict
 	}
+	if err != db.ErrUnknownCoin {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	if coin.ID == "" {
+		coin, err = cs.provider.ResolveSymbol(ctx, symbol)
+		if err != nil {
+			return nil, fmt.Errorf("cryptocurrency with symbol %s not found on %s: %w", symbol, cs.provider.Name(), err)
+		}
+	}
+
+	resp, err := cs.updateCoinPrice(ctx, symbol, coin)
+	if err != nil || backfillDays == 0 {
+		return resp, err
+	}
+
+	job, err := cs.StartBackfill(ctx, symbol, backfillDays)
 	if err != nil {
-		return nil, fmt.Errorf("error during checking cryptocurrency: %s", err)
+		log.Printf("Warning: failed to start backfill for %s: %v", symbol, err)
+		return resp, nil
 	}
+	resp.BackfillJob = job
+	return resp, nil
+}
+
+// EnsureTracked starts tracking symbol unless it is tracked already. coinID
+// optionally picks the asset of an ambiguous symbol, as in AddCrypto.
+func (cs *CryptoService) EnsureTracked(ctx context.Context, symbol string, coinID string) error {
+	symbol = strings.ToLower(symbol)
 
-	_, err = cs.cryptoDB.Get(symbol)
+	_, err := cs.cryptoDB.Get(symbol)
 	if err == nil {
-		return nil, ErrNameConflict
+		return nil
 	}
 	if err != db.ErrUnknownCoin {
-		return nil, fmt.Errorf("database error: %w", err)
+		return fmt.Errorf("database error: %w", err)
+	}
+
+	log.Printf("Starting to track %s", symbol)
+	_, err = cs.AddCrypto(ctx, symbol, coinID, 0)
+	if err == ErrNameConflict {
+		return nil
 	}
+	return err
+}
 
-	return cs.updateCoinPrice(symbol)
+// TrackedCoinID returns the provider coin id a tracked symbol is pinned to,
+// empty for coins tracked before coin ids were stored.
+func (cs *CryptoService) TrackedCoinID(symbol string) (string, error) {
+	coinData, err := cs.cryptoDB.Get(strings.ToLower(symbol))
+	if err != nil {
+		if err == db.ErrUnknownCoin {
+			return "", ErrCryptoNotFound
+		}
+		return "", fmt.Errorf("database error: %w", err)
+	}
+	return coinData.CoinID, nil
 }
 
-func (cs *CryptoService) GetAllCryptos() (*CryptoResponseList, error) {
+func (cs *CryptoService) GetAllCryptos(vs string, sortBy string, order string) (*CryptoResponseList, error) {
+	vs, err := cs.quoteCurrency(vs)
+	if err != nil {
+		return nil, err
+	}
+
+	less, err := cryptoOrdering(sortBy, order)
+	if err != nil {
+		return nil, err
+	}
+
 	cryptos, err := cs.cryptoDB.GetAllSlice()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get cryptocurrencies: %w", err)
 	}
 	
 	response := &CryptoResponseList{
-		Cryptos: make([]CryptoResponse, len(cryptos)),
+		Cryptos: make([]CryptoResponse, 0, len(cryptos)),
 	}
 	
-	for i, crypto := range cryptos {
-		response.Cryptos[i] = CryptoResponse{
+	for _, crypto := range cryptos {
+		price, exists := priceIn(crypto.Prices, crypto.CurrentPrice, vs)
+		if !exists {
+			log.Printf("Skipping %s: no %s price stored yet", crypto.Symbol, vs)
+			continue
+		}
+
+		response.Cryptos = append(response.Cryptos, CryptoResponse{
 			Symbol:       crypto.Symbol,
+			CoinID:       crypto.CoinID,
 			Name:         crypto.Name,
-			CurrentPrice: crypto.CurrentPrice,
+			CurrentPrice: price,
+			Currency:     vs,
+			Prices:       crypto.Prices,
+			MarketData:   marketIn(crypto.MarketData, crypto.CurrentPrice, price),
 			LastUpdated:  crypto.LastUpdate,
-		}
+			AgeSeconds:   ageSeconds(crypto.LastUpdate),
+		})
+	}
+
+	if less != nil {
+		sort.SliceStable(response.Cryptos, func(i, j int) bool {
+			return less(response.Cryptos[i], response.Cryptos[j])
+		})
 	}
 	
 	return response, nil
 }
 
-func (cs *CryptoService) GetCrypto(symbol string) (*CryptoResponse, error) {
+func (cs *CryptoService) GetCrypto(symbol string, vs string) (*CryptoResponse, error) {
 	symbol = strings.ToLower(symbol)
 
-	cnt, err := cs.redisClient.GetHistoryCount(symbol)
-	if cnt == 0 {
-		return nil, ErrCryptoNotFound
-	}
+	vs, err := cs.quoteCurrency(vs)
 	if err != nil {
-		return nil, fmt.Errorf("error during checking cryptocurrency: %s", err)
+		return nil, err
 	}
-	
+
 	coinData, err := cs.cryptoDB.Get(symbol)
 	if err != nil {
 		if err == db.ErrUnknownCoin {
@@ -122,69 +262,200 @@ func (cs *CryptoService) GetCrypto(symbol string) (*CryptoResponse, error) {
 		}
 		return nil, fmt.Errorf("database error: %w", err)
 	}
+
+	price, exists := priceIn(coinData.Prices, coinData.CurrentPrice, vs)
+	if !exists {
+		return nil, ErrPriceUnavailable
+	}
 	
 	return &CryptoResponse{
 		Symbol:       symbol,
+		CoinID:       coinData.CoinID,
 		Name:         coinData.Name,
-		CurrentPrice: coinData.CurrentPrice,
+		CurrentPrice: price,
+		Currency:     vs,
+		Prices:       coinData.Prices,
+		MarketData:   marketIn(coinData.MarketData, coinData.CurrentPrice, price),
 		LastUpdated:  coinData.LastUpdate,
+		AgeSeconds:   ageSeconds(coinData.LastUpdate),
 	}, nil
 }
 
-func (cs *CryptoService) RefreshCrypto(symbol string) (*CryptoResponse, error) {
+func (cs *CryptoService) RefreshCrypto(ctx context.Context, symbol string) (*CryptoResponse, error) {
 	symbol = strings.ToLower(symbol)
-	
-	cnt, err := cs.redisClient.GetHistoryCount(symbol)
-	if cnt == 0 {
-		return nil, ErrCryptoNotFound
-	}
-	if err != nil {
-		return nil, fmt.Errorf("error during checking cryptocurrency: %s", err)
-	}
 
-	_, err = cs.cryptoDB.Get(symbol)
+	coinData, err := cs.cryptoDB.Get(symbol)
 	if err != nil {
 		if err == db.ErrUnknownCoin {
 			return nil, ErrCryptoNotFound
 		}
 		return nil, fmt.Errorf("database error: %w", err)
 	}
-	
-	return cs.updateCoinPrice(symbol)
+
+	coin, err := cs.storedCoin(ctx, symbol, coinData)
+	if err == nil {
+		var resp *CryptoResponse
+		resp, err = cs.updateCoinPrice(ctx, symbol, coin)
+		if err == nil {
+			return resp, nil
+		}
+	}
+
+	if !isUpstreamFailure(err) {
+		return nil, err
+	}
+
+	log.Printf("Serving last known price for %s: %v", symbol, err)
+	monitoring.RecordStalePrice(symbol)
+	return cs.staleResponse(symbol, coinData), nil
+}
+
+// recentHistory returns the latest points, newest first, from the durable store.
+// Redis is only consulted when Postgres cannot be reached.
+func (cs *CryptoService) recentHistory(symbol string) ([]db.HistoryPoint, error) {
+	points, err := cs.historyDB.Latest(symbol, recentHistoryLimit)
+	if err == nil {
+		return points, nil
+	}
+	log.Printf("Warning: failed to get price history from Postgres, using Redis: %v", err)
+
+	points, redisErr := cs.cachedHistory(symbol)
+	if redisErr != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// cachedHistory reads the latest points kept in Redis, newest first.
+func (cs *CryptoService) cachedHistory(symbol string) ([]db.HistoryPoint, error) {
+	entries, err := cs.redisClient.GetPriceHistory(symbol, recentHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]db.HistoryPoint, 0, len(entries))
+	for _, entry := range entries {
+		points = append(points, db.HistoryPoint{
+			Price:      entry.Price,
+			Prices:     entry.Prices,
+			MarketData: db.MarketData(entry.MarketData),
+			Timestamp:  entry.Timestamp,
+		})
+	}
+	return points, nil
+}
+
+func isUpstreamFailure(err error) bool {
+	return errors.Is(err, provider.ErrUpstreamUnavailable) || errors.Is(err, provider.ErrRateLimited)
+}
+
+func ageSeconds(lastUpdate time.Time) int64 {
+	return int64(time.Since(lastUpdate).Seconds())
 }
 
-func (cs *CryptoService) GetCryptoHistory(symbol string) (*CryptoHistoryResponse, error) {
+// staleResponse builds a response from the last stored price, preferring the
+// Redis history head when it is newer than the Postgres row.
+func (cs *CryptoService) staleResponse(symbol string, coinData db.CoinData) *CryptoResponse {
+	price := coinData.CurrentPrice
+	prices := coinData.Prices
+	market := coinData.MarketData
+	lastUpdate := coinData.LastUpdate
+
+	latest, err := cs.redisClient.GetLatestPrice(symbol)
+	if err != nil {
+		log.Printf("Warning: failed to get latest price from Redis: %v", err)
+	}
+	if latest != nil && latest.Timestamp.After(lastUpdate) {
+		price = latest.Price
+		if len(latest.Prices) > 0 {
+			prices = latest.Prices
+		}
+		if latest.MarketData != (redis.MarketData{}) {
+			market = db.MarketData(latest.MarketData)
+		}
+		lastUpdate = latest.Timestamp
+	}
+
+	return &CryptoResponse{
+		Symbol:       symbol,
+		CoinID:       coinData.CoinID,
+		Name:         coinData.Name,
+		CurrentPrice: price,
+		Currency:     baseCurrency,
+		Prices:       prices,
+		MarketData:   market,
+		LastUpdated:  lastUpdate,
+		Stale:        true,
+		AgeSeconds:   ageSeconds(lastUpdate),
+	}
+}
+
+func (cs *CryptoService) GetCryptoHistory(symbol string, vs string, query HistoryQuery) (*CryptoHistoryResponse, error) {
 	symbol = strings.ToLower(symbol)
 
-	cnt, err := cs.redisClient.GetHistoryCount(symbol)
-	if cnt == 0 {
-		return nil, ErrCryptoNotFound
+	vs, err := cs.quoteCurrency(vs)
+	if err != nil {
+		return nil, err
 	}
+
+	latest := query.isLatest()
+	query, err = query.normalize()
 	if err != nil {
-		return nil, fmt.Errorf("error during checking cryptocurrency: %s", err)
+		return nil, err
 	}
 
-	history, err := cs.redisClient.GetPriceHistory(symbol, 100)
+	if _, err := cs.cryptoDB.Get(symbol); err != nil {
+		if err == db.ErrUnknownCoin {
+			return nil, ErrCryptoNotFound
+		}
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	var history []db.HistoryPoint
+	if latest && query.Limit <= recentHistoryLimit {
+		history, err = cs.recentHistory(symbol)
+		if len(history) > query.Limit {
+			history = history[:query.Limit]
+		}
+	} else {
+		history, err = cs.queryHistory(symbol, query)
+	}
 	if err != nil {
-		log.Printf("Warning: failed to get price history from Redis: %v", err)
-		history = []redis.PriceHistoryEntry{}
+		return nil, fmt.Errorf("failed to get price history: %w", err)
 	}
-	
+
+	var nextCursor string
+	if len(history) == query.Limit {
+		nextCursor = encodeHistoryCursor(history[len(history)-1].Timestamp)
+	}
+
+	if history == nil {
+		history = []db.HistoryPoint{}
+	}
+
 	return &CryptoHistoryResponse{
-		Symbol:  symbol,
-		History: history,
+		Symbol:     symbol,
+		Currency:   vs,
+		Interval:   query.Interval,
+		History:    historyIn(history, vs),
+		NextCursor: nextCursor,
 	}, nil
 }
 
-func (cs *CryptoService) GetCryptoStats(symbol string) (*CryptoStatsResponse, error) {
+func (cs *CryptoService) GetCryptoStats(symbol string, vs string, window string) (*CryptoStatsResponse, error) {
 	symbol = strings.ToLower(symbol)
-	
-	cnt, err := cs.redisClient.GetHistoryCount(symbol)
-	if cnt == 0 {
-		return nil, ErrCryptoNotFound
-	}
+
+	vs, err := cs.quoteCurrency(vs)
 	if err != nil {
-		return nil, fmt.Errorf("error during checking cryptocurrency: %s", err)
+		return nil, err
+	}
+
+	if window == "" {
+		window = DefaultStatsWindow
+	}
+	statsWindow, exists := statsWindows[window]
+	if !exists {
+		return nil, ErrInvalidStatsWindow
 	}
 
 	coinData, err := cs.cryptoDB.Get(symbol)
@@ -195,29 +466,22 @@ func (cs *CryptoService) GetCryptoStats(symbol string) (*CryptoStatsResponse, er
 		return nil, fmt.Errorf("database error: %w", err)
 	}
 
-	history, err := cs.redisClient.GetPriceHistory(symbol, 100)
+	currentPrice, exists := priceIn(coinData.Prices, coinData.CurrentPrice, vs)
+	if !exists {
+		return nil, ErrPriceUnavailable
+	}
+
+	samples, err := cs.windowSamples(symbol, vs, statsWindow)
 	if err != nil {
-		log.Printf("Warning: failed to get price history from Redis: %v", err)
-		return &CryptoStatsResponse{
-			Symbol:       symbol,
-			CurrentPrice: coinData.CurrentPrice,
-			Stats: CryptoStats{
-				MinPrice:           coinData.CurrentPrice,
-				MaxPrice:           coinData.CurrentPrice,
-				AvgPrice:           coinData.CurrentPrice,
-				PriceChange:        0,
-				PriceChangePercent: 0,
-				RecordsCount:       1,
-			},
-		}, nil
+		log.Printf("Warning: failed to get price history: %v", err)
 	}
-	
-	stats := cs.calculateStats(history, coinData.CurrentPrice)
-	
+
 	return &CryptoStatsResponse{
 		Symbol:       symbol,
-		CurrentPrice: coinData.CurrentPrice,
-		Stats:        stats,
+		Currency:     vs,
+		Window:       window,
+		CurrentPrice: currentPrice,
+		Stats:        calculateStats(samples, currentPrice),
 	}, nil
 }
 
@@ -232,6 +496,10 @@ func (cs *CryptoService) DeleteCrypto(symbol string) error {
 		return fmt.Errorf("database error: %w", err)
 	}
 
+	if err := cs.historyDB.Delete(symbol); err != nil {
+		log.Printf("Warning: failed to delete price history: %v", err)
+	}
+
 	err = cs.redisClient.DeletePriceHistory(symbol)
 	if err != nil {
 		log.Printf("Warning: failed to delete price history from Redis: %v", err)
@@ -240,56 +508,208 @@ func (cs *CryptoService) DeleteCrypto(symbol string) error {
 	return nil
 }
 
-func (cs *CryptoService) UpdateAllCryptos() (int, error) {
+func (cs *CryptoService) UpdateAllCryptos(ctx context.Context) (int, error) {
 	cryptos, err := cs.cryptoDB.GetAllSlice()
 	if err != nil {
 		return 0, fmt.Errorf("failed to get cryptocurrencies: %w", err)
 	}
-	
-	updated := 0
+	if len(cryptos) == 0 {
+		return 0, nil
+	}
+
+	coins := make(map[string]provider.CoinInfo, len(cryptos))
+	var unresolved []string
 	for _, crypto := range cryptos {
-		_, err := cs.updateCoinPrice(crypto.Symbol)
+		if crypto.CoinID == "" {
+			unresolved = append(unresolved, crypto.Symbol)
+			continue
+		}
+		coins[crypto.Symbol] = provider.CoinInfo{ID: crypto.CoinID, Symbol: crypto.Symbol, Name: crypto.Name}
+	}
+
+	if len(unresolved) > 0 {
+		resolved, err := cs.provider.ResolveSymbols(ctx, unresolved)
 		if err != nil {
-			log.Printf("Failed to update %s: %v", crypto.Symbol, err)
+			return 0, fmt.Errorf("failed to resolve symbols on %s: %w", cs.provider.Name(), err)
+		}
+		for symbol, coin := range resolved {
+			coins[symbol] = coin
+		}
+	}
+
+	ids := make([]string, 0, len(coins))
+	seen := make(map[string]bool, len(coins))
+	for _, coin := range coins {
+		if !seen[coin.ID] {
+			seen[coin.ID] = true
+			ids = append(ids, coin.ID)
+		}
+	}
+
+	quotes, err := cs.provider.GetQuotes(ctx, ids, cs.currencies)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get prices from %s: %w", cs.provider.Name(), err)
+	}
+
+	now := time.Now().UTC()
+	updates := make([]db.CoinDataWithSymbol, 0, len(cryptos))
+	points := make(map[string]db.HistoryPoint, len(cryptos))
+	entries := make(map[string]redis.PriceHistoryEntry, len(cryptos))
+	for _, crypto := range cryptos {
+		coin, exists := coins[crypto.Symbol]
+		if !exists {
+			log.Printf("Failed to update %s: symbol not found on %s", crypto.Symbol, cs.provider.Name())
 			continue
 		}
-		updated++
+
+		quote := quotes[coin.ID]
+		price, exists := quote.Prices[baseCurrency]
+		if !exists {
+			log.Printf("Failed to update %s: USD price not available (ID: %s)", crypto.Symbol, coin.ID)
+			continue
+		}
+
+		name := coin.Name
+		if name == "" {
+			name = crypto.Name
+		}
+
+		updates = append(updates, db.CoinDataWithSymbol{
+			Symbol:       crypto.Symbol,
+			CoinID:       coin.ID,
+			Name:         name,
+			CurrentPrice: price,
+			Prices:       quote.Prices,
+			MarketData:   db.MarketData(quote.Market),
+			LastUpdate:   now,
+		})
+		points[crypto.Symbol] = db.HistoryPoint{Price: price, Prices: quote.Prices, MarketData: db.MarketData(quote.Market), Timestamp: now}
+		entries[crypto.Symbol] = redis.PriceHistoryEntry{Prices: quote.Prices, MarketData: redis.MarketData(quote.Market)}
 	}
-	
-	return updated, nil
+
+	if len(updates) == 0 {
+		return 0, nil
+	}
+
+	if err := cs.cryptoDB.InsertBatch(updates); err != nil {
+		return 0, fmt.Errorf("failed to update in database: %w", err)
+	}
+
+	if err := cs.historyDB.InsertBatch(points); err != nil {
+		log.Printf("Warning: failed to store price history: %v", err)
+	}
+
+	if err := cs.redisClient.AddPriceHistoryBatch(entries); err != nil {
+		log.Printf("Warning: failed to add to Redis history: %v", err)
+	}
+
+	notified := make([]PriceUpdate, 0, len(updates))
+	for _, update := range updates {
+		notified = append(notified, PriceUpdate{
+			Symbol:     update.Symbol,
+			Name:       update.Name,
+			Price:      update.CurrentPrice,
+			Prices:     update.Prices,
+			MarketData: update.MarketData,
+			Timestamp:  update.LastUpdate,
+		})
+	}
+	cs.notifyPriceUpdates(notified)
+
+	return len(updates), nil
 }
 
-func (cs *CryptoService) updateCoinPrice(symbol string) (*CryptoResponse, error) {
-	coins, err := coingecko.GetCoinList()
+// BackfillCoinIDs stores a provider coin ID for rows tracked before coin IDs were persisted.
+func (cs *CryptoService) BackfillCoinIDs(ctx context.Context) (int, error) {
+	cryptos, err := cs.cryptoDB.GetAllSlice()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get coin list: %w", err)
+		return 0, fmt.Errorf("failed to get cryptocurrencies: %w", err)
 	}
 
-	coinID, err := coingecko.GetIDBySymbol(symbol, coins)
+	var symbols []string
+	for _, crypto := range cryptos {
+		if crypto.CoinID == "" {
+			symbols = append(symbols, crypto.Symbol)
+		}
+	}
+	if len(symbols) == 0 {
+		return 0, nil
+	}
+
+	coins, err := cs.provider.ResolveSymbols(ctx, symbols)
 	if err != nil {
-		return nil, fmt.Errorf("cryptocurrency with symbol %s not found on CoinGecko: %w", symbol, err)
+		return 0, fmt.Errorf("failed to resolve symbols on %s: %w", cs.provider.Name(), err)
 	}
 
-	log.Printf("Using CoinGecko ID '%s' for symbol '%s'", coinID, symbol)
+	filled := 0
+	for symbol, coin := range coins {
+		if err := cs.cryptoDB.SetCoinID(symbol, coin.ID); err != nil {
+			log.Printf("Failed to store coin ID for %s: %v", symbol, err)
+			continue
+		}
+		filled++
+	}
+
+	return filled, nil
+}
 
-	priceData, err := coingecko.GetPriceByID(coinID)
+// coinIndex is implemented by providers that keep their coin list indexed by
+// ID, like the coin catalog.
+type coinIndex interface {
+	GetByID(id string) (provider.CoinInfo, bool)
+}
+
+func (cs *CryptoService) findCoinByID(ctx context.Context, coinID string) (provider.CoinInfo, error) {
+	// ListCoins also loads an empty catalog before the index is read.
+	coins, err := cs.provider.ListCoins(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get price for %s (ID: %s): %w", symbol, coinID, err)
+		return provider.CoinInfo{}, fmt.Errorf("failed to get coin list: %w", err)
 	}
 
-	price, exists := priceData["usd"]
-	if !exists {
-		return nil, fmt.Errorf("USD price not available for %s (ID: %s)", symbol, coinID)
+	if index, ok := cs.provider.(coinIndex); ok {
+		if coin, found := index.GetByID(coinID); found {
+			return coin, nil
+		}
+		return provider.CoinInfo{}, ErrUnknownCoinID
 	}
 
-	var coinName string
 	for _, coin := range coins {
 		if coin.ID == coinID {
-			coinName = coin.Name
-			break
+			return coin, nil
 		}
 	}
 
+	return provider.CoinInfo{}, ErrUnknownCoinID
+}
+
+func (cs *CryptoService) storedCoin(ctx context.Context, symbol string, coinData db.CoinData) (provider.CoinInfo, error) {
+	if coinData.CoinID != "" {
+		return provider.CoinInfo{ID: coinData.CoinID, Symbol: symbol, Name: coinData.Name}, nil
+	}
+
+	coin, err := cs.provider.ResolveSymbol(ctx, symbol)
+	if err != nil {
+		return provider.CoinInfo{}, fmt.Errorf("cryptocurrency with symbol %s not found on %s: %w", symbol, cs.provider.Name(), err)
+	}
+	return coin, nil
+}
+
+func (cs *CryptoService) updateCoinPrice(ctx context.Context, symbol string, coin provider.CoinInfo) (*CryptoResponse, error) {
+	log.Printf("Using %s ID '%s' for symbol '%s'", cs.provider.Name(), coin.ID, symbol)
+
+	quotes, err := cs.provider.GetQuotes(ctx, []string{coin.ID}, cs.currencies)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price for %s (ID: %s): %w", symbol, coin.ID, err)
+	}
+
+	quote := quotes[coin.ID]
+	prices := quote.Prices
+	price, exists := prices[baseCurrency]
+	if !exists {
+		return nil, fmt.Errorf("USD price not available for %s (ID: %s)", symbol, coin.ID)
+	}
+
+	coinName := coin.Name
 	if coinName == "" {
 		coinName = strings.ToUpper(symbol)
 	}
@@ -297,8 +717,11 @@ func (cs *CryptoService) updateCoinPrice(symbol string) (*CryptoResponse, error)
 	log.Printf("Found coin: %s (%s) - Price: $%.2f", coinName, symbol, price)
 
 	coinData := db.CoinData{
+		CoinID:       coin.ID,
 		Name:         coinName,
 		CurrentPrice: price,
+		Prices:       prices,
+		MarketData:   db.MarketData(quote.Market),
 		LastUpdate:   time.Now().UTC(),
 	}
 
@@ -307,62 +730,38 @@ func (cs *CryptoService) updateCoinPrice(symbol string) (*CryptoResponse, error)
 		return nil, fmt.Errorf("failed to update in database: %w", err)
 	}
 
-	err = cs.redisClient.AddPriceHistory(symbol, price)
+	err = cs.historyDB.Insert(symbol, db.HistoryPoint{
+		Price:      price,
+		Prices:     prices,
+		MarketData: coinData.MarketData,
+		Timestamp:  coinData.LastUpdate,
+	})
+	if err != nil {
+		log.Printf("Warning: failed to store price history for %s: %v", symbol, err)
+	}
+
+	err = cs.redisClient.AddPriceHistory(symbol, redis.PriceHistoryEntry{Prices: prices, MarketData: redis.MarketData(quote.Market)})
 	if err != nil {
 		log.Printf("Warning: failed to add to Redis history for %s: %v", symbol, err)
 	}
 
+	cs.notifyPriceUpdates([]PriceUpdate{{
+		Symbol:     symbol,
+		Name:       coinName,
+		Price:      price,
+		Prices:     prices,
+		MarketData: coinData.MarketData,
+		Timestamp:  coinData.LastUpdate,
+	}})
+
 	return &CryptoResponse{
 		Symbol:       symbol,
+		CoinID:       coin.ID,
 		Name:         coinName,
 		CurrentPrice: price,
+		Currency:     baseCurrency,
+		Prices:       prices,
+		MarketData:   coinData.MarketData,
 		LastUpdated:  coinData.LastUpdate,
 	}, nil
-}
-
-func (cs *CryptoService) calculateStats(history []redis.PriceHistoryEntry, currentPrice float64) CryptoStats {
-	if len(history) == 0 {
-		return CryptoStats{
-			MinPrice:           currentPrice,
-			MaxPrice:           currentPrice,
-			AvgPrice:           currentPrice,
-			PriceChange:        0,
-			PriceChangePercent: 0,
-			RecordsCount:       1,
-		}
-	}
-	
-	var minPrice, maxPrice, sum float64
-	minPrice = history[0].Price
-	maxPrice = history[0].Price
-	
-	for _, entry := range history {
-		if entry.Price < minPrice {
-			minPrice = entry.Price
-		}
-		if entry.Price > maxPrice {
-			maxPrice = entry.Price
-		}
-		sum += entry.Price
-	}
-	
-	avgPrice := sum / float64(len(history))
-
-	var priceChange, priceChangePercent float64
-	if len(history) > 1 {
-		oldestPrice := history[len(history)-1].Price
-		priceChange = currentPrice - oldestPrice
-		if oldestPrice > 0 {
-			priceChangePercent = (priceChange / oldestPrice) * 100
-		}
-	}
-
-	return CryptoStats{
-		MinPrice:           math.Round(minPrice*100) / 100,
-		MaxPrice:           math.Round(maxPrice*100) / 100,
-		AvgPrice:           math.Round(avgPrice*100) / 100,
-		PriceChange:        math.Round(priceChange*100) / 100,
-		PriceChangePercent: math.Round(priceChangePercent*100) / 100,
-		RecordsCount:       len(history),
-	}
 }
\ No newline at end of file
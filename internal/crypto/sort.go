@@ -0,0 +1,59 @@
+package crypto
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidSort = errors.New("invalid sort field or order")
+
+type cryptoLess func(a, b CryptoResponse) bool
+
+var cryptoSortFields = map[string]func(c CryptoResponse) float64{
+	"current_price":            func(c CryptoResponse) float64 { return c.CurrentPrice },
+	"market_cap":               func(c CryptoResponse) float64 { return c.MarketCap },
+	"volume_24h":               func(c CryptoResponse) float64 { return c.Volume24h },
+	"high_24h":                 func(c CryptoResponse) float64 { return c.High24h },
+	"low_24h":                  func(c CryptoResponse) float64 { return c.Low24h },
+	"price_change_percent_24h": func(c CryptoResponse) float64 { return c.PriceChangePercent24h },
+}
+
+// cryptoOrdering returns the comparison for GET /crypto?sort=&order=. Numeric
+// fields sort descending by default, symbol and name ascending. A nil result
+// keeps the database order (by symbol).
+func cryptoOrdering(sortBy string, order string) (cryptoLess, error) {
+	sortBy = strings.ToLower(strings.TrimSpace(sortBy))
+	order = strings.ToLower(strings.TrimSpace(order))
+
+	if order != "" && order != "asc" && order != "desc" {
+		return nil, ErrInvalidSort
+	}
+
+	var less cryptoLess
+	switch sortBy {
+	case "":
+		if order != "desc" {
+			return nil, nil
+		}
+		less = func(a, b CryptoResponse) bool { return a.Symbol < b.Symbol }
+	case "symbol":
+		less = func(a, b CryptoResponse) bool { return a.Symbol < b.Symbol }
+	case "name":
+		less = func(a, b CryptoResponse) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) }
+	default:
+		field, exists := cryptoSortFields[sortBy]
+		if !exists {
+			return nil, ErrInvalidSort
+		}
+		if order == "" {
+			order = "desc"
+		}
+		less = func(a, b CryptoResponse) bool { return field(a) < field(b) }
+	}
+
+	if order == "desc" {
+		asc := less
+		less = func(a, b CryptoResponse) bool { return asc(b, a) }
+	}
+	return less, nil
+}
@@ -0,0 +1,231 @@
+package crypto
+
+import (
+	"RESTCryptoServer/internal/db"
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	DefaultStatsWindow = "24h"
+	maxStatsPoints     = 10000
+)
+
+var ErrInvalidStatsWindow = errors.New("invalid stats window")
+
+type statsWindow struct {
+	duration time.Duration
+	bucket   time.Duration
+	base     db.Resolution
+}
+
+// statsWindows maps the window query values to the history they cover. Short
+// windows use raw points (at most 8640 a day at the fastest update interval);
+// longer ones are sampled from buckets so the work per request stays bounded.
+var statsWindows = map[string]statsWindow{
+	"1h":  {time.Hour, 0, ""},
+	"24h": {24 * time.Hour, 0, ""},
+	"7d":  {7 * 24 * time.Hour, 5 * time.Minute, db.ResolutionMinute},
+	"30d": {30 * 24 * time.Hour, time.Hour, db.ResolutionHour},
+}
+
+// statsSample is a price observation. Raw points have Low, High and Avg equal to
+// Price and a weight of 1; buckets carry their range, mean and tick count.
+type statsSample struct {
+	Price     float64
+	Low       float64
+	High      float64
+	Avg       float64
+	Weight    int
+	Timestamp time.Time
+}
+
+// windowSamples loads the samples of a window in vs, oldest first. When Postgres
+// is unavailable the points cached in Redis that fall inside the window are used.
+func (cs *CryptoService) windowSamples(symbol string, vs string, window statsWindow) ([]statsSample, error) {
+	to := time.Now().UTC()
+	from := to.Add(-window.duration)
+
+	var samples []statsSample
+	if window.bucket == 0 {
+		points, err := cs.historyDB.Range(symbol, from, to.Add(time.Second), maxStatsPoints)
+		if err != nil {
+			cached, redisErr := cs.cachedHistory(symbol)
+			if redisErr != nil {
+				return nil, err
+			}
+			points = cached
+		}
+
+		for _, point := range historyIn(points, vs) {
+			if point.Timestamp.Before(from) {
+				continue
+			}
+			samples = append(samples, statsSample{
+				Price:     point.Price,
+				Low:       point.Price,
+				High:      point.Price,
+				Avg:       point.Price,
+				Weight:    1,
+				Timestamp: point.Timestamp,
+			})
+		}
+	} else {
+		spec := db.BucketSpec{Width: window.bucket, Base: window.base}
+		buckets, err := cs.historyDB.Buckets(symbol, spec, from.Truncate(window.bucket), to, maxStatsPoints)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, bucket := range buckets {
+			candle, ok := candleIn(bucket, vs)
+			if !ok {
+				continue
+			}
+			avg := bucket.Avg
+			if bucket.Close != 0 {
+				avg *= candle.Close / bucket.Close
+			}
+			samples = append(samples, statsSample{
+				Price:     candle.Close,
+				Low:       candle.Low,
+				High:      candle.High,
+				Avg:       avg,
+				Weight:    bucket.Samples,
+				Timestamp: bucket.Bucket,
+			})
+		}
+	}
+
+	// Both queries return newest first.
+	for i, j := 0, len(samples)-1; i < j; i, j = i+1, j-1 {
+		samples[i], samples[j] = samples[j], samples[i]
+	}
+	return samples, nil
+}
+
+// calculateStats summarizes samples ordered oldest first. Values are not rounded
+// so low-priced assets keep their precision.
+func calculateStats(samples []statsSample, currentPrice float64) CryptoStats {
+	if len(samples) == 0 {
+		return CryptoStats{
+			MinPrice:    currentPrice,
+			MaxPrice:    currentPrice,
+			AvgPrice:    currentPrice,
+			MedianPrice: currentPrice,
+			Percentiles: PricePercentiles{
+				P5:  currentPrice,
+				P25: currentPrice,
+				P75: currentPrice,
+				P95: currentPrice,
+			},
+			RecordsCount: 1,
+		}
+	}
+
+	minPrice := samples[0].Low
+	maxPrice := samples[0].High
+	var weightedSum float64
+	var records int
+	prices := make([]float64, 0, len(samples))
+
+	for _, sample := range samples {
+		minPrice = math.Min(minPrice, sample.Low)
+		maxPrice = math.Max(maxPrice, sample.High)
+		weightedSum += sample.Avg * float64(sample.Weight)
+		records += sample.Weight
+		prices = append(prices, sample.Price)
+	}
+
+	avgPrice := weightedSum / float64(records)
+
+	first := samples[0]
+	last := samples[len(samples)-1]
+
+	var priceChange, priceChangePercent float64
+	if len(samples) > 1 {
+		priceChange = currentPrice - first.Price
+		if first.Price > 0 {
+			priceChangePercent = (priceChange / first.Price) * 100
+		}
+	}
+
+	sorted := append([]float64(nil), prices...)
+	sort.Float64s(sorted)
+
+	return CryptoStats{
+		MinPrice:    minPrice,
+		MaxPrice:    maxPrice,
+		AvgPrice:    avgPrice,
+		MedianPrice: percentile(sorted, 50),
+		StdDev:      stdDev(prices),
+		Volatility:  annualizedVolatility(samples),
+		Percentiles: PricePercentiles{
+			P5:  percentile(sorted, 5),
+			P25: percentile(sorted, 25),
+			P75: percentile(sorted, 75),
+			P95: percentile(sorted, 95),
+		},
+		PriceChange:        priceChange,
+		PriceChangePercent: priceChangePercent,
+		RecordsCount:       records,
+		FirstTimestamp:     &first.Timestamp,
+		LastTimestamp:      &last.Timestamp,
+	}
+}
+
+// percentile interpolates linearly between the closest ranks of sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// stdDev is the sample standard deviation.
+func stdDev(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	mean := sum / float64(len(values))
+
+	var squares float64
+	for _, value := range values {
+		squares += (value - mean) * (value - mean)
+	}
+	return math.Sqrt(squares / float64(len(values)-1))
+}
+
+// annualizedVolatility is the standard deviation of log returns between
+// consecutive samples scaled to a year by their average spacing, in percent.
+func annualizedVolatility(samples []statsSample) float64 {
+	returns := make([]float64, 0, len(samples))
+	for i := 1; i < len(samples); i++ {
+		if samples[i-1].Price <= 0 || samples[i].Price <= 0 {
+			continue
+		}
+		returns = append(returns, math.Log(samples[i].Price/samples[i-1].Price))
+	}
+	if len(returns) < 2 {
+		return 0
+	}
+
+	spacing := samples[len(samples)-1].Timestamp.Sub(samples[0].Timestamp) / time.Duration(len(samples)-1)
+	if spacing <= 0 {
+		return 0
+	}
+
+	periodsPerYear := float64(365*24*time.Hour) / float64(spacing)
+	return stdDev(returns) * math.Sqrt(periodsPerYear) * 100
+}
@@ -0,0 +1,60 @@
+package crypto
+
+import (
+	"RESTCryptoServer/internal/db"
+	"time"
+)
+
+// PriceUpdate is a price that was just stored for one coin. Price is in USD,
+// Prices holds every configured quote currency.
+type PriceUpdate struct {
+	Symbol string             `json:"symbol"`
+	Name   string             `json:"name"`
+	Price  float64            `json:"price"`
+	Prices map[string]float64 `json:"prices"`
+	db.MarketData
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// PriceListener receives the prices stored by one write. It runs on the
+// goroutine that wrote them, so listeners hand slow work off elsewhere.
+type PriceListener func(updates []PriceUpdate)
+
+// OnPriceUpdate registers listener to be called after every price write of
+// updateCoinPrice and UpdateAllCryptos. Listeners are registered at startup.
+func (cs *CryptoService) OnPriceUpdate(listener PriceListener) {
+	cs.listenersMu.Lock()
+	defer cs.listenersMu.Unlock()
+
+	cs.listeners = append(cs.listeners, listener)
+}
+
+func (cs *CryptoService) notifyPriceUpdates(updates []PriceUpdate) {
+	cs.listenersMu.RLock()
+	listeners := cs.listeners
+	cs.listenersMu.RUnlock()
+
+	for _, listener := range listeners {
+		listener(updates)
+	}
+}
+
+// PriceRange returns the lowest and highest price in vs recorded since the
+// given time. ok is false when no point in the range has a price in vs.
+func (cs *CryptoService) PriceRange(symbol string, vs string, since time.Time) (low float64, high float64, ok bool, err error) {
+	points, err := cs.historyDB.Range(symbol, since, time.Now().UTC().Add(time.Second), maxStatsPoints)
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	for _, point := range historyIn(points, vs) {
+		if !ok || point.Price < low {
+			low = point.Price
+		}
+		if !ok || point.Price > high {
+			high = point.Price
+		}
+		ok = true
+	}
+	return low, high, ok, nil
+}
@@ -0,0 +1,306 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrUnknownAlert  = errors.New("unknown alert")
+	ErrTooManyAlerts = errors.New("too many alerts")
+)
+
+const (
+	AlertAbove     = "above"
+	AlertBelow     = "below"
+	AlertChange    = "change"
+	AlertDeviation = "deviation"
+)
+
+// Alert is a price rule owned by one user. Threshold is the price level of
+// above and below rules and the reference price of deviation rules. Percent is
+// the move of change rules within WindowSeconds, or the allowed deviation.
+// An armed alert fires when its rule holds; it re-arms once the rule stops
+// holding, and fires again no sooner than CooldownSeconds after LastFiredAt.
+type Alert struct {
+	ID              int64      `json:"id"`
+	Owner           string     `json:"-"`
+	Symbol          string     `json:"symbol"`
+	Currency        string     `json:"currency"`
+	Type            string     `json:"type"`
+	Threshold       float64    `json:"threshold"`
+	Percent         float64    `json:"percent"`
+	WindowSeconds   int        `json:"window_seconds"`
+	Direction       string     `json:"direction,omitempty"`
+	CooldownSeconds int        `json:"cooldown_seconds"`
+	Note            string     `json:"note"`
+	Enabled         bool       `json:"enabled"`
+	Armed           bool       `json:"armed"`
+	LastFiredAt     *time.Time `json:"last_fired_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// AlertEvent is one firing of an alert. AlertID is null once the alert was
+// deleted; the event stays in the inbox of its owner.
+type AlertEvent struct {
+	ID       int64      `json:"id"`
+	AlertID  *int64     `json:"alert_id"`
+	Symbol   string     `json:"symbol"`
+	Currency string     `json:"currency"`
+	Price    float64    `json:"price"`
+	Message  string     `json:"message"`
+	FiredAt  time.Time  `json:"fired_at"`
+	ReadAt   *time.Time `json:"read_at"`
+}
+
+type AlertDB struct {
+	conn *sql.DB
+}
+
+func NewAlertDB() (*AlertDB, error) {
+	dsn := os.Getenv("DB_DSN")
+	if dsn == "" {
+		return nil, errors.New("DB_DSN environment variable is required")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, err
+	}
+
+	if err := runMigrations(db); err != nil {
+		return nil, err
+	}
+
+	return &AlertDB{conn: db}, nil
+}
+
+const alertSelect = `
+	SELECT id, owner, symbol, currency, type, threshold, percent, window_seconds, direction,
+	       cooldown_seconds, note, enabled, armed, last_fired_at, created_at, updated_at
+	FROM alerts
+`
+
+func scanAlert(row interface{ Scan(...any) error }) (Alert, error) {
+	var alert Alert
+	err := row.Scan(&alert.ID, &alert.Owner, &alert.Symbol, &alert.Currency, &alert.Type, &alert.Threshold,
+		&alert.Percent, &alert.WindowSeconds, &alert.Direction, &alert.CooldownSeconds, &alert.Note,
+		&alert.Enabled, &alert.Armed, &alert.LastFiredAt, &alert.CreatedAt, &alert.UpdatedAt)
+	return alert, err
+}
+
+func scanAlerts(rows *sql.Rows) ([]Alert, error) {
+	defer rows.Close()
+
+	alerts := []Alert{}
+	for rows.Next() {
+		alert, err := scanAlert(rows)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, rows.Err()
+}
+
+// Create stores a new armed alert unless the owner already has limit alerts.
+func (adb *AlertDB) Create(alert Alert, limit int) (Alert, error) {
+	tx, err := adb.conn.Begin()
+	if err != nil {
+		return Alert{}, err
+	}
+	defer tx.Rollback()
+
+	// Locking the user row serializes concurrent creates for the limit check.
+	if _, err := tx.Exec(`SELECT login FROM users WHERE login = $1 FOR UPDATE`, alert.Owner); err != nil {
+		return Alert{}, err
+	}
+
+	var count int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM alerts WHERE owner = $1`, alert.Owner).Scan(&count); err != nil {
+		return Alert{}, err
+	}
+	if count >= limit {
+		return Alert{}, ErrTooManyAlerts
+	}
+
+	alert, err = scanAlert(tx.QueryRow(`
+		INSERT INTO alerts (owner, symbol, currency, type, threshold, percent, window_seconds, direction,
+		                    cooldown_seconds, note, enabled)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, owner, symbol, currency, type, threshold, percent, window_seconds, direction,
+		          cooldown_seconds, note, enabled, armed, last_fired_at, created_at, updated_at
+	`, alert.Owner, alert.Symbol, alert.Currency, alert.Type, alert.Threshold, alert.Percent, alert.WindowSeconds,
+		alert.Direction, alert.CooldownSeconds, alert.Note, alert.Enabled))
+	if err != nil {
+		return Alert{}, err
+	}
+
+	return alert, tx.Commit()
+}
+
+func (adb *AlertDB) List(owner string) ([]Alert, error) {
+	rows, err := adb.conn.Query(alertSelect+`WHERE owner = $1 ORDER BY id`, owner)
+	if err != nil {
+		return nil, err
+	}
+	return scanAlerts(rows)
+}
+
+func (adb *AlertDB) Get(owner string, id int64) (Alert, error) {
+	alert, err := scanAlert(adb.conn.QueryRow(alertSelect+`WHERE owner = $1 AND id = $2`, owner, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Alert{}, ErrUnknownAlert
+		}
+		return Alert{}, err
+	}
+	return alert, nil
+}
+
+// Update replaces the rule of an alert and re-arms it.
+func (adb *AlertDB) Update(alert Alert) (Alert, error) {
+	alert, err := scanAlert(adb.conn.QueryRow(`
+		UPDATE alerts SET symbol = $3, currency = $4, type = $5, threshold = $6, percent = $7,
+		       window_seconds = $8, direction = $9, cooldown_seconds = $10, note = $11, enabled = $12,
+		       armed = TRUE, updated_at = NOW()
+		WHERE owner = $1 AND id = $2
+		RETURNING id, owner, symbol, currency, type, threshold, percent, window_seconds, direction,
+		          cooldown_seconds, note, enabled, armed, last_fired_at, created_at, updated_at
+	`, alert.Owner, alert.ID, alert.Symbol, alert.Currency, alert.Type, alert.Threshold, alert.Percent,
+		alert.WindowSeconds, alert.Direction, alert.CooldownSeconds, alert.Note, alert.Enabled))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Alert{}, ErrUnknownAlert
+		}
+		return Alert{}, err
+	}
+	return alert, nil
+}
+
+func (adb *AlertDB) Delete(owner string, id int64) error {
+	res, err := adb.conn.Exec(`DELETE FROM alerts WHERE owner = $1 AND id = $2`, owner, id)
+	if err != nil {
+		return err
+	}
+	return requireRow(res, ErrUnknownAlert)
+}
+
+// EnabledForSymbols returns the enabled alerts of every owner on symbols.
+func (adb *AlertDB) EnabledForSymbols(symbols []string) ([]Alert, error) {
+	rows, err := adb.conn.Query(alertSelect+`WHERE enabled AND symbol = ANY($1) ORDER BY id`, pq.Array(symbols))
+	if err != nil {
+		return nil, err
+	}
+	return scanAlerts(rows)
+}
+
+// Fire disarms an alert and records event in its owner's inbox. It reports
+// false without recording anything when the alert was disarmed, disabled or
+// deleted in the meantime.
+func (adb *AlertDB) Fire(alert Alert, event AlertEvent) (AlertEvent, bool, error) {
+	tx, err := adb.conn.Begin()
+	if err != nil {
+		return AlertEvent{}, false, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+		UPDATE alerts SET armed = FALSE, last_fired_at = $2
+		WHERE id = $1 AND armed AND enabled
+	`, alert.ID, event.FiredAt)
+	if err != nil {
+		return AlertEvent{}, false, err
+	}
+	if err := requireRow(res, ErrUnknownAlert); err != nil {
+		if err == ErrUnknownAlert {
+			return AlertEvent{}, false, nil
+		}
+		return AlertEvent{}, false, err
+	}
+
+	event.AlertID = &alert.ID
+	err = tx.QueryRow(`
+		INSERT INTO alert_events (alert_id, owner, symbol, currency, price, message, fired_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`, alert.ID, alert.Owner, event.Symbol, event.Currency, event.Price, event.Message, event.FiredAt).Scan(&event.ID)
+	if err != nil {
+		return AlertEvent{}, false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return AlertEvent{}, false, err
+	}
+	return event, true, nil
+}
+
+// Rearm arms the given alerts again.
+func (adb *AlertDB) Rearm(ids []int64) error {
+	_, err := adb.conn.Exec(`UPDATE alerts SET armed = TRUE WHERE id = ANY($1) AND NOT armed`, pq.Array(ids))
+	return err
+}
+
+// Events returns up to limit firings of an owner, newest first. A non-zero
+// alertID limits them to one alert; unread skips the ones marked read.
+func (adb *AlertDB) Events(owner string, alertID int64, unread bool, limit int) ([]AlertEvent, error) {
+	rows, err := adb.conn.Query(`
+		SELECT id, alert_id, symbol, currency, price, message, fired_at, read_at
+		FROM alert_events
+		WHERE owner = $1 AND ($2 = 0 OR alert_id = $2) AND (NOT $3 OR read_at IS NULL)
+		ORDER BY fired_at DESC, id DESC
+		LIMIT $4
+	`, owner, alertID, unread, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []AlertEvent{}
+	for rows.Next() {
+		var event AlertEvent
+		err := rows.Scan(&event.ID, &event.AlertID, &event.Symbol, &event.Currency, &event.Price,
+			&event.Message, &event.FiredAt, &event.ReadAt)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// MarkRead marks firings of an owner as read, all unread ones when ids is
+// empty, and returns how many changed.
+func (adb *AlertDB) MarkRead(owner string, ids []int64) (int64, error) {
+	if ids == nil {
+		ids = []int64{}
+	}
+	res, err := adb.conn.Exec(`
+		UPDATE alert_events SET read_at = NOW()
+		WHERE owner = $1 AND read_at IS NULL AND (cardinality($2::bigint[]) = 0 OR id = ANY($2))
+	`, owner, pq.Array(ids))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (adb *AlertDB) Close() error {
+	if adb.conn != nil {
+		return adb.conn.Close()
+	}
+	return nil
+}
+
+func (adb *AlertDB) Ping() error {
+	return adb.conn.Ping()
+}
@@ -0,0 +1,138 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var ErrEmptyCatalog = errors.New("coin catalog snapshot is empty")
+
+type CatalogEntry struct {
+	ID     string `json:"id"`
+	Symbol string `json:"symbol"`
+	Name   string `json:"name"`
+}
+
+type CatalogDB struct {
+	conn *sql.DB
+}
+
+func NewCatalogDB() (*CatalogDB, error) {
+	dsn := os.Getenv("DB_DSN")
+	if dsn == "" {
+		return nil, errors.New("DB_DSN environment variable is required")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, err
+	}
+
+	if err := runMigrations(db); err != nil {
+		return nil, err
+	}
+
+	return &CatalogDB{conn: db}, nil
+}
+
+func (cdb *CatalogDB) Replace(provider string, entries []CatalogEntry, updatedAt time.Time) error {
+	tx, err := cdb.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM coin_catalog WHERE provider = $1`, provider); err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(pq.CopyIn("coin_catalog", "provider", "id", "symbol", "name", "updated_at"))
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		if seen[entry.ID] {
+			continue
+		}
+		seen[entry.ID] = true
+
+		if _, err := stmt.Exec(provider, entry.ID, entry.Symbol, entry.Name, updatedAt); err != nil {
+			stmt.Close()
+			return err
+		}
+	}
+
+	if _, err := stmt.Exec(); err != nil {
+		stmt.Close()
+		return err
+	}
+	if err := stmt.Close(); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	log.Printf("Stored %s coin catalog snapshot with %d coins", provider, len(seen))
+	return nil
+}
+
+func (cdb *CatalogDB) GetAll(provider string) ([]CatalogEntry, time.Time, error) {
+	rows, err := cdb.conn.Query(`
+		SELECT id, symbol, name, updated_at
+		FROM coin_catalog
+		WHERE provider = $1
+	`, provider)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer rows.Close()
+
+	var entries []CatalogEntry
+	var updatedAt time.Time
+	for rows.Next() {
+		var entry CatalogEntry
+		var rowUpdatedAt time.Time
+
+		if err := rows.Scan(&entry.ID, &entry.Symbol, &entry.Name, &rowUpdatedAt); err != nil {
+			return nil, time.Time{}, err
+		}
+		if rowUpdatedAt.After(updatedAt) {
+			updatedAt = rowUpdatedAt
+		}
+
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if len(entries) == 0 {
+		return nil, time.Time{}, ErrEmptyCatalog
+	}
+
+	return entries, updatedAt, nil
+}
+
+func (cdb *CatalogDB) Close() error {
+	if cdb.conn != nil {
+		return cdb.conn.Close()
+	}
+	return nil
+}
+
+func (cdb *CatalogDB) Ping() error {
+	return cdb.conn.Ping()
+}
@@ -2,6 +2,7 @@ package db
 
 import (
     "database/sql"
+    "encoding/json"
     "errors"
     "log"
     "os"
@@ -10,16 +11,31 @@ import (
 
 var ErrUnknownCoin = errors.New("unknown coin name")
 
+// MarketData holds the 24h market figures of a coin in USD.
+type MarketData struct {
+	MarketCap             float64 `json:"market_cap"`
+	Volume24h             float64 `json:"volume_24h"`
+	High24h               float64 `json:"high_24h"`
+	Low24h                float64 `json:"low_24h"`
+	PriceChangePercent24h float64 `json:"price_change_percent_24h"`
+}
+
 type CoinData struct {
+	CoinID string `json:"coin_id"`
 	Name string `json:"name"`
 	CurrentPrice float64 `json:"current_price"`
+	Prices map[string]float64 `json:"prices"`
+	MarketData
 	LastUpdate time.Time `json:"last_updated"`
 }
 
 type CoinDataWithSymbol struct {
 	Symbol       string    `json:"symbol"`
+	CoinID       string    `json:"coin_id"`
 	Name         string    `json:"name"`
 	CurrentPrice float64   `json:"current_price"`
+	Prices       map[string]float64 `json:"prices"`
+	MarketData
 	LastUpdate   time.Time `json:"last_updated"`
 }
 
@@ -49,15 +65,40 @@ func NewCryptoDB() (*CryptoDB, error) {
     return &CryptoDB{conn: db}, nil
 }
 
+func encodePrices(prices map[string]float64) []byte {
+	if len(prices) == 0 {
+		return []byte(`{}`)
+	}
+	data, _ := json.Marshal(prices)
+	return data
+}
+
+func decodePrices(raw []byte) map[string]float64 {
+	prices := make(map[string]float64)
+	if err := json.Unmarshal(raw, &prices); err != nil {
+		log.Printf("Failed to decode stored prices: %v", err)
+	}
+	return prices
+}
+
 func (cdb *CryptoDB) Insert(symbol string, data CoinData) error {
 	_, err := cdb.conn.Exec(`
-		INSERT INTO crypto (symbol, name, current_price, last_update)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO crypto (symbol, coin_id, name, current_price, prices,
+		                    market_cap, volume_24h, high_24h, low_24h, price_change_percent_24h, last_update)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		ON CONFLICT (symbol) DO UPDATE 
-		    SET current_price = EXCLUDED.current_price,
-		        last_update   = EXCLUDED.last_update,
-		        name          = EXCLUDED.name
-	`, symbol, data.Name, data.CurrentPrice, data.LastUpdate)
+		    SET current_price            = EXCLUDED.current_price,
+		        prices                   = EXCLUDED.prices,
+		        market_cap               = EXCLUDED.market_cap,
+		        volume_24h               = EXCLUDED.volume_24h,
+		        high_24h                 = EXCLUDED.high_24h,
+		        low_24h                  = EXCLUDED.low_24h,
+		        price_change_percent_24h = EXCLUDED.price_change_percent_24h,
+		        last_update              = EXCLUDED.last_update,
+		        name                     = EXCLUDED.name,
+		        coin_id                  = COALESCE(EXCLUDED.coin_id, crypto.coin_id)
+	`, symbol, data.CoinID, data.Name, data.CurrentPrice, encodePrices(data.Prices),
+		data.MarketCap, data.Volume24h, data.High24h, data.Low24h, data.PriceChangePercent24h, data.LastUpdate)
 	
 	if err != nil {
 		log.Printf("Failed to insert/update crypto %s: %v", symbol, err)
@@ -68,12 +109,60 @@ func (cdb *CryptoDB) Insert(symbol string, data CoinData) error {
 	return nil
 }
 
+func (cdb *CryptoDB) InsertBatch(cryptos []CoinDataWithSymbol) error {
+	tx, err := cdb.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO crypto (symbol, coin_id, name, current_price, prices,
+		                    market_cap, volume_24h, high_24h, low_24h, price_change_percent_24h, last_update)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (symbol) DO UPDATE 
+		    SET current_price            = EXCLUDED.current_price,
+		        prices                   = EXCLUDED.prices,
+		        market_cap               = EXCLUDED.market_cap,
+		        volume_24h               = EXCLUDED.volume_24h,
+		        high_24h                 = EXCLUDED.high_24h,
+		        low_24h                  = EXCLUDED.low_24h,
+		        price_change_percent_24h = EXCLUDED.price_change_percent_24h,
+		        last_update              = EXCLUDED.last_update,
+		        name                     = EXCLUDED.name,
+		        coin_id                  = COALESCE(EXCLUDED.coin_id, crypto.coin_id)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, crypto := range cryptos {
+		_, err := stmt.Exec(crypto.Symbol, crypto.CoinID, crypto.Name, crypto.CurrentPrice, encodePrices(crypto.Prices),
+			crypto.MarketCap, crypto.Volume24h, crypto.High24h, crypto.Low24h, crypto.PriceChangePercent24h, crypto.LastUpdate)
+		if err != nil {
+			log.Printf("Failed to insert/update crypto %s: %v", crypto.Symbol, err)
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	log.Printf("Successfully updated %d cryptocurrencies", len(cryptos))
+	return nil
+}
+
 func (cdb *CryptoDB) Get(symbol string) (CoinData, error) {
 	var data CoinData
+	var rawPrices []byte
 	err := cdb.conn.QueryRow(`
-		SELECT name, current_price, last_update 
+		SELECT COALESCE(coin_id, ''), name, current_price, prices,
+		       market_cap, volume_24h, high_24h, low_24h, price_change_percent_24h, last_update 
 		FROM crypto WHERE symbol = $1
-	`, symbol).Scan(&data.Name, &data.CurrentPrice, &data.LastUpdate)
+	`, symbol).Scan(&data.CoinID, &data.Name, &data.CurrentPrice, &rawPrices,
+		&data.MarketCap, &data.Volume24h, &data.High24h, &data.Low24h, &data.PriceChangePercent24h, &data.LastUpdate)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -82,12 +171,14 @@ func (cdb *CryptoDB) Get(symbol string) (CoinData, error) {
 		return CoinData{}, err
 	}
 
+	data.Prices = decodePrices(rawPrices)
 	return data, nil
 }
 
 func (cdb *CryptoDB) GetAll() (map[string]CoinData, error) {
 	rows, err := cdb.conn.Query(`
-		SELECT symbol, name, current_price, last_update 
+		SELECT symbol, COALESCE(coin_id, ''), name, current_price, prices,
+		       market_cap, volume_24h, high_24h, low_24h, price_change_percent_24h, last_update 
 		FROM crypto 
 		ORDER BY symbol
 	`)
@@ -100,11 +191,14 @@ func (cdb *CryptoDB) GetAll() (map[string]CoinData, error) {
 	for rows.Next() {
 		var symbol string
 		var data CoinData
+		var rawPrices []byte
 		
-		err := rows.Scan(&symbol, &data.Name, &data.CurrentPrice, &data.LastUpdate)
+		err := rows.Scan(&symbol, &data.CoinID, &data.Name, &data.CurrentPrice, &rawPrices,
+			&data.MarketCap, &data.Volume24h, &data.High24h, &data.Low24h, &data.PriceChangePercent24h, &data.LastUpdate)
 		if err != nil {
 			return nil, err
 		}
+		data.Prices = decodePrices(rawPrices)
 		
 		cryptos[symbol] = data
 	}
@@ -114,7 +208,8 @@ func (cdb *CryptoDB) GetAll() (map[string]CoinData, error) {
 
 func (cdb *CryptoDB) GetAllSlice() ([]CoinDataWithSymbol, error) {
 	rows, err := cdb.conn.Query(`
-		SELECT symbol, name, current_price, last_update 
+		SELECT symbol, COALESCE(coin_id, ''), name, current_price, prices,
+		       market_cap, volume_24h, high_24h, low_24h, price_change_percent_24h, last_update 
 		FROM crypto 
 		ORDER BY symbol
 	`)
@@ -126,11 +221,14 @@ func (cdb *CryptoDB) GetAllSlice() ([]CoinDataWithSymbol, error) {
 	var cryptos []CoinDataWithSymbol
 	for rows.Next() {
 		var crypto CoinDataWithSymbol
+		var rawPrices []byte
 		
-		err := rows.Scan(&crypto.Symbol, &crypto.Name, &crypto.CurrentPrice, &crypto.LastUpdate)
+		err := rows.Scan(&crypto.Symbol, &crypto.CoinID, &crypto.Name, &crypto.CurrentPrice, &rawPrices,
+			&crypto.MarketCap, &crypto.Volume24h, &crypto.High24h, &crypto.Low24h, &crypto.PriceChangePercent24h, &crypto.LastUpdate)
 		if err != nil {
 			return nil, err
 		}
+		crypto.Prices = decodePrices(rawPrices)
 		
 		cryptos = append(cryptos, crypto)
 	}
@@ -181,6 +279,25 @@ func (cdb *CryptoDB) UpdatePrice(symbol string, newPrice float64) error {
 	return nil
 }
 
+func (cdb *CryptoDB) SetCoinID(symbol string, coinID string) error {
+	res, err := cdb.conn.Exec(`UPDATE crypto SET coin_id = $1 WHERE symbol = $2`, coinID, symbol)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrUnknownCoin
+	}
+
+	log.Printf("Stored coin ID for %s: %s", symbol, coinID)
+	return nil
+}
+
 func (cdb *CryptoDB) GetCount() (int, error) {
 	var count int
 	err := cdb.conn.QueryRow(`SELECT COUNT(*) FROM crypto`).Scan(&count)
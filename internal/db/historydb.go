@@ -0,0 +1,453 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// HistoryPoint is a single recorded price. Price is in USD, Prices holds every
+// quote currency configured at the time it was recorded.
+type HistoryPoint struct {
+	Price  float64            `json:"price"`
+	Prices map[string]float64 `json:"prices,omitempty"`
+	MarketData
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Rollup aggregates the raw points of one bucket. Prices holds the closing
+// price in every quote currency.
+type Rollup struct {
+	Bucket    time.Time          `json:"bucket"`
+	Open      float64            `json:"open"`
+	High      float64            `json:"high"`
+	Low       float64            `json:"low"`
+	Close     float64            `json:"close"`
+	Avg       float64            `json:"avg"`
+	Samples   int                `json:"samples"`
+	Prices    map[string]float64 `json:"prices,omitempty"`
+	MarketCap float64            `json:"market_cap"`
+	Volume24h float64            `json:"volume_24h"`
+}
+
+type Resolution string
+
+const (
+	ResolutionMinute Resolution = "1m"
+	ResolutionHour   Resolution = "1h"
+	ResolutionDay    Resolution = "1d"
+)
+
+var Resolutions = []Resolution{ResolutionMinute, ResolutionHour, ResolutionDay}
+
+var resolutionUnits = map[Resolution]string{
+	ResolutionMinute: "minute",
+	ResolutionHour:   "hour",
+	ResolutionDay:    "day",
+}
+
+type HistoryDB struct {
+	conn *sql.DB
+}
+
+func NewHistoryDB() (*HistoryDB, error) {
+	dsn := os.Getenv("DB_DSN")
+	if dsn == "" {
+		return nil, errors.New("DB_DSN environment variable is required")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, err
+	}
+
+	if err := runMigrations(db); err != nil {
+		return nil, err
+	}
+
+	return &HistoryDB{conn: db}, nil
+}
+
+const insertHistoryPoint = `
+	INSERT INTO price_history (symbol, ts, price, prices,
+	                           market_cap, volume_24h, high_24h, low_24h, price_change_percent_24h)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	ON CONFLICT (symbol, ts) DO NOTHING
+`
+
+func (hdb *HistoryDB) Insert(symbol string, point HistoryPoint) error {
+	_, err := hdb.conn.Exec(insertHistoryPoint, symbol, point.Timestamp, point.Price, encodePrices(point.Prices),
+		point.MarketCap, point.Volume24h, point.High24h, point.Low24h, point.PriceChangePercent24h)
+	if err != nil {
+		log.Printf("Failed to insert price history for %s: %v", symbol, err)
+		return err
+	}
+	return nil
+}
+
+func (hdb *HistoryDB) InsertBatch(points map[string]HistoryPoint) error {
+	tx, err := hdb.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(insertHistoryPoint)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for symbol, point := range points {
+		_, err := stmt.Exec(symbol, point.Timestamp, point.Price, encodePrices(point.Prices),
+			point.MarketCap, point.Volume24h, point.High24h, point.Low24h, point.PriceChangePercent24h)
+		if err != nil {
+			log.Printf("Failed to insert price history for %s: %v", symbol, err)
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// InsertOlder stores points recorded before anything already kept for symbol,
+// raw or rolled up, so overlapping imports never duplicate or dilute history.
+// It returns how many points were stored.
+func (hdb *HistoryDB) InsertOlder(symbol string, points []HistoryPoint) (int, error) {
+	tx, err := hdb.conn.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var oldest sql.NullTime
+	err = tx.QueryRow(`
+		SELECT LEAST(
+			(SELECT MIN(ts) FROM price_history WHERE symbol = $1),
+			(SELECT MIN(bucket) FROM price_history_rollups WHERE symbol = $1)
+		)
+	`, symbol).Scan(&oldest)
+	if err != nil {
+		return 0, err
+	}
+
+	stmt, err := tx.Prepare(insertHistoryPoint)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	inserted := 0
+	for _, point := range points {
+		if oldest.Valid && !point.Timestamp.Before(oldest.Time) {
+			continue
+		}
+
+		res, err := stmt.Exec(symbol, point.Timestamp, point.Price, encodePrices(point.Prices),
+			point.MarketCap, point.Volume24h, point.High24h, point.Low24h, point.PriceChangePercent24h)
+		if err != nil {
+			return 0, err
+		}
+		if rows, _ := res.RowsAffected(); rows > 0 {
+			inserted++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	log.Printf("Stored %d older price history points for %s", inserted, symbol)
+	return inserted, nil
+}
+
+// Latest returns up to limit raw points, newest first.
+func (hdb *HistoryDB) Latest(symbol string, limit int) ([]HistoryPoint, error) {
+	rows, err := hdb.conn.Query(`
+		SELECT ts, price, prices, market_cap, volume_24h, high_24h, low_24h, price_change_percent_24h
+		FROM price_history
+		WHERE symbol = $1
+		ORDER BY ts DESC
+		LIMIT $2
+	`, symbol, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanHistoryPoints(rows)
+}
+
+func scanHistoryPoints(rows *sql.Rows) ([]HistoryPoint, error) {
+	var points []HistoryPoint
+	for rows.Next() {
+		var point HistoryPoint
+		var rawPrices []byte
+
+		err := rows.Scan(&point.Timestamp, &point.Price, &rawPrices,
+			&point.MarketCap, &point.Volume24h, &point.High24h, &point.Low24h, &point.PriceChangePercent24h)
+		if err != nil {
+			return nil, err
+		}
+		point.Prices = decodePrices(rawPrices)
+		point.Timestamp = point.Timestamp.UTC()
+
+		points = append(points, point)
+	}
+
+	return points, rows.Err()
+}
+
+// Range returns up to limit raw points with from <= ts < to, newest first.
+func (hdb *HistoryDB) Range(symbol string, from time.Time, to time.Time, limit int) ([]HistoryPoint, error) {
+	rows, err := hdb.conn.Query(`
+		SELECT ts, price, prices, market_cap, volume_24h, high_24h, low_24h, price_change_percent_24h
+		FROM price_history
+		WHERE symbol = $1 AND ts >= $2 AND ts < $3
+		ORDER BY ts DESC
+		LIMIT $4
+	`, symbol, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanHistoryPoints(rows)
+}
+
+// BucketSpec describes how points are grouped into buckets. Fixed-width buckets
+// are aligned to the unix epoch in UTC. A calendar Unit ("day" or "week") aligns
+// buckets to local midnight in Location instead. Base is the rollup resolution
+// used for buckets older than the raw points; it must evenly divide a bucket.
+type BucketSpec struct {
+	Width    time.Duration
+	Unit     string
+	Location string
+	Base     Resolution
+}
+
+func bucketExpr(column string) string {
+	return fmt.Sprintf(`CASE WHEN $7 = '' THEN date_bin($2::interval, %[1]s, TIMESTAMPTZ 'epoch')
+		ELSE date_trunc($7, %[1]s AT TIME ZONE $8) AT TIME ZONE $8 END`, column)
+}
+
+// Buckets aggregates history into buckets, newest first. Empty buckets are not
+// returned. Buckets still covered by raw points are computed from them; older
+// buckets are merged from the base rollups.
+func (hdb *HistoryDB) Buckets(symbol string, spec BucketSpec, from time.Time, to time.Time, limit int) ([]Rollup, error) {
+	location := spec.Location
+	if location == "" {
+		location = "UTC"
+	}
+
+	rows, err := hdb.conn.Query(`
+		WITH bounds AS (
+			SELECT COALESCE(`+bucketExpr("MIN(ts)")+`, 'infinity'::timestamptz) AS raw_start
+			FROM price_history
+			WHERE symbol = $1
+		),
+		raw AS (
+			SELECT `+bucketExpr("p.ts")+` AS bucket,
+			       (array_agg(p.price ORDER BY p.ts))[1] AS open,
+			       MAX(p.price) AS high,
+			       MIN(p.price) AS low,
+			       (array_agg(p.price ORDER BY p.ts DESC))[1] AS close,
+			       AVG(p.price) AS avg,
+			       COUNT(*) AS samples,
+			       (array_agg(p.prices ORDER BY p.ts DESC))[1] AS prices,
+			       (array_agg(p.market_cap ORDER BY p.ts DESC))[1] AS market_cap,
+			       (array_agg(p.volume_24h ORDER BY p.ts DESC))[1] AS volume_24h
+			FROM price_history p, bounds b
+			WHERE p.symbol = $1 AND p.ts >= GREATEST($4::timestamptz, b.raw_start) AND p.ts < $5
+			GROUP BY 1
+		),
+		rolled AS (
+			SELECT `+bucketExpr("r.bucket")+` AS bucket,
+			       (array_agg(r.open ORDER BY r.bucket))[1] AS open,
+			       MAX(r.high) AS high,
+			       MIN(r.low) AS low,
+			       (array_agg(r.close ORDER BY r.bucket DESC))[1] AS close,
+			       SUM(r.avg * r.samples) / SUM(r.samples) AS avg,
+			       SUM(r.samples) AS samples,
+			       (array_agg(r.prices ORDER BY r.bucket DESC))[1] AS prices,
+			       (array_agg(r.market_cap ORDER BY r.bucket DESC))[1] AS market_cap,
+			       (array_agg(r.volume_24h ORDER BY r.bucket DESC))[1] AS volume_24h
+			FROM price_history_rollups r, bounds b
+			WHERE r.symbol = $1 AND r.resolution = $3 AND r.bucket >= $4 AND r.bucket < LEAST($5::timestamptz, b.raw_start)
+			GROUP BY 1
+		)
+		SELECT bucket, open, high, low, close, avg, samples, prices, market_cap, volume_24h
+		FROM (SELECT * FROM raw UNION ALL SELECT * FROM rolled) buckets
+		ORDER BY bucket DESC
+		LIMIT $6
+	`, symbol, fmt.Sprintf("%d seconds", int64(spec.Width/time.Second)), string(spec.Base), from, to, limit,
+		spec.Unit, location)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var buckets []Rollup
+	for rows.Next() {
+		var bucket Rollup
+		var rawPrices []byte
+
+		err := rows.Scan(&bucket.Bucket, &bucket.Open, &bucket.High, &bucket.Low, &bucket.Close, &bucket.Avg,
+			&bucket.Samples, &rawPrices, &bucket.MarketCap, &bucket.Volume24h)
+		if err != nil {
+			return nil, err
+		}
+		bucket.Prices = decodePrices(rawPrices)
+		bucket.Bucket = bucket.Bucket.UTC()
+
+		buckets = append(buckets, bucket)
+	}
+
+	return buckets, rows.Err()
+}
+
+func (hdb *HistoryDB) Delete(symbol string) error {
+	tx, err := hdb.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM price_history WHERE symbol = $1`, symbol); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM price_history_rollups WHERE symbol = $1`, symbol); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Rollup recomputes every bucket of resolution that received raw points since
+// the given time. Buckets are rebuilt from all of their raw points, so running
+// it twice is harmless.
+func (hdb *HistoryDB) Rollup(resolution Resolution, since time.Time) (int64, error) {
+	unit, exists := resolutionUnits[resolution]
+	if !exists {
+		return 0, fmt.Errorf("unknown resolution %s", resolution)
+	}
+
+	res, err := hdb.conn.Exec(`
+		WITH touched AS (
+			SELECT DISTINCT symbol, date_trunc($2, ts AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS bucket
+			FROM price_history
+			WHERE inserted_at >= $3
+		)
+		INSERT INTO price_history_rollups (symbol, resolution, bucket, open, high, low, close, avg, samples,
+		                                   prices, market_cap, volume_24h)
+		SELECT p.symbol, $1, t.bucket,
+		       (array_agg(p.price ORDER BY p.ts))[1],
+		       MAX(p.price),
+		       MIN(p.price),
+		       (array_agg(p.price ORDER BY p.ts DESC))[1],
+		       AVG(p.price),
+		       COUNT(*),
+		       (array_agg(p.prices ORDER BY p.ts DESC))[1],
+		       (array_agg(p.market_cap ORDER BY p.ts DESC))[1],
+		       (array_agg(p.volume_24h ORDER BY p.ts DESC))[1]
+		FROM touched t
+		JOIN price_history p
+		  ON p.symbol = t.symbol
+		 AND p.ts >= t.bucket
+		 AND p.ts < t.bucket + ('1 ' || $2)::interval
+		GROUP BY p.symbol, t.bucket
+		ON CONFLICT (symbol, resolution, bucket) DO UPDATE
+		    SET open       = EXCLUDED.open,
+		        high       = EXCLUDED.high,
+		        low        = EXCLUDED.low,
+		        close      = EXCLUDED.close,
+		        avg        = EXCLUDED.avg,
+		        samples    = EXCLUDED.samples,
+		        prices     = EXCLUDED.prices,
+		        market_cap = EXCLUDED.market_cap,
+		        volume_24h = EXCLUDED.volume_24h
+	`, string(resolution), unit, since)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// PurgeRaw deletes raw points older than retention, one UTC day at a time, and
+// only days whose rollups of every given resolution already count all of the
+// day's points. Points backfilled since the last rollup are kept until they
+// are rolled up.
+func (hdb *HistoryDB) PurgeRaw(retention time.Duration, resolutions []Resolution) (int64, error) {
+	cutoff := time.Now().UTC().Add(-retention).Truncate(24 * time.Hour)
+
+	if len(resolutions) == 0 {
+		res, err := hdb.conn.Exec(`DELETE FROM price_history WHERE ts < $1`, cutoff)
+		if err != nil {
+			return 0, err
+		}
+		return res.RowsAffected()
+	}
+
+	names := make([]string, len(resolutions))
+	for i, resolution := range resolutions {
+		names[i] = string(resolution)
+	}
+
+	res, err := hdb.conn.Exec(`
+		WITH raw_days AS (
+			SELECT symbol, date_trunc('day', ts AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS day, COUNT(*) AS samples
+			FROM price_history
+			WHERE ts < $1
+			GROUP BY 1, 2
+		), rolled AS (
+			SELECT symbol, resolution, date_trunc('day', bucket AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS day,
+			       SUM(samples) AS samples
+			FROM price_history_rollups
+			WHERE bucket < $1 AND resolution = ANY($2)
+			GROUP BY 1, 2, 3
+		), covered AS (
+			SELECT raw_days.symbol, raw_days.day
+			FROM raw_days
+			JOIN rolled ON rolled.symbol = raw_days.symbol AND rolled.day = raw_days.day AND rolled.samples = raw_days.samples
+			GROUP BY raw_days.symbol, raw_days.day
+			HAVING COUNT(*) = $3
+		)
+		DELETE FROM price_history p
+		USING covered c
+		WHERE p.symbol = c.symbol AND p.ts >= c.day AND p.ts < c.day + INTERVAL '1 day'
+	`, cutoff, pq.Array(names), len(names))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (hdb *HistoryDB) PurgeRollups(resolution Resolution, retention time.Duration) (int64, error) {
+	cutoff := time.Now().UTC().Add(-retention)
+
+	res, err := hdb.conn.Exec(`DELETE FROM price_history_rollups WHERE resolution = $1 AND bucket < $2`, string(resolution), cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (hdb *HistoryDB) Close() error {
+	if hdb.conn != nil {
+		return hdb.conn.Close()
+	}
+	return nil
+}
+
+func (hdb *HistoryDB) Ping() error {
+	return hdb.conn.Ping()
+}
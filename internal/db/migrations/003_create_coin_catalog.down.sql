@@ -0,0 +1 @@
+DROP TABLE IF EXISTS coin_catalog;
\ No newline at end of file
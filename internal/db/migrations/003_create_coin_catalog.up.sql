@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS coin_catalog (
+    provider TEXT NOT NULL,
+    id TEXT NOT NULL,
+    symbol TEXT NOT NULL,
+    name TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (provider, id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_coin_catalog_symbol ON coin_catalog(provider, symbol);
\ No newline at end of file
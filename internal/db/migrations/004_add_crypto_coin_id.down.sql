@@ -0,0 +1 @@
+ALTER TABLE crypto DROP COLUMN IF EXISTS coin_id;
\ No newline at end of file
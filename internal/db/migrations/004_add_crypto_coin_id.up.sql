@@ -0,0 +1 @@
+ALTER TABLE crypto ADD COLUMN IF NOT EXISTS coin_id TEXT;
\ No newline at end of file
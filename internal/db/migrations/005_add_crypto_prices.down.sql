@@ -0,0 +1 @@
+ALTER TABLE crypto DROP COLUMN IF EXISTS prices;
\ No newline at end of file
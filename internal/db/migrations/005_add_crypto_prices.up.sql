@@ -0,0 +1 @@
+ALTER TABLE crypto ADD COLUMN IF NOT EXISTS prices JSONB NOT NULL DEFAULT '{}'::jsonb;
\ No newline at end of file
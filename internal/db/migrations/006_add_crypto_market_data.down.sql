@@ -0,0 +1,6 @@
+ALTER TABLE crypto
+    DROP COLUMN IF EXISTS market_cap,
+    DROP COLUMN IF EXISTS volume_24h,
+    DROP COLUMN IF EXISTS high_24h,
+    DROP COLUMN IF EXISTS low_24h,
+    DROP COLUMN IF EXISTS price_change_percent_24h;
\ No newline at end of file
@@ -0,0 +1,6 @@
+ALTER TABLE crypto
+    ADD COLUMN IF NOT EXISTS market_cap DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS volume_24h DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS high_24h DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS low_24h DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS price_change_percent_24h DOUBLE PRECISION NOT NULL DEFAULT 0;
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS price_history_rollups;
+DROP TABLE IF EXISTS price_history;
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS price_history (
+    symbol TEXT NOT NULL,
+    ts TIMESTAMPTZ NOT NULL,
+    price DOUBLE PRECISION NOT NULL,
+    prices JSONB NOT NULL DEFAULT '{}'::jsonb,
+    market_cap DOUBLE PRECISION NOT NULL DEFAULT 0,
+    volume_24h DOUBLE PRECISION NOT NULL DEFAULT 0,
+    high_24h DOUBLE PRECISION NOT NULL DEFAULT 0,
+    low_24h DOUBLE PRECISION NOT NULL DEFAULT 0,
+    price_change_percent_24h DOUBLE PRECISION NOT NULL DEFAULT 0,
+    inserted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (symbol, ts)
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_history_inserted_at ON price_history(inserted_at);
+
+CREATE TABLE IF NOT EXISTS price_history_rollups (
+    symbol TEXT NOT NULL,
+    resolution TEXT NOT NULL,
+    bucket TIMESTAMPTZ NOT NULL,
+    open DOUBLE PRECISION NOT NULL,
+    high DOUBLE PRECISION NOT NULL,
+    low DOUBLE PRECISION NOT NULL,
+    close DOUBLE PRECISION NOT NULL,
+    avg DOUBLE PRECISION NOT NULL,
+    samples INTEGER NOT NULL,
+    prices JSONB NOT NULL DEFAULT '{}'::jsonb,
+    market_cap DOUBLE PRECISION NOT NULL DEFAULT 0,
+    volume_24h DOUBLE PRECISION NOT NULL DEFAULT 0,
+    PRIMARY KEY (symbol, resolution, bucket)
+);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS watchlists (
+    id BIGSERIAL PRIMARY KEY,
+    owner TEXT NOT NULL REFERENCES users(login) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (owner, name)
+);
+
+CREATE TABLE IF NOT EXISTS watchlist_items (
+    watchlist_id BIGINT NOT NULL REFERENCES watchlists(id) ON DELETE CASCADE,
+    symbol TEXT NOT NULL REFERENCES crypto(symbol) ON DELETE CASCADE,
+    added_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (watchlist_id, symbol)
+);
+
+CREATE INDEX IF NOT EXISTS idx_watchlist_items_symbol ON watchlist_items(symbol);
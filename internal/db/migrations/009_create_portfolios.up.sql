@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS portfolios (
+    id BIGSERIAL PRIMARY KEY,
+    owner TEXT NOT NULL REFERENCES users(login) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    currency TEXT NOT NULL DEFAULT 'usd',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (owner, name)
+);
+
+-- Positions do not reference crypto(symbol): holdings must survive a coin
+-- being removed from tracking.
+CREATE TABLE IF NOT EXISTS portfolio_positions (
+    portfolio_id BIGINT NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+    symbol TEXT NOT NULL,
+    quantity NUMERIC(38, 18) NOT NULL CHECK (quantity > 0),
+    cost_basis NUMERIC(38, 18) NOT NULL CHECK (cost_basis >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (portfolio_id, symbol)
+);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS portfolio_transactions (
+    id BIGSERIAL PRIMARY KEY,
+    portfolio_id BIGINT NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+    type TEXT NOT NULL CHECK (type IN ('buy', 'sell', 'transfer_in', 'transfer_out')),
+    symbol TEXT NOT NULL,
+    quantity NUMERIC(38, 18) NOT NULL CHECK (quantity > 0),
+    price NUMERIC(38, 18) NOT NULL DEFAULT 0 CHECK (price >= 0),
+    fee NUMERIC(38, 18) NOT NULL DEFAULT 0 CHECK (fee >= 0),
+    executed_at TIMESTAMPTZ NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_portfolio_transactions_portfolio ON portfolio_transactions(portfolio_id, executed_at, id);
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS alerts (
+    id BIGSERIAL PRIMARY KEY,
+    owner TEXT NOT NULL REFERENCES users(login) ON DELETE CASCADE,
+    symbol TEXT NOT NULL,
+    currency TEXT NOT NULL DEFAULT 'usd',
+    type TEXT NOT NULL CHECK (type IN ('above', 'below', 'change', 'deviation')),
+    threshold DOUBLE PRECISION NOT NULL DEFAULT 0,
+    percent DOUBLE PRECISION NOT NULL DEFAULT 0,
+    window_seconds INTEGER NOT NULL DEFAULT 0,
+    direction TEXT NOT NULL DEFAULT '',
+    cooldown_seconds INTEGER NOT NULL DEFAULT 0,
+    note TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    armed BOOLEAN NOT NULL DEFAULT TRUE,
+    last_fired_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_alerts_owner ON alerts(owner);
+CREATE INDEX IF NOT EXISTS idx_alerts_symbol ON alerts(symbol) WHERE enabled;
+
+-- Firings keep the owner so the inbox survives deleting the alert.
+CREATE TABLE IF NOT EXISTS alert_events (
+    id BIGSERIAL PRIMARY KEY,
+    alert_id BIGINT REFERENCES alerts(id) ON DELETE SET NULL,
+    owner TEXT NOT NULL REFERENCES users(login) ON DELETE CASCADE,
+    symbol TEXT NOT NULL,
+    currency TEXT NOT NULL,
+    price DOUBLE PRECISION NOT NULL,
+    message TEXT NOT NULL,
+    fired_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    read_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_events_owner ON alert_events(owner, fired_at DESC);
+CREATE INDEX IF NOT EXISTS idx_alert_events_alert ON alert_events(alert_id, fired_at DESC);
@@ -0,0 +1,3 @@
+DELETE FROM watchlist_items WHERE symbol NOT IN (SELECT symbol FROM crypto);
+ALTER TABLE watchlist_items ADD CONSTRAINT watchlist_items_symbol_fkey
+    FOREIGN KEY (symbol) REFERENCES crypto(symbol) ON DELETE CASCADE;
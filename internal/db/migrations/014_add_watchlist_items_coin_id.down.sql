@@ -0,0 +1 @@
+ALTER TABLE watchlist_items DROP COLUMN IF EXISTS coin_id;
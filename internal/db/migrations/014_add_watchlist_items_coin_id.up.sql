@@ -0,0 +1,5 @@
+ALTER TABLE watchlist_items ADD COLUMN IF NOT EXISTS coin_id TEXT;
+
+UPDATE watchlist_items i SET coin_id = c.coin_id
+FROM crypto c
+WHERE c.symbol = i.symbol AND i.coin_id IS NULL;
@@ -0,0 +1,390 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrUnknownPortfolio   = errors.New("unknown portfolio")
+	ErrPortfolioNameUsed  = errors.New("portfolio name already used")
+	ErrUnknownPosition    = errors.New("unknown position")
+	ErrPositionExists     = errors.New("position already exists")
+	ErrUnknownTransaction = errors.New("unknown transaction")
+)
+
+const (
+	TransactionBuy         = "buy"
+	TransactionSell        = "sell"
+	TransactionTransferIn  = "transfer_in"
+	TransactionTransferOut = "transfer_out"
+)
+
+// Portfolio is a named set of holdings owned by one user. Cost basis and
+// valuation are expressed in Currency.
+type Portfolio struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Currency  string    `json:"currency"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Position is the holding of one symbol in a portfolio. CostBasis is the total
+// amount paid for Quantity. Stored positions are opening balances acquired at
+// CreatedAt; the ledger transactions are applied on top of them.
+type Position struct {
+	Symbol    string          `json:"symbol"`
+	Quantity  decimal.Decimal `json:"quantity"`
+	CostBasis decimal.Decimal `json:"cost_basis"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// Transaction is one ledger entry of a portfolio. Price is per unit and Fee is
+// a total, both in the portfolio currency.
+type Transaction struct {
+	ID         int64           `json:"id"`
+	Type       string          `json:"type"`
+	Symbol     string          `json:"symbol"`
+	Quantity   decimal.Decimal `json:"quantity"`
+	Price      decimal.Decimal `json:"price"`
+	Fee        decimal.Decimal `json:"fee"`
+	ExecutedAt time.Time       `json:"executed_at"`
+	Note       string          `json:"note"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// LedgerCheck validates the opening positions and transactions of a portfolio
+// as they would be after a change. Returning an error aborts the change.
+type LedgerCheck func(positions []Position, transactions []Transaction) error
+
+type PortfolioDB struct {
+	conn *sql.DB
+}
+
+type querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+func NewPortfolioDB() (*PortfolioDB, error) {
+	dsn := os.Getenv("DB_DSN")
+	if dsn == "" {
+		return nil, errors.New("DB_DSN environment variable is required")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, err
+	}
+
+	if err := runMigrations(db); err != nil {
+		return nil, err
+	}
+
+	return &PortfolioDB{conn: db}, nil
+}
+
+func (pdb *PortfolioDB) Create(owner string, name string, currency string) (Portfolio, error) {
+	portfolio := Portfolio{Name: name, Currency: currency}
+	err := pdb.conn.QueryRow(`
+		INSERT INTO portfolios (owner, name, currency) VALUES ($1, $2, $3)
+		RETURNING id, created_at, updated_at
+	`, owner, name, currency).Scan(&portfolio.ID, &portfolio.CreatedAt, &portfolio.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return Portfolio{}, ErrPortfolioNameUsed
+		}
+		return Portfolio{}, err
+	}
+	return portfolio, nil
+}
+
+func (pdb *PortfolioDB) List(owner string) ([]Portfolio, error) {
+	rows, err := pdb.conn.Query(`
+		SELECT id, name, currency, created_at, updated_at
+		FROM portfolios
+		WHERE owner = $1
+		ORDER BY name
+	`, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	portfolios := []Portfolio{}
+	for rows.Next() {
+		var portfolio Portfolio
+		if err := rows.Scan(&portfolio.ID, &portfolio.Name, &portfolio.Currency, &portfolio.CreatedAt, &portfolio.UpdatedAt); err != nil {
+			return nil, err
+		}
+		portfolios = append(portfolios, portfolio)
+	}
+	return portfolios, rows.Err()
+}
+
+func (pdb *PortfolioDB) Get(owner string, id int64) (Portfolio, error) {
+	var portfolio Portfolio
+	err := pdb.conn.QueryRow(`
+		SELECT id, name, currency, created_at, updated_at
+		FROM portfolios
+		WHERE owner = $1 AND id = $2
+	`, owner, id).Scan(&portfolio.ID, &portfolio.Name, &portfolio.Currency, &portfolio.CreatedAt, &portfolio.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Portfolio{}, ErrUnknownPortfolio
+		}
+		return Portfolio{}, err
+	}
+	return portfolio, nil
+}
+
+func (pdb *PortfolioDB) Rename(owner string, id int64, name string) error {
+	res, err := pdb.conn.Exec(`
+		UPDATE portfolios SET name = $3, updated_at = NOW()
+		WHERE owner = $1 AND id = $2
+	`, owner, id, name)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrPortfolioNameUsed
+		}
+		return err
+	}
+	return requireRow(res, ErrUnknownPortfolio)
+}
+
+func (pdb *PortfolioDB) Delete(owner string, id int64) error {
+	res, err := pdb.conn.Exec(`DELETE FROM portfolios WHERE owner = $1 AND id = $2`, owner, id)
+	if err != nil {
+		return err
+	}
+	return requireRow(res, ErrUnknownPortfolio)
+}
+
+// Positions returns the positions of a portfolio ordered by symbol. The caller
+// checks ownership with Get first.
+func (pdb *PortfolioDB) Positions(id int64) ([]Position, error) {
+	return queryPositions(pdb.conn, id)
+}
+
+func queryPositions(q querier, id int64) ([]Position, error) {
+	rows, err := q.Query(`
+		SELECT symbol, quantity, cost_basis, created_at, updated_at
+		FROM portfolio_positions
+		WHERE portfolio_id = $1
+		ORDER BY symbol
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	positions := []Position{}
+	for rows.Next() {
+		var position Position
+		if err := rows.Scan(&position.Symbol, &position.Quantity, &position.CostBasis, &position.CreatedAt, &position.UpdatedAt); err != nil {
+			return nil, err
+		}
+		positions = append(positions, position)
+	}
+	return positions, rows.Err()
+}
+
+func (pdb *PortfolioDB) AddPosition(owner string, id int64, position Position) (Position, error) {
+	err := pdb.conn.QueryRow(`
+		INSERT INTO portfolio_positions (portfolio_id, symbol, quantity, cost_basis)
+		SELECT id, $3, $4, $5 FROM portfolios WHERE owner = $1 AND id = $2
+		RETURNING created_at, updated_at
+	`, owner, id, position.Symbol, position.Quantity, position.CostBasis).Scan(&position.CreatedAt, &position.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Position{}, ErrUnknownPortfolio
+		}
+		if isUniqueViolation(err) {
+			return Position{}, ErrPositionExists
+		}
+		return Position{}, err
+	}
+	return position, nil
+}
+
+// UpdatePosition replaces the opening balance of a symbol if the ledger still
+// passes check afterwards.
+func (pdb *PortfolioDB) UpdatePosition(owner string, id int64, position Position, check LedgerCheck) (Position, error) {
+	err := pdb.withLedger(owner, id, func(tx *sql.Tx, positions []Position, transactions []Transaction) error {
+		index := slices.IndexFunc(positions, func(p Position) bool { return p.Symbol == position.Symbol })
+		if index < 0 {
+			return ErrUnknownPosition
+		}
+		positions[index].Quantity = position.Quantity
+		positions[index].CostBasis = position.CostBasis
+		if err := check(positions, transactions); err != nil {
+			return err
+		}
+
+		return tx.QueryRow(`
+			UPDATE portfolio_positions SET quantity = $3, cost_basis = $4, updated_at = NOW()
+			WHERE portfolio_id = $1 AND symbol = $2
+			RETURNING created_at, updated_at
+		`, id, position.Symbol, position.Quantity, position.CostBasis).Scan(&position.CreatedAt, &position.UpdatedAt)
+	})
+	if err != nil {
+		return Position{}, err
+	}
+	return position, nil
+}
+
+// DeletePosition removes the opening balance of a symbol if the ledger still
+// passes check afterwards.
+func (pdb *PortfolioDB) DeletePosition(owner string, id int64, symbol string, check LedgerCheck) error {
+	return pdb.withLedger(owner, id, func(tx *sql.Tx, positions []Position, transactions []Transaction) error {
+		index := slices.IndexFunc(positions, func(p Position) bool { return p.Symbol == symbol })
+		if index < 0 {
+			return ErrUnknownPosition
+		}
+		if err := check(slices.Delete(positions, index, index+1), transactions); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(`DELETE FROM portfolio_positions WHERE portfolio_id = $1 AND symbol = $2`, id, symbol)
+		return err
+	})
+}
+
+// Transactions returns the ledger of a portfolio in execution order, limited
+// to symbol unless it is empty. The caller checks ownership with Get first.
+func (pdb *PortfolioDB) Transactions(id int64, symbol string) ([]Transaction, error) {
+	return queryTransactions(pdb.conn, id, symbol)
+}
+
+func queryTransactions(q querier, id int64, symbol string) ([]Transaction, error) {
+	rows, err := q.Query(`
+		SELECT id, type, symbol, quantity, price, fee, executed_at, note, created_at
+		FROM portfolio_transactions
+		WHERE portfolio_id = $1 AND ($2 = '' OR symbol = $2)
+		ORDER BY executed_at, id
+	`, id, symbol)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transactions := []Transaction{}
+	for rows.Next() {
+		var t Transaction
+		err := rows.Scan(&t.ID, &t.Type, &t.Symbol, &t.Quantity, &t.Price, &t.Fee, &t.ExecutedAt, &t.Note, &t.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		t.ExecutedAt = t.ExecutedAt.UTC()
+		transactions = append(transactions, t)
+	}
+	return transactions, rows.Err()
+}
+
+// AddTransactions appends entries to the ledger of a portfolio in one database
+// transaction if the ledger passes check with them.
+func (pdb *PortfolioDB) AddTransactions(owner string, id int64, entries []Transaction, check LedgerCheck) ([]Transaction, error) {
+	err := pdb.withLedger(owner, id, func(tx *sql.Tx, positions []Position, transactions []Transaction) error {
+		if err := check(positions, append(transactions, entries...)); err != nil {
+			return err
+		}
+
+		stmt, err := tx.Prepare(`
+			INSERT INTO portfolio_transactions (portfolio_id, type, symbol, quantity, price, fee, executed_at, note)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id, created_at
+		`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for i := range entries {
+			t := &entries[i]
+			err := stmt.QueryRow(id, t.Type, t.Symbol, t.Quantity, t.Price, t.Fee, t.ExecutedAt, t.Note).Scan(&t.ID, &t.CreatedAt)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec(`UPDATE portfolios SET updated_at = NOW() WHERE id = $1`, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// DeleteTransaction removes a ledger entry if the ledger still passes check
+// without it.
+func (pdb *PortfolioDB) DeleteTransaction(owner string, id int64, transactionID int64, check LedgerCheck) error {
+	return pdb.withLedger(owner, id, func(tx *sql.Tx, positions []Position, transactions []Transaction) error {
+		index := slices.IndexFunc(transactions, func(t Transaction) bool { return t.ID == transactionID })
+		if index < 0 {
+			return ErrUnknownTransaction
+		}
+		if err := check(positions, slices.Delete(transactions, index, index+1)); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(`DELETE FROM portfolio_transactions WHERE id = $1`, transactionID); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`UPDATE portfolios SET updated_at = NOW() WHERE id = $1`, id)
+		return err
+	})
+}
+
+// withLedger runs apply in a database transaction holding a lock on the
+// portfolio row, so concurrent changes are checked against the same ledger.
+func (pdb *PortfolioDB) withLedger(owner string, id int64, apply func(tx *sql.Tx, positions []Position, transactions []Transaction) error) error {
+	tx, err := pdb.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`SELECT id FROM portfolios WHERE owner = $1 AND id = $2 FOR UPDATE`, owner, id).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUnknownPortfolio
+		}
+		return err
+	}
+
+	positions, err := queryPositions(tx, id)
+	if err != nil {
+		return err
+	}
+	transactions, err := queryTransactions(tx, id, "")
+	if err != nil {
+		return err
+	}
+
+	if err := apply(tx, positions, transactions); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (pdb *PortfolioDB) Close() error {
+	if pdb.conn != nil {
+		return pdb.conn.Close()
+	}
+	return nil
+}
+
+func (pdb *PortfolioDB) Ping() error {
+	return pdb.conn.Ping()
+}
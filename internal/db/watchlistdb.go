@@ -0,0 +1,284 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrUnknownWatchlist     = errors.New("unknown watchlist")
+	ErrWatchlistNameUsed    = errors.New("watchlist name already used")
+	ErrSymbolNotInWatchlist = errors.New("symbol not in watchlist")
+	ErrWatchlistFull        = errors.New("watchlist is full")
+)
+
+// Watchlist is a named list of tracked symbols owned by one user. Symbols are
+// ordered by the time they were added.
+type Watchlist struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Symbols   []string  `json:"symbols"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WatchlistItem is a member of a watchlist with the provider coin id it was
+// added as, empty for members added before coin ids were stored.
+type WatchlistItem struct {
+	Symbol string `json:"symbol"`
+	CoinID string `json:"coin_id,omitempty"`
+}
+
+type WatchlistDB struct {
+	conn *sql.DB
+}
+
+func NewWatchlistDB() (*WatchlistDB, error) {
+	dsn := os.Getenv("DB_DSN")
+	if dsn == "" {
+		return nil, errors.New("DB_DSN environment variable is required")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, err
+	}
+
+	if err := runMigrations(db); err != nil {
+		return nil, err
+	}
+
+	return &WatchlistDB{conn: db}, nil
+}
+
+const watchlistSelect = `
+	SELECT w.id, w.name, w.created_at, w.updated_at,
+	       COALESCE(array_agg(i.symbol ORDER BY i.added_at, i.symbol) FILTER (WHERE i.symbol IS NOT NULL), '{}')
+	FROM watchlists w
+	LEFT JOIN watchlist_items i ON i.watchlist_id = w.id
+`
+
+func scanWatchlist(row interface{ Scan(...any) error }) (Watchlist, error) {
+	var watchlist Watchlist
+	err := row.Scan(&watchlist.ID, &watchlist.Name, &watchlist.CreatedAt, &watchlist.UpdatedAt, pq.Array(&watchlist.Symbols))
+	if watchlist.Symbols == nil {
+		watchlist.Symbols = []string{}
+	}
+	return watchlist, err
+}
+
+func isUniqueViolation(err error) bool {
+	pgErr, ok := err.(*pq.Error)
+	return ok && pgErr.Code == "23505"
+}
+
+func (wdb *WatchlistDB) Create(owner string, name string) (Watchlist, error) {
+	watchlist := Watchlist{Name: name, Symbols: []string{}}
+	err := wdb.conn.QueryRow(`
+		INSERT INTO watchlists (owner, name) VALUES ($1, $2)
+		RETURNING id, created_at, updated_at
+	`, owner, name).Scan(&watchlist.ID, &watchlist.CreatedAt, &watchlist.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return Watchlist{}, ErrWatchlistNameUsed
+		}
+		return Watchlist{}, err
+	}
+	return watchlist, nil
+}
+
+func (wdb *WatchlistDB) List(owner string) ([]Watchlist, error) {
+	rows, err := wdb.conn.Query(watchlistSelect+`
+		WHERE w.owner = $1
+		GROUP BY w.id
+		ORDER BY w.name
+	`, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	watchlists := []Watchlist{}
+	for rows.Next() {
+		watchlist, err := scanWatchlist(rows)
+		if err != nil {
+			return nil, err
+		}
+		watchlists = append(watchlists, watchlist)
+	}
+	return watchlists, rows.Err()
+}
+
+func (wdb *WatchlistDB) Get(owner string, id int64) (Watchlist, error) {
+	watchlist, err := scanWatchlist(wdb.conn.QueryRow(watchlistSelect+`
+		WHERE w.owner = $1 AND w.id = $2
+		GROUP BY w.id
+	`, owner, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Watchlist{}, ErrUnknownWatchlist
+		}
+		return Watchlist{}, err
+	}
+	return watchlist, nil
+}
+
+func (wdb *WatchlistDB) Rename(owner string, id int64, name string) error {
+	res, err := wdb.conn.Exec(`
+		UPDATE watchlists SET name = $3, updated_at = NOW()
+		WHERE owner = $1 AND id = $2
+	`, owner, id, name)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrWatchlistNameUsed
+		}
+		return err
+	}
+	return requireRow(res, ErrUnknownWatchlist)
+}
+
+func (wdb *WatchlistDB) Delete(owner string, id int64) error {
+	res, err := wdb.conn.Exec(`DELETE FROM watchlists WHERE owner = $1 AND id = $2`, owner, id)
+	if err != nil {
+		return err
+	}
+	return requireRow(res, ErrUnknownWatchlist)
+}
+
+// Items returns the members of a watchlist in the order they were added.
+func (wdb *WatchlistDB) Items(id int64) ([]WatchlistItem, error) {
+	rows, err := wdb.conn.Query(`
+		SELECT symbol, COALESCE(coin_id, '')
+		FROM watchlist_items
+		WHERE watchlist_id = $1
+		ORDER BY added_at, symbol
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []WatchlistItem{}
+	for rows.Next() {
+		var item WatchlistItem
+		if err := rows.Scan(&item.Symbol, &item.CoinID); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// AddSymbol adds a tracked symbol to a watchlist holding fewer than limit
+// symbols, pinned to coinID. Adding a symbol twice is not an error; a non-empty
+// coinID replaces the one it was added as.
+func (wdb *WatchlistDB) AddSymbol(owner string, id int64, symbol string, coinID string, limit int) error {
+	tx, err := wdb.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Locking the watchlist row serializes concurrent adds for the limit check.
+	err = tx.QueryRow(`SELECT id FROM watchlists WHERE owner = $1 AND id = $2 FOR UPDATE`, owner, id).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUnknownWatchlist
+		}
+		return err
+	}
+
+	var count int
+	var exists bool
+	err = tx.QueryRow(`
+		SELECT COUNT(*), COALESCE(bool_or(symbol = $2), false)
+		FROM watchlist_items
+		WHERE watchlist_id = $1
+	`, id, symbol).Scan(&count, &exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if coinID == "" {
+			return nil
+		}
+		_, err := tx.Exec(`UPDATE watchlist_items SET coin_id = $3 WHERE watchlist_id = $1 AND symbol = $2`, id, symbol, coinID)
+		if err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+	if count >= limit {
+		return ErrWatchlistFull
+	}
+
+	_, err = tx.Exec(`INSERT INTO watchlist_items (watchlist_id, symbol, coin_id) VALUES ($1, $2, NULLIF($3, ''))`,
+		id, symbol, coinID)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE watchlists SET updated_at = NOW() WHERE id = $1`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (wdb *WatchlistDB) RemoveSymbol(owner string, id int64, symbol string) error {
+	tx, err := wdb.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+		UPDATE watchlists SET updated_at = NOW()
+		WHERE owner = $1 AND id = $2
+	`, owner, id)
+	if err != nil {
+		return err
+	}
+	if err := requireRow(res, ErrUnknownWatchlist); err != nil {
+		return err
+	}
+
+	res, err = tx.Exec(`DELETE FROM watchlist_items WHERE watchlist_id = $1 AND symbol = $2`, id, symbol)
+	if err != nil {
+		return err
+	}
+	if err := requireRow(res, ErrSymbolNotInWatchlist); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func requireRow(res sql.Result, notFound error) error {
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return notFound
+	}
+	return nil
+}
+
+func (wdb *WatchlistDB) Close() error {
+	if wdb.conn != nil {
+		return wdb.conn.Close()
+	}
+	return nil
+}
+
+func (wdb *WatchlistDB) Ping() error {
+	return wdb.conn.Ping()
+}
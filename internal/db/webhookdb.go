@@ -0,0 +1,422 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrUnknownWebhook    = errors.New("unknown webhook")
+	ErrTooManyWebhooks   = errors.New("too many webhooks")
+	ErrUnknownDelivery   = errors.New("unknown webhook delivery")
+	ErrDeliveryNotFailed = errors.New("only failed deliveries can be retried")
+)
+
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// Webhook is an endpoint of one user that receives the events it subscribed
+// to. An empty Symbols receives price updates of every symbol. Secret signs
+// the payloads and is only shown when the webhook is created.
+type Webhook struct {
+	ID        int64     `json:"id"`
+	Owner     string    `json:"-"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Symbols   []string  `json:"symbols"`
+	Secret    string    `json:"secret,omitempty"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WebhookDelivery is one event sent to one webhook, with the outcome of its
+// latest attempt. Pending deliveries are retried from NextAttemptAt on.
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	WebhookID      int64           `json:"webhook_id"`
+	EventID        string          `json:"event_id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at"`
+	ResponseStatus *int            `json:"response_status"`
+	LastError      string          `json:"last_error"`
+	LastAttemptAt  *time.Time      `json:"last_attempt_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+
+	// URL and Secret are those of the webhook when the delivery was claimed.
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
+
+type WebhookDB struct {
+	conn *sql.DB
+}
+
+func NewWebhookDB() (*WebhookDB, error) {
+	dsn := os.Getenv("DB_DSN")
+	if dsn == "" {
+		return nil, errors.New("DB_DSN environment variable is required")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, err
+	}
+
+	if err := runMigrations(db); err != nil {
+		return nil, err
+	}
+
+	return &WebhookDB{conn: db}, nil
+}
+
+const webhookColumns = `id, owner, url, events, symbols, secret, enabled, created_at, updated_at`
+
+func scanWebhook(row interface{ Scan(...any) error }) (Webhook, error) {
+	var webhook Webhook
+	err := row.Scan(&webhook.ID, &webhook.Owner, &webhook.URL, pq.Array(&webhook.Events), pq.Array(&webhook.Symbols),
+		&webhook.Secret, &webhook.Enabled, &webhook.CreatedAt, &webhook.UpdatedAt)
+	if webhook.Symbols == nil {
+		webhook.Symbols = []string{}
+	}
+	return webhook, err
+}
+
+func scanWebhooks(rows *sql.Rows) ([]Webhook, error) {
+	defer rows.Close()
+
+	webhooks := []Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, rows.Err()
+}
+
+// Create stores a new webhook unless the owner already has limit webhooks.
+func (wdb *WebhookDB) Create(webhook Webhook, limit int) (Webhook, error) {
+	tx, err := wdb.conn.Begin()
+	if err != nil {
+		return Webhook{}, err
+	}
+	defer tx.Rollback()
+
+	// Locking the user row serializes concurrent creates for the limit check.
+	if _, err := tx.Exec(`SELECT login FROM users WHERE login = $1 FOR UPDATE`, webhook.Owner); err != nil {
+		return Webhook{}, err
+	}
+
+	var count int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM webhooks WHERE owner = $1`, webhook.Owner).Scan(&count); err != nil {
+		return Webhook{}, err
+	}
+	if count >= limit {
+		return Webhook{}, ErrTooManyWebhooks
+	}
+
+	webhook, err = scanWebhook(tx.QueryRow(`
+		INSERT INTO webhooks (owner, url, events, symbols, secret, enabled)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+webhookColumns,
+		webhook.Owner, webhook.URL, pq.Array(webhook.Events), pq.Array(webhook.Symbols), webhook.Secret, webhook.Enabled))
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	return webhook, tx.Commit()
+}
+
+func (wdb *WebhookDB) List(owner string) ([]Webhook, error) {
+	rows, err := wdb.conn.Query(`SELECT `+webhookColumns+` FROM webhooks WHERE owner = $1 ORDER BY id`, owner)
+	if err != nil {
+		return nil, err
+	}
+	return scanWebhooks(rows)
+}
+
+func (wdb *WebhookDB) Get(owner string, id int64) (Webhook, error) {
+	webhook, err := scanWebhook(wdb.conn.QueryRow(`SELECT `+webhookColumns+` FROM webhooks WHERE owner = $1 AND id = $2`, owner, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Webhook{}, ErrUnknownWebhook
+		}
+		return Webhook{}, err
+	}
+	return webhook, nil
+}
+
+// Update replaces the URL, events, symbols and enabled flag of a webhook, and
+// its secret unless webhook.Secret is empty. Disabling a webhook fails its
+// pending deliveries.
+func (wdb *WebhookDB) Update(webhook Webhook) (Webhook, error) {
+	tx, err := wdb.conn.Begin()
+	if err != nil {
+		return Webhook{}, err
+	}
+	defer tx.Rollback()
+
+	webhook, err = scanWebhook(tx.QueryRow(`
+		UPDATE webhooks SET url = $3, events = $4, symbols = $5, secret = COALESCE(NULLIF($6, ''), secret),
+		       enabled = $7, updated_at = NOW()
+		WHERE owner = $1 AND id = $2
+		RETURNING `+webhookColumns,
+		webhook.Owner, webhook.ID, webhook.URL, pq.Array(webhook.Events), pq.Array(webhook.Symbols), webhook.Secret,
+		webhook.Enabled))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Webhook{}, ErrUnknownWebhook
+		}
+		return Webhook{}, err
+	}
+
+	if !webhook.Enabled {
+		_, err := tx.Exec(`
+			UPDATE webhook_deliveries SET status = 'failed', last_error = 'webhook disabled'
+			WHERE webhook_id = $1 AND status = 'pending'
+		`, webhook.ID)
+		if err != nil {
+			return Webhook{}, err
+		}
+	}
+
+	return webhook, tx.Commit()
+}
+
+func (wdb *WebhookDB) Delete(owner string, id int64) error {
+	res, err := wdb.conn.Exec(`DELETE FROM webhooks WHERE owner = $1 AND id = $2`, owner, id)
+	if err != nil {
+		return err
+	}
+	return requireRow(res, ErrUnknownWebhook)
+}
+
+// Subscribed returns the enabled webhooks subscribed to event. A non-empty
+// owner limits them to one user; symbols skips webhooks filtering on other
+// symbols only.
+func (wdb *WebhookDB) Subscribed(event string, owner string, symbols []string) ([]Webhook, error) {
+	rows, err := wdb.conn.Query(`
+		SELECT `+webhookColumns+` FROM webhooks
+		WHERE enabled AND $1 = ANY(events) AND ($2 = '' OR owner = $2)
+		  AND (cardinality(symbols) = 0 OR symbols && $3)
+		ORDER BY id
+	`, event, owner, pq.Array(symbols))
+	if err != nil {
+		return nil, err
+	}
+	return scanWebhooks(rows)
+}
+
+// Enqueue stores pending deliveries, due at once.
+func (wdb *WebhookDB) Enqueue(deliveries []WebhookDelivery) error {
+	tx, err := wdb.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT INTO webhook_deliveries (webhook_id, event_id, event, payload) VALUES ($1, $2, $3, $4)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, delivery := range deliveries {
+		if _, err := stmt.Exec(delivery.WebhookID, delivery.EventID, delivery.Event, string(delivery.Payload)); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// EnqueueClaimed stores a pending delivery that is already claimed for lease,
+// so its first attempt can be made right away by the caller.
+func (wdb *WebhookDB) EnqueueClaimed(delivery WebhookDelivery, lease time.Duration) (WebhookDelivery, error) {
+	err := wdb.conn.QueryRow(`
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event, payload, next_attempt_at)
+		VALUES ($1, $2, $3, $4, NOW() + $5 * INTERVAL '1 second')
+		RETURNING id, status, next_attempt_at, created_at
+	`, delivery.WebhookID, delivery.EventID, delivery.Event, string(delivery.Payload), lease.Seconds()).
+		Scan(&delivery.ID, &delivery.Status, &delivery.NextAttemptAt, &delivery.CreatedAt)
+	if err != nil {
+		return WebhookDelivery{}, err
+	}
+	return delivery, nil
+}
+
+// Claim returns up to limit due deliveries of enabled webhooks and postpones
+// them by lease. A delivery whose attempt is not recorded within the lease,
+// e.g. because the server stopped, becomes due again.
+func (wdb *WebhookDB) Claim(limit int, lease time.Duration) ([]WebhookDelivery, error) {
+	rows, err := wdb.conn.Query(`
+		WITH due AS (
+			SELECT d.id FROM webhook_deliveries d
+			JOIN webhooks w ON w.id = d.webhook_id
+			WHERE d.status = 'pending' AND d.next_attempt_at <= NOW() AND w.enabled
+			ORDER BY d.next_attempt_at
+			LIMIT $1
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d SET next_attempt_at = NOW() + $2 * INTERVAL '1 second'
+		FROM due, webhooks w
+		WHERE d.id = due.id AND w.id = d.webhook_id
+		RETURNING d.id, d.webhook_id, d.event_id, d.event, d.payload, d.attempts, d.created_at, w.url, w.secret
+	`, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []WebhookDelivery
+	for rows.Next() {
+		delivery := WebhookDelivery{Status: DeliveryPending}
+		var payload []byte
+		err := rows.Scan(&delivery.ID, &delivery.WebhookID, &delivery.EventID, &delivery.Event, &payload,
+			&delivery.Attempts, &delivery.CreatedAt, &delivery.URL, &delivery.Secret)
+		if err != nil {
+			return nil, err
+		}
+		delivery.Payload = payload
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+// RecordAttempt stores the outcome of one attempt. status is pending with the
+// time of the next attempt, delivered or failed. A zero responseStatus means
+// no response was received.
+func (wdb *WebhookDB) RecordAttempt(id int64, status string, responseStatus int, lastError string, nextAttempt time.Time) (WebhookDelivery, error) {
+	delivery, err := scanDelivery(wdb.conn.QueryRow(`
+		UPDATE webhook_deliveries
+		SET status = $2::text, attempts = attempts + 1, response_status = NULLIF($3, 0), last_error = $4,
+		    last_attempt_at = NOW(), next_attempt_at = $5,
+		    delivered_at = CASE WHEN $2::text = 'delivered' THEN NOW() END
+		WHERE id = $1 AND status = 'pending'
+		RETURNING `+deliveryColumns,
+		id, status, responseStatus, lastError, nextAttempt))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return WebhookDelivery{}, ErrUnknownDelivery
+		}
+		return WebhookDelivery{}, err
+	}
+	return delivery, nil
+}
+
+const deliveryColumns = `id, webhook_id, event_id, event, payload, status, attempts, next_attempt_at, response_status,
+	last_error, last_attempt_at, delivered_at, created_at`
+
+func scanDelivery(row interface{ Scan(...any) error }) (WebhookDelivery, error) {
+	var delivery WebhookDelivery
+	var payload []byte
+	var nextAttempt time.Time
+	err := row.Scan(&delivery.ID, &delivery.WebhookID, &delivery.EventID, &delivery.Event, &payload,
+		&delivery.Status, &delivery.Attempts, &nextAttempt, &delivery.ResponseStatus, &delivery.LastError,
+		&delivery.LastAttemptAt, &delivery.DeliveredAt, &delivery.CreatedAt)
+	delivery.Payload = payload
+	if delivery.Status == DeliveryPending {
+		delivery.NextAttemptAt = &nextAttempt
+	}
+	return delivery, err
+}
+
+// Deliveries returns up to limit deliveries of a webhook, newest first. A
+// non-empty status limits them to that status.
+func (wdb *WebhookDB) Deliveries(webhookID int64, status string, limit int) ([]WebhookDelivery, error) {
+	rows, err := wdb.conn.Query(`
+		SELECT `+deliveryColumns+` FROM webhook_deliveries
+		WHERE webhook_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3
+	`, webhookID, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+// Redeliver queues a failed delivery of a webhook again with a fresh attempt
+// budget.
+func (wdb *WebhookDB) Redeliver(webhookID int64, id int64) (WebhookDelivery, error) {
+	tx, err := wdb.conn.Begin()
+	if err != nil {
+		return WebhookDelivery{}, err
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.QueryRow(`SELECT status FROM webhook_deliveries WHERE webhook_id = $1 AND id = $2 FOR UPDATE`,
+		webhookID, id).Scan(&status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return WebhookDelivery{}, ErrUnknownDelivery
+		}
+		return WebhookDelivery{}, err
+	}
+	if status != DeliveryFailed {
+		return WebhookDelivery{}, ErrDeliveryNotFailed
+	}
+
+	delivery, err := scanDelivery(tx.QueryRow(`
+		UPDATE webhook_deliveries SET status = 'pending', attempts = 0, next_attempt_at = NOW()
+		WHERE id = $1
+		RETURNING `+deliveryColumns, id))
+	if err != nil {
+		return WebhookDelivery{}, err
+	}
+
+	return delivery, tx.Commit()
+}
+
+// PurgeDeliveries removes delivered and failed deliveries older than
+// retention and returns how many were removed.
+func (wdb *WebhookDB) PurgeDeliveries(retention time.Duration) (int64, error) {
+	res, err := wdb.conn.Exec(`
+		DELETE FROM webhook_deliveries
+		WHERE status <> 'pending' AND created_at < $1
+	`, time.Now().UTC().Add(-retention))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (wdb *WebhookDB) Close() error {
+	if wdb.conn != nil {
+		return wdb.conn.Close()
+	}
+	return nil
+}
+
+func (wdb *WebhookDB) Ping() error {
+	return wdb.conn.Ping()
+}
@@ -0,0 +1,227 @@
+package grpcapi
+
+import (
+	"RESTCryptoServer/internal/crypto"
+	"RESTCryptoServer/internal/db"
+	"RESTCryptoServer/internal/grpcapi/cryptov1"
+	"RESTCryptoServer/internal/stream"
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type cryptoServer struct {
+	cryptov1.UnimplementedCryptoServiceServer
+
+	cryptoService *crypto.CryptoService
+	hub           *stream.Hub
+	limit         int
+}
+
+// timestamp converts t, leaving zero times unset.
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// requestTime reads an optional time of a request; nil is the zero time.
+func requestTime(t *timestamppb.Timestamp) (time.Time, error) {
+	if t == nil {
+		return time.Time{}, nil
+	}
+	if err := t.CheckValid(); err != nil {
+		return time.Time{}, crypto.ErrInvalidTimeRange
+	}
+	return t.AsTime(), nil
+}
+
+func marketMessage(market db.MarketData) *cryptov1.MarketData {
+	return &cryptov1.MarketData{
+		MarketCap:              market.MarketCap,
+		Volume_24H:             market.Volume24h,
+		High_24H:               market.High24h,
+		Low_24H:                market.Low24h,
+		PriceChangePercent_24H: market.PriceChangePercent24h,
+	}
+}
+
+func backfillJobMessage(job *crypto.BackfillJob) *cryptov1.BackfillJob {
+	if job == nil {
+		return nil
+	}
+	return &cryptov1.BackfillJob{
+		Id:             job.ID,
+		Symbol:         job.Symbol,
+		CoinId:         job.CoinID,
+		Days:           int32(job.Days),
+		Status:         string(job.Status),
+		PointsFetched:  int32(job.PointsFetched),
+		PointsInserted: int32(job.PointsInserted),
+		Error:          job.Error,
+		CreatedAt:      timestamp(job.CreatedAt),
+		UpdatedAt:      timestamp(job.UpdatedAt),
+	}
+}
+
+func cryptoMessage(resp *crypto.CryptoResponse) *cryptov1.Crypto {
+	return &cryptov1.Crypto{
+		Symbol:       resp.Symbol,
+		CoinId:       resp.CoinID,
+		Name:         resp.Name,
+		CurrentPrice: resp.CurrentPrice,
+		Currency:     resp.Currency,
+		Prices:       resp.Prices,
+		Market:       marketMessage(resp.MarketData),
+		LastUpdated:  timestamp(resp.LastUpdated),
+		Stale:        resp.Stale,
+		AgeSeconds:   resp.AgeSeconds,
+		BackfillJob:  backfillJobMessage(resp.BackfillJob),
+	}
+}
+
+func (s *cryptoServer) ListCryptos(_ context.Context, req *cryptov1.ListCryptosRequest) (*cryptov1.ListCryptosResponse, error) {
+	list, err := s.cryptoService.GetAllCryptos(req.GetVs(), req.GetSort(), req.GetOrder())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	resp := &cryptov1.ListCryptosResponse{
+		Cryptos: make([]*cryptov1.Crypto, len(list.Cryptos)),
+	}
+	for i := range list.Cryptos {
+		resp.Cryptos[i] = cryptoMessage(&list.Cryptos[i])
+	}
+	return resp, nil
+}
+
+func (s *cryptoServer) GetCrypto(_ context.Context, req *cryptov1.GetCryptoRequest) (*cryptov1.Crypto, error) {
+	resp, err := s.cryptoService.GetCrypto(req.GetSymbol(), req.GetVs())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return cryptoMessage(resp), nil
+}
+
+func (s *cryptoServer) AddCrypto(ctx context.Context, req *cryptov1.AddCryptoRequest) (*cryptov1.Crypto, error) {
+	if req.GetSymbol() == "" && req.GetCoinId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "symbol or coin_id is required")
+	}
+
+	var backfillDays int
+	if req.GetBackfillDays() != 0 {
+		var err error
+		if backfillDays, err = crypto.ParseBackfillPeriod(strconv.Itoa(int(req.GetBackfillDays()))); err != nil {
+			return nil, statusError(err)
+		}
+	}
+
+	resp, err := s.cryptoService.AddCrypto(ctx, req.GetSymbol(), req.GetCoinId(), backfillDays)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return cryptoMessage(resp), nil
+}
+
+func (s *cryptoServer) RefreshCrypto(ctx context.Context, req *cryptov1.RefreshCryptoRequest) (*cryptov1.Crypto, error) {
+	resp, err := s.cryptoService.RefreshCrypto(ctx, req.GetSymbol())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return cryptoMessage(resp), nil
+}
+
+func (s *cryptoServer) DeleteCrypto(_ context.Context, req *cryptov1.DeleteCryptoRequest) (*cryptov1.DeleteCryptoResponse, error) {
+	if err := s.cryptoService.DeleteCrypto(req.GetSymbol()); err != nil {
+		return nil, statusError(err)
+	}
+	return &cryptov1.DeleteCryptoResponse{}, nil
+}
+
+func (s *cryptoServer) GetHistory(_ context.Context, req *cryptov1.GetHistoryRequest) (*cryptov1.GetHistoryResponse, error) {
+	query := crypto.HistoryQuery{
+		Limit:    crypto.DefaultHistoryLimit,
+		Cursor:   req.GetCursor(),
+		Interval: strings.ToLower(req.GetInterval()),
+	}
+	if req.GetLimit() != 0 {
+		query.Limit = int(req.GetLimit())
+	}
+
+	var err error
+	if query.From, err = requestTime(req.GetFrom()); err != nil {
+		return nil, statusError(err)
+	}
+	if query.To, err = requestTime(req.GetTo()); err != nil {
+		return nil, statusError(err)
+	}
+
+	history, err := s.cryptoService.GetCryptoHistory(req.GetSymbol(), req.GetVs(), query)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	resp := &cryptov1.GetHistoryResponse{
+		Symbol:     history.Symbol,
+		Currency:   history.Currency,
+		Interval:   history.Interval,
+		History:    make([]*cryptov1.HistoryPoint, len(history.History)),
+		NextCursor: history.NextCursor,
+	}
+	for i, point := range history.History {
+		resp.History[i] = &cryptov1.HistoryPoint{
+			Price:     point.Price,
+			Prices:    point.Prices,
+			Market:    marketMessage(point.MarketData),
+			Timestamp: timestamp(point.Timestamp),
+		}
+	}
+	return resp, nil
+}
+
+func (s *cryptoServer) GetStats(_ context.Context, req *cryptov1.GetStatsRequest) (*cryptov1.GetStatsResponse, error) {
+	resp, err := s.cryptoService.GetCryptoStats(req.GetSymbol(), req.GetVs(), req.GetWindow())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	stats := resp.Stats
+	return &cryptov1.GetStatsResponse{
+		Symbol:       resp.Symbol,
+		Currency:     resp.Currency,
+		Window:       resp.Window,
+		CurrentPrice: resp.CurrentPrice,
+		Stats: &cryptov1.Stats{
+			MinPrice:    stats.MinPrice,
+			MaxPrice:    stats.MaxPrice,
+			AvgPrice:    stats.AvgPrice,
+			MedianPrice: stats.MedianPrice,
+			StdDev:      stats.StdDev,
+			Volatility:  stats.Volatility,
+			Percentiles: &cryptov1.PricePercentiles{
+				P5:  stats.Percentiles.P5,
+				P25: stats.Percentiles.P25,
+				P75: stats.Percentiles.P75,
+				P95: stats.Percentiles.P95,
+			},
+			PriceChange:        stats.PriceChange,
+			PriceChangePercent: stats.PriceChangePercent,
+			RecordsCount:       int32(stats.RecordsCount),
+			FirstTimestamp:     optionalTimestamp(stats.FirstTimestamp),
+			LastTimestamp:      optionalTimestamp(stats.LastTimestamp),
+		},
+	}, nil
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamp(*t)
+}
@@ -0,0 +1,2151 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: crypto/v1/crypto.proto
+
+package cryptov1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type MarketData struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	MarketCap              float64                `protobuf:"fixed64,1,opt,name=market_cap,json=marketCap,proto3" json:"market_cap,omitempty"`
+	Volume_24H             float64                `protobuf:"fixed64,2,opt,name=volume_24h,json=volume24h,proto3" json:"volume_24h,omitempty"`
+	High_24H               float64                `protobuf:"fixed64,3,opt,name=high_24h,json=high24h,proto3" json:"high_24h,omitempty"`
+	Low_24H                float64                `protobuf:"fixed64,4,opt,name=low_24h,json=low24h,proto3" json:"low_24h,omitempty"`
+	PriceChangePercent_24H float64                `protobuf:"fixed64,5,opt,name=price_change_percent_24h,json=priceChangePercent24h,proto3" json:"price_change_percent_24h,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *MarketData) Reset() {
+	*x = MarketData{}
+	mi := &file_crypto_v1_crypto_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarketData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarketData) ProtoMessage() {}
+
+func (x *MarketData) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_v1_crypto_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarketData.ProtoReflect.Descriptor instead.
+func (*MarketData) Descriptor() ([]byte, []int) {
+	return file_crypto_v1_crypto_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *MarketData) GetMarketCap() float64 {
+	if x != nil {
+		return x.MarketCap
+	}
+	return 0
+}
+
+func (x *MarketData) GetVolume_24H() float64 {
+	if x != nil {
+		return x.Volume_24H
+	}
+	return 0
+}
+
+func (x *MarketData) GetHigh_24H() float64 {
+	if x != nil {
+		return x.High_24H
+	}
+	return 0
+}
+
+func (x *MarketData) GetLow_24H() float64 {
+	if x != nil {
+		return x.Low_24H
+	}
+	return 0
+}
+
+func (x *MarketData) GetPriceChangePercent_24H() float64 {
+	if x != nil {
+		return x.PriceChangePercent_24H
+	}
+	return 0
+}
+
+type BackfillJob struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Symbol string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	CoinId string                 `protobuf:"bytes,3,opt,name=coin_id,json=coinId,proto3" json:"coin_id,omitempty"`
+	Days   int32                  `protobuf:"varint,4,opt,name=days,proto3" json:"days,omitempty"`
+	// pending, running, completed or failed.
+	Status         string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	PointsFetched  int32                  `protobuf:"varint,6,opt,name=points_fetched,json=pointsFetched,proto3" json:"points_fetched,omitempty"`
+	PointsInserted int32                  `protobuf:"varint,7,opt,name=points_inserted,json=pointsInserted,proto3" json:"points_inserted,omitempty"`
+	Error          string                 `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BackfillJob) Reset() {
+	*x = BackfillJob{}
+	mi := &file_crypto_v1_crypto_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackfillJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackfillJob) ProtoMessage() {}
+
+func (x *BackfillJob) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_v1_crypto_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackfillJob.ProtoReflect.Descriptor instead.
+func (*BackfillJob) Descriptor() ([]byte, []int) {
+	return file_crypto_v1_crypto_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BackfillJob) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BackfillJob) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *BackfillJob) GetCoinId() string {
+	if x != nil {
+		return x.CoinId
+	}
+	return ""
+}
+
+func (x *BackfillJob) GetDays() int32 {
+	if x != nil {
+		return x.Days
+	}
+	return 0
+}
+
+func (x *BackfillJob) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *BackfillJob) GetPointsFetched() int32 {
+	if x != nil {
+		return x.PointsFetched
+	}
+	return 0
+}
+
+func (x *BackfillJob) GetPointsInserted() int32 {
+	if x != nil {
+		return x.PointsInserted
+	}
+	return 0
+}
+
+func (x *BackfillJob) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *BackfillJob) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *BackfillJob) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type Crypto struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Symbol string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	CoinId string                 `protobuf:"bytes,2,opt,name=coin_id,json=coinId,proto3" json:"coin_id,omitempty"`
+	Name   string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	// Price in currency.
+	CurrentPrice float64 `protobuf:"fixed64,4,opt,name=current_price,json=currentPrice,proto3" json:"current_price,omitempty"`
+	Currency     string  `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	// Price in every quote currency.
+	Prices      map[string]float64     `protobuf:"bytes,6,rep,name=prices,proto3" json:"prices,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	Market      *MarketData            `protobuf:"bytes,7,opt,name=market,proto3" json:"market,omitempty"`
+	LastUpdated *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+	// Set when the provider failed and the last known price is served.
+	Stale      bool  `protobuf:"varint,9,opt,name=stale,proto3" json:"stale,omitempty"`
+	AgeSeconds int64 `protobuf:"varint,10,opt,name=age_seconds,json=ageSeconds,proto3" json:"age_seconds,omitempty"`
+	// Only set by AddCrypto with backfill_days.
+	BackfillJob   *BackfillJob `protobuf:"bytes,11,opt,name=backfill_job,json=backfillJob,proto3" json:"backfill_job,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Crypto) Reset() {
+	*x = Crypto{}
+	mi := &file_crypto_v1_crypto_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Crypto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Crypto) ProtoMessage() {}
+
+func (x *Crypto) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_v1_crypto_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Crypto.ProtoReflect.Descriptor instead.
+func (*Crypto) Descriptor() ([]byte, []int) {
+	return file_crypto_v1_crypto_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Crypto) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Crypto) GetCoinId() string {
+	if x != nil {
+		return x.CoinId
+	}
+	return ""
+}
+
+func (x *Crypto) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Crypto) GetCurrentPrice() float64 {
+	if x != nil {
+		return x.CurrentPrice
+	}
+	return 0
+}
+
+func (x *Crypto) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Crypto) GetPrices() map[string]float64 {
+	if x != nil {
+		return x.Prices
+	}
+	return nil
+}
+
+func (x *Crypto) GetMarket() *MarketData {
+	if x != nil {
+		return x.Market
+	}
+	return nil
+}
+
+func (x *Crypto) GetLastUpdated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUpdated
+	}
+	return nil
+}
+
+func (x *Crypto) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *Crypto) GetAgeSeconds() int64 {
+	if x != nil {
+		return x.AgeSeconds
+	}
+	return 0
+}
+
+func (x *Crypto) GetBackfillJob() *BackfillJob {
+	if x != nil {
+		return x.BackfillJob
+	}
+	return nil
+}
+
+type ListCryptosRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Quote currency, default the first of QUOTE_CURRENCIES.
+	Vs string `protobuf:"bytes,1,opt,name=vs,proto3" json:"vs,omitempty"`
+	// symbol, name, current_price, market_cap, volume_24h, high_24h, low_24h
+	// or price_change_percent_24h.
+	Sort string `protobuf:"bytes,2,opt,name=sort,proto3" json:"sort,omitempty"`
+	// asc or desc.
+	Order         string `protobuf:"bytes,3,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCryptosRequest) Reset() {
+	*x = ListCryptosRequest{}
+	mi := &file_crypto_v1_crypto_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCryptosRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCryptosRequest) ProtoMessage() {}
+
+func (x *ListCryptosRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_v1_crypto_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCryptosRequest.ProtoReflect.Descriptor instead.
+func (*ListCryptosRequest) Descriptor() ([]byte, []int) {
+	return file_crypto_v1_crypto_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListCryptosRequest) GetVs() string {
+	if x != nil {
+		return x.Vs
+	}
+	return ""
+}
+
+func (x *ListCryptosRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListCryptosRequest) GetOrder() string {
+	if x != nil {
+		return x.Order
+	}
+	return ""
+}
+
+type ListCryptosResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cryptos       []*Crypto              `protobuf:"bytes,1,rep,name=cryptos,proto3" json:"cryptos,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCryptosResponse) Reset() {
+	*x = ListCryptosResponse{}
+	mi := &file_crypto_v1_crypto_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCryptosResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCryptosResponse) ProtoMessage() {}
+
+func (x *ListCryptosResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_v1_crypto_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCryptosResponse.ProtoReflect.Descriptor instead.
+func (*ListCryptosResponse) Descriptor() ([]byte, []int) {
+	return file_crypto_v1_crypto_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListCryptosResponse) GetCryptos() []*Crypto {
+	if x != nil {
+		return x.Cryptos
+	}
+	return nil
+}
+
+type GetCryptoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Vs            string                 `protobuf:"bytes,2,opt,name=vs,proto3" json:"vs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCryptoRequest) Reset() {
+	*x = GetCryptoRequest{}
+	mi := &file_crypto_v1_crypto_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCryptoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCryptoRequest) ProtoMessage() {}
+
+func (x *GetCryptoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_v1_crypto_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCryptoRequest.ProtoReflect.Descriptor instead.
+func (*GetCryptoRequest) Descriptor() ([]byte, []int) {
+	return file_crypto_v1_crypto_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetCryptoRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetCryptoRequest) GetVs() string {
+	if x != nil {
+		return x.Vs
+	}
+	return ""
+}
+
+// AddCryptoRequest needs symbol, coin_id or both.
+type AddCryptoRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Symbol string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	CoinId string                 `protobuf:"bytes,2,opt,name=coin_id,json=coinId,proto3" json:"coin_id,omitempty"`
+	// Days of history to load in the background, up to 365.
+	BackfillDays  int32 `protobuf:"varint,3,opt,name=backfill_days,json=backfillDays,proto3" json:"backfill_days,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddCryptoRequest) Reset() {
+	*x = AddCryptoRequest{}
+	mi := &file_crypto_v1_crypto_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddCryptoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddCryptoRequest) ProtoMessage() {}
+
+func (x *AddCryptoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_v1_crypto_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddCryptoRequest.ProtoReflect.Descriptor instead.
+func (*AddCryptoRequest) Descriptor() ([]byte, []int) {
+	return file_crypto_v1_crypto_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AddCryptoRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *AddCryptoRequest) GetCoinId() string {
+	if x != nil {
+		return x.CoinId
+	}
+	return ""
+}
+
+func (x *AddCryptoRequest) GetBackfillDays() int32 {
+	if x != nil {
+		return x.BackfillDays
+	}
+	return 0
+}
+
+type RefreshCryptoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefreshCryptoRequest) Reset() {
+	*x = RefreshCryptoRequest{}
+	mi := &file_crypto_v1_crypto_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshCryptoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshCryptoRequest) ProtoMessage() {}
+
+func (x *RefreshCryptoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_v1_crypto_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshCryptoRequest.ProtoReflect.Descriptor instead.
+func (*RefreshCryptoRequest) Descriptor() ([]byte, []int) {
+	return file_crypto_v1_crypto_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RefreshCryptoRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+type DeleteCryptoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCryptoRequest) Reset() {
+	*x = DeleteCryptoRequest{}
+	mi := &file_crypto_v1_crypto_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCryptoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCryptoRequest) ProtoMessage() {}
+
+func (x *DeleteCryptoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_v1_crypto_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCryptoRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCryptoRequest) Descriptor() ([]byte, []int) {
+	return file_crypto_v1_crypto_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeleteCryptoRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+type DeleteCryptoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCryptoResponse) Reset() {
+	*x = DeleteCryptoResponse{}
+	mi := &file_crypto_v1_crypto_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCryptoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCryptoResponse) ProtoMessage() {}
+
+func (x *DeleteCryptoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_v1_crypto_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCryptoResponse.ProtoReflect.Descriptor instead.
+func (*DeleteCryptoResponse) Descriptor() ([]byte, []int) {
+	return file_crypto_v1_crypto_proto_rawDescGZIP(), []int{9}
+}
+
+type GetHistoryRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Symbol string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Vs     string                 `protobuf:"bytes,2,opt,name=vs,proto3" json:"vs,omitempty"`
+	From   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	// Default 100, at most 1000.
+	Limit int32 `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	// next_cursor of the previous page.
+	Cursor string `protobuf:"bytes,6,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// raw, 1m, 5m, 1h or 1d.
+	Interval      string `protobuf:"bytes,7,opt,name=interval,proto3" json:"interval,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHistoryRequest) Reset() {
+	*x = GetHistoryRequest{}
+	mi := &file_crypto_v1_crypto_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHistoryRequest) ProtoMessage() {}
+
+func (x *GetHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_v1_crypto_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_crypto_v1_crypto_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetHistoryRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetHistoryRequest) GetVs() string {
+	if x != nil {
+		return x.Vs
+	}
+	return ""
+}
+
+func (x *GetHistoryRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *GetHistoryRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *GetHistoryRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetHistoryRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *GetHistoryRequest) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+type HistoryPoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Price         float64                `protobuf:"fixed64,1,opt,name=price,proto3" json:"price,omitempty"`
+	Prices        map[string]float64     `protobuf:"bytes,2,rep,name=prices,proto3" json:"prices,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	Market        *MarketData            `protobuf:"bytes,3,opt,name=market,proto3" json:"market,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryPoint) Reset() {
+	*x = HistoryPoint{}
+	mi := &file_crypto_v1_crypto_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryPoint) ProtoMessage() {}
+
+func (x *HistoryPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_v1_crypto_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryPoint.ProtoReflect.Descriptor instead.
+func (*HistoryPoint) Descriptor() ([]byte, []int) {
+	return file_crypto_v1_crypto_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *HistoryPoint) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *HistoryPoint) GetPrices() map[string]float64 {
+	if x != nil {
+		return x.Prices
+	}
+	return nil
+}
+
+func (x *HistoryPoint) GetMarket() *MarketData {
+	if x != nil {
+		return x.Market
+	}
+	return nil
+}
+
+func (x *HistoryPoint) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type GetHistoryResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Symbol   string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Currency string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	Interval string                 `protobuf:"bytes,3,opt,name=interval,proto3" json:"interval,omitempty"`
+	// Newest first.
+	History []*HistoryPoint `protobuf:"bytes,4,rep,name=history,proto3" json:"history,omitempty"`
+	// Empty on the last page.
+	NextCursor    string `protobuf:"bytes,5,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHistoryResponse) Reset() {
+	*x = GetHistoryResponse{}
+	mi := &file_crypto_v1_crypto_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHistoryResponse) ProtoMessage() {}
+
+func (x *GetHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_v1_crypto_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_crypto_v1_crypto_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetHistoryResponse) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetHistoryResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *GetHistoryResponse) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *GetHistoryResponse) GetHistory() []*HistoryPoint {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+func (x *GetHistoryResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type GetStatsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Symbol string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Vs     string                 `protobuf:"bytes,2,opt,name=vs,proto3" json:"vs,omitempty"`
+	// 1h, 24h, 7d or 30d, default 24h.
+	Window        string `protobuf:"bytes,3,opt,name=window,proto3" json:"window,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsRequest) Reset() {
+	*x = GetStatsRequest{}
+	mi := &file_crypto_v1_crypto_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsRequest) ProtoMessage() {}
+
+func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_v1_crypto_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+	return file_crypto_v1_crypto_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetStatsRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetStatsRequest) GetVs() string {
+	if x != nil {
+		return x.Vs
+	}
+	return ""
+}
+
+func (x *GetStatsRequest) GetWindow() string {
+	if x != nil {
+		return x.Window
+	}
+	return ""
+}
+
+type PricePercentiles struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	P5            float64                `protobuf:"fixed64,1,opt,name=p5,proto3" json:"p5,omitempty"`
+	P25           float64                `protobuf:"fixed64,2,opt,name=p25,proto3" json:"p25,omitempty"`
+	P75           float64                `protobuf:"fixed64,3,opt,name=p75,proto3" json:"p75,omitempty"`
+	P95           float64                `protobuf:"fixed64,4,opt,name=p95,proto3" json:"p95,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PricePercentiles) Reset() {
+	*x = PricePercentiles{}
+	mi := &file_crypto_v1_crypto_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PricePercentiles) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PricePercentiles) ProtoMessage() {}
+
+func (x *PricePercentiles) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_v1_crypto_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PricePercentiles.ProtoReflect.Descriptor instead.
+func (*PricePercentiles) Descriptor() ([]byte, []int) {
+	return file_crypto_v1_crypto_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *PricePercentiles) GetP5() float64 {
+	if x != nil {
+		return x.P5
+	}
+	return 0
+}
+
+func (x *PricePercentiles) GetP25() float64 {
+	if x != nil {
+		return x.P25
+	}
+	return 0
+}
+
+func (x *PricePercentiles) GetP75() float64 {
+	if x != nil {
+		return x.P75
+	}
+	return 0
+}
+
+func (x *PricePercentiles) GetP95() float64 {
+	if x != nil {
+		return x.P95
+	}
+	return 0
+}
+
+type Stats struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	MinPrice           float64                `protobuf:"fixed64,1,opt,name=min_price,json=minPrice,proto3" json:"min_price,omitempty"`
+	MaxPrice           float64                `protobuf:"fixed64,2,opt,name=max_price,json=maxPrice,proto3" json:"max_price,omitempty"`
+	AvgPrice           float64                `protobuf:"fixed64,3,opt,name=avg_price,json=avgPrice,proto3" json:"avg_price,omitempty"`
+	MedianPrice        float64                `protobuf:"fixed64,4,opt,name=median_price,json=medianPrice,proto3" json:"median_price,omitempty"`
+	StdDev             float64                `protobuf:"fixed64,5,opt,name=std_dev,json=stdDev,proto3" json:"std_dev,omitempty"`
+	Volatility         float64                `protobuf:"fixed64,6,opt,name=volatility,proto3" json:"volatility,omitempty"`
+	Percentiles        *PricePercentiles      `protobuf:"bytes,7,opt,name=percentiles,proto3" json:"percentiles,omitempty"`
+	PriceChange        float64                `protobuf:"fixed64,8,opt,name=price_change,json=priceChange,proto3" json:"price_change,omitempty"`
+	PriceChangePercent float64                `protobuf:"fixed64,9,opt,name=price_change_percent,json=priceChangePercent,proto3" json:"price_change_percent,omitempty"`
+	RecordsCount       int32                  `protobuf:"varint,10,opt,name=records_count,json=recordsCount,proto3" json:"records_count,omitempty"`
+	FirstTimestamp     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=first_timestamp,json=firstTimestamp,proto3" json:"first_timestamp,omitempty"`
+	LastTimestamp      *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=last_timestamp,json=lastTimestamp,proto3" json:"last_timestamp,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Stats) Reset() {
+	*x = Stats{}
+	mi := &file_crypto_v1_crypto_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Stats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stats) ProtoMessage() {}
+
+func (x *Stats) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_v1_crypto_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stats.ProtoReflect.Descriptor instead.
+func (*Stats) Descriptor() ([]byte, []int) {
+	return file_crypto_v1_crypto_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Stats) GetMinPrice() float64 {
+	if x != nil {
+		return x.MinPrice
+	}
+	return 0
+}
+
+func (x *Stats) GetMaxPrice() float64 {
+	if x != nil {
+		return x.MaxPrice
+	}
+	return 0
+}
+
+func (x *Stats) GetAvgPrice() float64 {
+	if x != nil {
+		return x.AvgPrice
+	}
+	return 0
+}
+
+func (x *Stats) GetMedianPrice() float64 {
+	if x != nil {
+		return x.MedianPrice
+	}
+	return 0
+}
+
+func (x *Stats) GetStdDev() float64 {
+	if x != nil {
+		return x.StdDev
+	}
+	return 0
+}
+
+func (x *Stats) GetVolatility() float64 {
+	if x != nil {
+		return x.Volatility
+	}
+	return 0
+}
+
+func (x *Stats) GetPercentiles() *PricePercentiles {
+	if x != nil {
+		return x.Percentiles
+	}
+	return nil
+}
+
+func (x *Stats) GetPriceChange() float64 {
+	if x != nil {
+		return x.PriceChange
+	}
+	return 0
+}
+
+func (x *Stats) GetPriceChangePercent() float64 {
+	if x != nil {
+		return x.PriceChangePercent
+	}
+	return 0
+}
+
+func (x *Stats) GetRecordsCount() int32 {
+	if x != nil {
+		return x.RecordsCount
+	}
+	return 0
+}
+
+func (x *Stats) GetFirstTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FirstTimestamp
+	}
+	return nil
+}
+
+func (x *Stats) GetLastTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastTimestamp
+	}
+	return nil
+}
+
+type GetStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	Window        string                 `protobuf:"bytes,3,opt,name=window,proto3" json:"window,omitempty"`
+	CurrentPrice  float64                `protobuf:"fixed64,4,opt,name=current_price,json=currentPrice,proto3" json:"current_price,omitempty"`
+	Stats         *Stats                 `protobuf:"bytes,5,opt,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsResponse) Reset() {
+	*x = GetStatsResponse{}
+	mi := &file_crypto_v1_crypto_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsResponse) ProtoMessage() {}
+
+func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_v1_crypto_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetStatsResponse) Descriptor() ([]byte, []int) {
+	return file_crypto_v1_crypto_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetStatsResponse) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetStatsResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *GetStatsResponse) GetWindow() string {
+	if x != nil {
+		return x.Window
+	}
+	return ""
+}
+
+func (x *GetStatsResponse) GetCurrentPrice() float64 {
+	if x != nil {
+		return x.CurrentPrice
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetStats() *Stats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+type WatchPricesRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Symbols []string               `protobuf:"bytes,1,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	// Id of the last tick received on a previous stream. The buffered ticks
+	// after it are sent first.
+	AfterId       int64 `protobuf:"varint,2,opt,name=after_id,json=afterId,proto3" json:"after_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchPricesRequest) Reset() {
+	*x = WatchPricesRequest{}
+	mi := &file_crypto_v1_crypto_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchPricesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchPricesRequest) ProtoMessage() {}
+
+func (x *WatchPricesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_v1_crypto_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchPricesRequest.ProtoReflect.Descriptor instead.
+func (*WatchPricesRequest) Descriptor() ([]byte, []int) {
+	return file_crypto_v1_crypto_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *WatchPricesRequest) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+func (x *WatchPricesRequest) GetAfterId() int64 {
+	if x != nil {
+		return x.AfterId
+	}
+	return 0
+}
+
+// PriceTick is one price write, with an id that is the same on every server.
+type PriceTick struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Price         float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
+	Prices        map[string]float64     `protobuf:"bytes,5,rep,name=prices,proto3" json:"prices,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	Market        *MarketData            `protobuf:"bytes,6,opt,name=market,proto3" json:"market,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceTick) Reset() {
+	*x = PriceTick{}
+	mi := &file_crypto_v1_crypto_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceTick) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceTick) ProtoMessage() {}
+
+func (x *PriceTick) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_v1_crypto_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceTick.ProtoReflect.Descriptor instead.
+func (*PriceTick) Descriptor() ([]byte, []int) {
+	return file_crypto_v1_crypto_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PriceTick) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *PriceTick) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *PriceTick) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PriceTick) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *PriceTick) GetPrices() map[string]float64 {
+	if x != nil {
+		return x.Prices
+	}
+	return nil
+}
+
+func (x *PriceTick) GetMarket() *MarketData {
+	if x != nil {
+		return x.Market
+	}
+	return nil
+}
+
+func (x *PriceTick) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+// Lagged tells that skipped updates were replaced by newer ones because the
+// client did not keep up.
+type Lagged struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Skipped       int32                  `protobuf:"varint,1,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Lagged) Reset() {
+	*x = Lagged{}
+	mi := &file_crypto_v1_crypto_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Lagged) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Lagged) ProtoMessage() {}
+
+func (x *Lagged) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_v1_crypto_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Lagged.ProtoReflect.Descriptor instead.
+func (*Lagged) Descriptor() ([]byte, []int) {
+	return file_crypto_v1_crypto_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Lagged) GetSkipped() int32 {
+	if x != nil {
+		return x.Skipped
+	}
+	return 0
+}
+
+// Resync tells that some ticks after after_id are no longer buffered, so
+// prices should be reloaded with GetCrypto or ListCryptos.
+type Resync struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AfterId       int64                  `protobuf:"varint,1,opt,name=after_id,json=afterId,proto3" json:"after_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Resync) Reset() {
+	*x = Resync{}
+	mi := &file_crypto_v1_crypto_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Resync) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Resync) ProtoMessage() {}
+
+func (x *Resync) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_v1_crypto_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Resync.ProtoReflect.Descriptor instead.
+func (*Resync) Descriptor() ([]byte, []int) {
+	return file_crypto_v1_crypto_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Resync) GetAfterId() int64 {
+	if x != nil {
+		return x.AfterId
+	}
+	return 0
+}
+
+type WatchPricesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*WatchPricesResponse_Tick
+	//	*WatchPricesResponse_Lagged
+	//	*WatchPricesResponse_Resync
+	Event         isWatchPricesResponse_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchPricesResponse) Reset() {
+	*x = WatchPricesResponse{}
+	mi := &file_crypto_v1_crypto_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchPricesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchPricesResponse) ProtoMessage() {}
+
+func (x *WatchPricesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_v1_crypto_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchPricesResponse.ProtoReflect.Descriptor instead.
+func (*WatchPricesResponse) Descriptor() ([]byte, []int) {
+	return file_crypto_v1_crypto_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *WatchPricesResponse) GetEvent() isWatchPricesResponse_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *WatchPricesResponse) GetTick() *PriceTick {
+	if x != nil {
+		if x, ok := x.Event.(*WatchPricesResponse_Tick); ok {
+			return x.Tick
+		}
+	}
+	return nil
+}
+
+func (x *WatchPricesResponse) GetLagged() *Lagged {
+	if x != nil {
+		if x, ok := x.Event.(*WatchPricesResponse_Lagged); ok {
+			return x.Lagged
+		}
+	}
+	return nil
+}
+
+func (x *WatchPricesResponse) GetResync() *Resync {
+	if x != nil {
+		if x, ok := x.Event.(*WatchPricesResponse_Resync); ok {
+			return x.Resync
+		}
+	}
+	return nil
+}
+
+type isWatchPricesResponse_Event interface {
+	isWatchPricesResponse_Event()
+}
+
+type WatchPricesResponse_Tick struct {
+	Tick *PriceTick `protobuf:"bytes,1,opt,name=tick,proto3,oneof"`
+}
+
+type WatchPricesResponse_Lagged struct {
+	Lagged *Lagged `protobuf:"bytes,2,opt,name=lagged,proto3,oneof"`
+}
+
+type WatchPricesResponse_Resync struct {
+	Resync *Resync `protobuf:"bytes,3,opt,name=resync,proto3,oneof"`
+}
+
+func (*WatchPricesResponse_Tick) isWatchPricesResponse_Event() {}
+
+func (*WatchPricesResponse_Lagged) isWatchPricesResponse_Event() {}
+
+func (*WatchPricesResponse_Resync) isWatchPricesResponse_Event() {}
+
+type GetScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetScheduleRequest) Reset() {
+	*x = GetScheduleRequest{}
+	mi := &file_crypto_v1_crypto_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetScheduleRequest) ProtoMessage() {}
+
+func (x *GetScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_v1_crypto_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetScheduleRequest.ProtoReflect.Descriptor instead.
+func (*GetScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_crypto_v1_crypto_proto_rawDescGZIP(), []int{22}
+}
+
+type Schedule struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Enabled         bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	IntervalSeconds int32                  `protobuf:"varint,2,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"`
+	LastUpdate      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=last_update,json=lastUpdate,proto3" json:"last_update,omitempty"`
+	NextUpdate      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=next_update,json=nextUpdate,proto3" json:"next_update,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Schedule) Reset() {
+	*x = Schedule{}
+	mi := &file_crypto_v1_crypto_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Schedule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Schedule) ProtoMessage() {}
+
+func (x *Schedule) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_v1_crypto_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Schedule.ProtoReflect.Descriptor instead.
+func (*Schedule) Descriptor() ([]byte, []int) {
+	return file_crypto_v1_crypto_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Schedule) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *Schedule) GetIntervalSeconds() int32 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+func (x *Schedule) GetLastUpdate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUpdate
+	}
+	return nil
+}
+
+func (x *Schedule) GetNextUpdate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextUpdate
+	}
+	return nil
+}
+
+type UpdateScheduleRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Enabled bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// 10 to 3600.
+	IntervalSeconds int32 `protobuf:"varint,2,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdateScheduleRequest) Reset() {
+	*x = UpdateScheduleRequest{}
+	mi := &file_crypto_v1_crypto_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateScheduleRequest) ProtoMessage() {}
+
+func (x *UpdateScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_v1_crypto_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateScheduleRequest.ProtoReflect.Descriptor instead.
+func (*UpdateScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_crypto_v1_crypto_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *UpdateScheduleRequest) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *UpdateScheduleRequest) GetIntervalSeconds() int32 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+type TriggerUpdateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriggerUpdateRequest) Reset() {
+	*x = TriggerUpdateRequest{}
+	mi := &file_crypto_v1_crypto_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriggerUpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriggerUpdateRequest) ProtoMessage() {}
+
+func (x *TriggerUpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_v1_crypto_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriggerUpdateRequest.ProtoReflect.Descriptor instead.
+func (*TriggerUpdateRequest) Descriptor() ([]byte, []int) {
+	return file_crypto_v1_crypto_proto_rawDescGZIP(), []int{25}
+}
+
+type TriggerUpdateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UpdatedCount  int32                  `protobuf:"varint,1,opt,name=updated_count,json=updatedCount,proto3" json:"updated_count,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriggerUpdateResponse) Reset() {
+	*x = TriggerUpdateResponse{}
+	mi := &file_crypto_v1_crypto_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriggerUpdateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriggerUpdateResponse) ProtoMessage() {}
+
+func (x *TriggerUpdateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crypto_v1_crypto_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriggerUpdateResponse.ProtoReflect.Descriptor instead.
+func (*TriggerUpdateResponse) Descriptor() ([]byte, []int) {
+	return file_crypto_v1_crypto_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *TriggerUpdateResponse) GetUpdatedCount() int32 {
+	if x != nil {
+		return x.UpdatedCount
+	}
+	return 0
+}
+
+func (x *TriggerUpdateResponse) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+var File_crypto_v1_crypto_proto protoreflect.FileDescriptor
+
+const file_crypto_v1_crypto_proto_rawDesc = "" +
+	"\n" +
+	"\x16crypto/v1/crypto.proto\x12\tcrypto.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb7\x01\n" +
+	"\n" +
+	"MarketData\x12\x1d\n" +
+	"\n" +
+	"market_cap\x18\x01 \x01(\x01R\tmarketCap\x12\x1d\n" +
+	"\n" +
+	"volume_24h\x18\x02 \x01(\x01R\tvolume24h\x12\x19\n" +
+	"\bhigh_24h\x18\x03 \x01(\x01R\ahigh24h\x12\x17\n" +
+	"\alow_24h\x18\x04 \x01(\x01R\x06low24h\x127\n" +
+	"\x18price_change_percent_24h\x18\x05 \x01(\x01R\x15priceChangePercent24h\"\xd6\x02\n" +
+	"\vBackfillJob\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12\x17\n" +
+	"\acoin_id\x18\x03 \x01(\tR\x06coinId\x12\x12\n" +
+	"\x04days\x18\x04 \x01(\x05R\x04days\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12%\n" +
+	"\x0epoints_fetched\x18\x06 \x01(\x05R\rpointsFetched\x12'\n" +
+	"\x0fpoints_inserted\x18\a \x01(\x05R\x0epointsInserted\x12\x14\n" +
+	"\x05error\x18\b \x01(\tR\x05error\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xe0\x03\n" +
+	"\x06Crypto\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x17\n" +
+	"\acoin_id\x18\x02 \x01(\tR\x06coinId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12#\n" +
+	"\rcurrent_price\x18\x04 \x01(\x01R\fcurrentPrice\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\x125\n" +
+	"\x06prices\x18\x06 \x03(\v2\x1d.crypto.v1.Crypto.PricesEntryR\x06prices\x12-\n" +
+	"\x06market\x18\a \x01(\v2\x15.crypto.v1.MarketDataR\x06market\x12=\n" +
+	"\flast_updated\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\vlastUpdated\x12\x14\n" +
+	"\x05stale\x18\t \x01(\bR\x05stale\x12\x1f\n" +
+	"\vage_seconds\x18\n" +
+	" \x01(\x03R\n" +
+	"ageSeconds\x129\n" +
+	"\fbackfill_job\x18\v \x01(\v2\x16.crypto.v1.BackfillJobR\vbackfillJob\x1a9\n" +
+	"\vPricesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"N\n" +
+	"\x12ListCryptosRequest\x12\x0e\n" +
+	"\x02vs\x18\x01 \x01(\tR\x02vs\x12\x12\n" +
+	"\x04sort\x18\x02 \x01(\tR\x04sort\x12\x14\n" +
+	"\x05order\x18\x03 \x01(\tR\x05order\"B\n" +
+	"\x13ListCryptosResponse\x12+\n" +
+	"\acryptos\x18\x01 \x03(\v2\x11.crypto.v1.CryptoR\acryptos\":\n" +
+	"\x10GetCryptoRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x0e\n" +
+	"\x02vs\x18\x02 \x01(\tR\x02vs\"h\n" +
+	"\x10AddCryptoRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x17\n" +
+	"\acoin_id\x18\x02 \x01(\tR\x06coinId\x12#\n" +
+	"\rbackfill_days\x18\x03 \x01(\x05R\fbackfillDays\".\n" +
+	"\x14RefreshCryptoRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\"-\n" +
+	"\x13DeleteCryptoRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\"\x16\n" +
+	"\x14DeleteCryptoResponse\"\xe1\x01\n" +
+	"\x11GetHistoryRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x0e\n" +
+	"\x02vs\x18\x02 \x01(\tR\x02vs\x12.\n" +
+	"\x04from\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x06 \x01(\tR\x06cursor\x12\x1a\n" +
+	"\binterval\x18\a \x01(\tR\binterval\"\x85\x02\n" +
+	"\fHistoryPoint\x12\x14\n" +
+	"\x05price\x18\x01 \x01(\x01R\x05price\x12;\n" +
+	"\x06prices\x18\x02 \x03(\v2#.crypto.v1.HistoryPoint.PricesEntryR\x06prices\x12-\n" +
+	"\x06market\x18\x03 \x01(\v2\x15.crypto.v1.MarketDataR\x06market\x128\n" +
+	"\ttimestamp\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x1a9\n" +
+	"\vPricesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"\xb8\x01\n" +
+	"\x12GetHistoryResponse\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\x12\x1a\n" +
+	"\binterval\x18\x03 \x01(\tR\binterval\x121\n" +
+	"\ahistory\x18\x04 \x03(\v2\x17.crypto.v1.HistoryPointR\ahistory\x12\x1f\n" +
+	"\vnext_cursor\x18\x05 \x01(\tR\n" +
+	"nextCursor\"Q\n" +
+	"\x0fGetStatsRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x0e\n" +
+	"\x02vs\x18\x02 \x01(\tR\x02vs\x12\x16\n" +
+	"\x06window\x18\x03 \x01(\tR\x06window\"X\n" +
+	"\x10PricePercentiles\x12\x0e\n" +
+	"\x02p5\x18\x01 \x01(\x01R\x02p5\x12\x10\n" +
+	"\x03p25\x18\x02 \x01(\x01R\x03p25\x12\x10\n" +
+	"\x03p75\x18\x03 \x01(\x01R\x03p75\x12\x10\n" +
+	"\x03p95\x18\x04 \x01(\x01R\x03p95\"\xfb\x03\n" +
+	"\x05Stats\x12\x1b\n" +
+	"\tmin_price\x18\x01 \x01(\x01R\bminPrice\x12\x1b\n" +
+	"\tmax_price\x18\x02 \x01(\x01R\bmaxPrice\x12\x1b\n" +
+	"\tavg_price\x18\x03 \x01(\x01R\bavgPrice\x12!\n" +
+	"\fmedian_price\x18\x04 \x01(\x01R\vmedianPrice\x12\x17\n" +
+	"\astd_dev\x18\x05 \x01(\x01R\x06stdDev\x12\x1e\n" +
+	"\n" +
+	"volatility\x18\x06 \x01(\x01R\n" +
+	"volatility\x12=\n" +
+	"\vpercentiles\x18\a \x01(\v2\x1b.crypto.v1.PricePercentilesR\vpercentiles\x12!\n" +
+	"\fprice_change\x18\b \x01(\x01R\vpriceChange\x120\n" +
+	"\x14price_change_percent\x18\t \x01(\x01R\x12priceChangePercent\x12#\n" +
+	"\rrecords_count\x18\n" +
+	" \x01(\x05R\frecordsCount\x12C\n" +
+	"\x0ffirst_timestamp\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\x0efirstTimestamp\x12A\n" +
+	"\x0elast_timestamp\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\rlastTimestamp\"\xab\x01\n" +
+	"\x10GetStatsResponse\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06window\x18\x03 \x01(\tR\x06window\x12#\n" +
+	"\rcurrent_price\x18\x04 \x01(\x01R\fcurrentPrice\x12&\n" +
+	"\x05stats\x18\x05 \x01(\v2\x10.crypto.v1.StatsR\x05stats\"I\n" +
+	"\x12WatchPricesRequest\x12\x18\n" +
+	"\asymbols\x18\x01 \x03(\tR\asymbols\x12\x19\n" +
+	"\bafter_id\x18\x02 \x01(\x03R\aafterId\"\xbb\x02\n" +
+	"\tPriceTick\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\x01R\x05price\x128\n" +
+	"\x06prices\x18\x05 \x03(\v2 .crypto.v1.PriceTick.PricesEntryR\x06prices\x12-\n" +
+	"\x06market\x18\x06 \x01(\v2\x15.crypto.v1.MarketDataR\x06market\x128\n" +
+	"\ttimestamp\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x1a9\n" +
+	"\vPricesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"\"\n" +
+	"\x06Lagged\x12\x18\n" +
+	"\askipped\x18\x01 \x01(\x05R\askipped\"#\n" +
+	"\x06Resync\x12\x19\n" +
+	"\bafter_id\x18\x01 \x01(\x03R\aafterId\"\xa4\x01\n" +
+	"\x13WatchPricesResponse\x12*\n" +
+	"\x04tick\x18\x01 \x01(\v2\x14.crypto.v1.PriceTickH\x00R\x04tick\x12+\n" +
+	"\x06lagged\x18\x02 \x01(\v2\x11.crypto.v1.LaggedH\x00R\x06lagged\x12+\n" +
+	"\x06resync\x18\x03 \x01(\v2\x11.crypto.v1.ResyncH\x00R\x06resyncB\a\n" +
+	"\x05event\"\x14\n" +
+	"\x12GetScheduleRequest\"\xc9\x01\n" +
+	"\bSchedule\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12)\n" +
+	"\x10interval_seconds\x18\x02 \x01(\x05R\x0fintervalSeconds\x12;\n" +
+	"\vlast_update\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"lastUpdate\x12;\n" +
+	"\vnext_update\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"nextUpdate\"\\\n" +
+	"\x15UpdateScheduleRequest\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12)\n" +
+	"\x10interval_seconds\x18\x02 \x01(\x05R\x0fintervalSeconds\"\x16\n" +
+	"\x14TriggerUpdateRequest\"v\n" +
+	"\x15TriggerUpdateResponse\x12#\n" +
+	"\rupdated_count\x18\x01 \x01(\x05R\fupdatedCount\x128\n" +
+	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp2\xcd\x04\n" +
+	"\rCryptoService\x12L\n" +
+	"\vListCryptos\x12\x1d.crypto.v1.ListCryptosRequest\x1a\x1e.crypto.v1.ListCryptosResponse\x12;\n" +
+	"\tGetCrypto\x12\x1b.crypto.v1.GetCryptoRequest\x1a\x11.crypto.v1.Crypto\x12;\n" +
+	"\tAddCrypto\x12\x1b.crypto.v1.AddCryptoRequest\x1a\x11.crypto.v1.Crypto\x12C\n" +
+	"\rRefreshCrypto\x12\x1f.crypto.v1.RefreshCryptoRequest\x1a\x11.crypto.v1.Crypto\x12O\n" +
+	"\fDeleteCrypto\x12\x1e.crypto.v1.DeleteCryptoRequest\x1a\x1f.crypto.v1.DeleteCryptoResponse\x12I\n" +
+	"\n" +
+	"GetHistory\x12\x1c.crypto.v1.GetHistoryRequest\x1a\x1d.crypto.v1.GetHistoryResponse\x12C\n" +
+	"\bGetStats\x12\x1a.crypto.v1.GetStatsRequest\x1a\x1b.crypto.v1.GetStatsResponse\x12N\n" +
+	"\vWatchPrices\x12\x1d.crypto.v1.WatchPricesRequest\x1a\x1e.crypto.v1.WatchPricesResponse0\x012\xf1\x01\n" +
+	"\x0fScheduleService\x12A\n" +
+	"\vGetSchedule\x12\x1d.crypto.v1.GetScheduleRequest\x1a\x13.crypto.v1.Schedule\x12G\n" +
+	"\x0eUpdateSchedule\x12 .crypto.v1.UpdateScheduleRequest\x1a\x13.crypto.v1.Schedule\x12R\n" +
+	"\rTriggerUpdate\x12\x1f.crypto.v1.TriggerUpdateRequest\x1a .crypto.v1.TriggerUpdateResponseB5Z3RESTCryptoServer/internal/grpcapi/cryptov1;cryptov1b\x06proto3"
+
+var (
+	file_crypto_v1_crypto_proto_rawDescOnce sync.Once
+	file_crypto_v1_crypto_proto_rawDescData []byte
+)
+
+func file_crypto_v1_crypto_proto_rawDescGZIP() []byte {
+	file_crypto_v1_crypto_proto_rawDescOnce.Do(func() {
+		file_crypto_v1_crypto_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_crypto_v1_crypto_proto_rawDesc), len(file_crypto_v1_crypto_proto_rawDesc)))
+	})
+	return file_crypto_v1_crypto_proto_rawDescData
+}
+
+var file_crypto_v1_crypto_proto_msgTypes = make([]protoimpl.MessageInfo, 30)
+var file_crypto_v1_crypto_proto_goTypes = []any{
+	(*MarketData)(nil),            // 0: crypto.v1.MarketData
+	(*BackfillJob)(nil),           // 1: crypto.v1.BackfillJob
+	(*Crypto)(nil),                // 2: crypto.v1.Crypto
+	(*ListCryptosRequest)(nil),    // 3: crypto.v1.ListCryptosRequest
+	(*ListCryptosResponse)(nil),   // 4: crypto.v1.ListCryptosResponse
+	(*GetCryptoRequest)(nil),      // 5: crypto.v1.GetCryptoRequest
+	(*AddCryptoRequest)(nil),      // 6: crypto.v1.AddCryptoRequest
+	(*RefreshCryptoRequest)(nil),  // 7: crypto.v1.RefreshCryptoRequest
+	(*DeleteCryptoRequest)(nil),   // 8: crypto.v1.DeleteCryptoRequest
+	(*DeleteCryptoResponse)(nil),  // 9: crypto.v1.DeleteCryptoResponse
+	(*GetHistoryRequest)(nil),     // 10: crypto.v1.GetHistoryRequest
+	(*HistoryPoint)(nil),          // 11: crypto.v1.HistoryPoint
+	(*GetHistoryResponse)(nil),    // 12: crypto.v1.GetHistoryResponse
+	(*GetStatsRequest)(nil),       // 13: crypto.v1.GetStatsRequest
+	(*PricePercentiles)(nil),      // 14: crypto.v1.PricePercentiles
+	(*Stats)(nil),                 // 15: crypto.v1.Stats
+	(*GetStatsResponse)(nil),      // 16: crypto.v1.GetStatsResponse
+	(*WatchPricesRequest)(nil),    // 17: crypto.v1.WatchPricesRequest
+	(*PriceTick)(nil),             // 18: crypto.v1.PriceTick
+	(*Lagged)(nil),                // 19: crypto.v1.Lagged
+	(*Resync)(nil),                // 20: crypto.v1.Resync
+	(*WatchPricesResponse)(nil),   // 21: crypto.v1.WatchPricesResponse
+	(*GetScheduleRequest)(nil),    // 22: crypto.v1.GetScheduleRequest
+	(*Schedule)(nil),              // 23: crypto.v1.Schedule
+	(*UpdateScheduleRequest)(nil), // 24: crypto.v1.UpdateScheduleRequest
+	(*TriggerUpdateRequest)(nil),  // 25: crypto.v1.TriggerUpdateRequest
+	(*TriggerUpdateResponse)(nil), // 26: crypto.v1.TriggerUpdateResponse
+	nil,                           // 27: crypto.v1.Crypto.PricesEntry
+	nil,                           // 28: crypto.v1.HistoryPoint.PricesEntry
+	nil,                           // 29: crypto.v1.PriceTick.PricesEntry
+	(*timestamppb.Timestamp)(nil), // 30: google.protobuf.Timestamp
+}
+var file_crypto_v1_crypto_proto_depIdxs = []int32{
+	30, // 0: crypto.v1.BackfillJob.created_at:type_name -> google.protobuf.Timestamp
+	30, // 1: crypto.v1.BackfillJob.updated_at:type_name -> google.protobuf.Timestamp
+	27, // 2: crypto.v1.Crypto.prices:type_name -> crypto.v1.Crypto.PricesEntry
+	0,  // 3: crypto.v1.Crypto.market:type_name -> crypto.v1.MarketData
+	30, // 4: crypto.v1.Crypto.last_updated:type_name -> google.protobuf.Timestamp
+	1,  // 5: crypto.v1.Crypto.backfill_job:type_name -> crypto.v1.BackfillJob
+	2,  // 6: crypto.v1.ListCryptosResponse.cryptos:type_name -> crypto.v1.Crypto
+	30, // 7: crypto.v1.GetHistoryRequest.from:type_name -> google.protobuf.Timestamp
+	30, // 8: crypto.v1.GetHistoryRequest.to:type_name -> google.protobuf.Timestamp
+	28, // 9: crypto.v1.HistoryPoint.prices:type_name -> crypto.v1.HistoryPoint.PricesEntry
+	0,  // 10: crypto.v1.HistoryPoint.market:type_name -> crypto.v1.MarketData
+	30, // 11: crypto.v1.HistoryPoint.timestamp:type_name -> google.protobuf.Timestamp
+	11, // 12: crypto.v1.GetHistoryResponse.history:type_name -> crypto.v1.HistoryPoint
+	14, // 13: crypto.v1.Stats.percentiles:type_name -> crypto.v1.PricePercentiles
+	30, // 14: crypto.v1.Stats.first_timestamp:type_name -> google.protobuf.Timestamp
+	30, // 15: crypto.v1.Stats.last_timestamp:type_name -> google.protobuf.Timestamp
+	15, // 16: crypto.v1.GetStatsResponse.stats:type_name -> crypto.v1.Stats
+	29, // 17: crypto.v1.PriceTick.prices:type_name -> crypto.v1.PriceTick.PricesEntry
+	0,  // 18: crypto.v1.PriceTick.market:type_name -> crypto.v1.MarketData
+	30, // 19: crypto.v1.PriceTick.timestamp:type_name -> google.protobuf.Timestamp
+	18, // 20: crypto.v1.WatchPricesResponse.tick:type_name -> crypto.v1.PriceTick
+	19, // 21: crypto.v1.WatchPricesResponse.lagged:type_name -> crypto.v1.Lagged
+	20, // 22: crypto.v1.WatchPricesResponse.resync:type_name -> crypto.v1.Resync
+	30, // 23: crypto.v1.Schedule.last_update:type_name -> google.protobuf.Timestamp
+	30, // 24: crypto.v1.Schedule.next_update:type_name -> google.protobuf.Timestamp
+	30, // 25: crypto.v1.TriggerUpdateResponse.timestamp:type_name -> google.protobuf.Timestamp
+	3,  // 26: crypto.v1.CryptoService.ListCryptos:input_type -> crypto.v1.ListCryptosRequest
+	5,  // 27: crypto.v1.CryptoService.GetCrypto:input_type -> crypto.v1.GetCryptoRequest
+	6,  // 28: crypto.v1.CryptoService.AddCrypto:input_type -> crypto.v1.AddCryptoRequest
+	7,  // 29: crypto.v1.CryptoService.RefreshCrypto:input_type -> crypto.v1.RefreshCryptoRequest
+	8,  // 30: crypto.v1.CryptoService.DeleteCrypto:input_type -> crypto.v1.DeleteCryptoRequest
+	10, // 31: crypto.v1.CryptoService.GetHistory:input_type -> crypto.v1.GetHistoryRequest
+	13, // 32: crypto.v1.CryptoService.GetStats:input_type -> crypto.v1.GetStatsRequest
+	17, // 33: crypto.v1.CryptoService.WatchPrices:input_type -> crypto.v1.WatchPricesRequest
+	22, // 34: crypto.v1.ScheduleService.GetSchedule:input_type -> crypto.v1.GetScheduleRequest
+	24, // 35: crypto.v1.ScheduleService.UpdateSchedule:input_type -> crypto.v1.UpdateScheduleRequest
+	25, // 36: crypto.v1.ScheduleService.TriggerUpdate:input_type -> crypto.v1.TriggerUpdateRequest
+	4,  // 37: crypto.v1.CryptoService.ListCryptos:output_type -> crypto.v1.ListCryptosResponse
+	2,  // 38: crypto.v1.CryptoService.GetCrypto:output_type -> crypto.v1.Crypto
+	2,  // 39: crypto.v1.CryptoService.AddCrypto:output_type -> crypto.v1.Crypto
+	2,  // 40: crypto.v1.CryptoService.RefreshCrypto:output_type -> crypto.v1.Crypto
+	9,  // 41: crypto.v1.CryptoService.DeleteCrypto:output_type -> crypto.v1.DeleteCryptoResponse
+	12, // 42: crypto.v1.CryptoService.GetHistory:output_type -> crypto.v1.GetHistoryResponse
+	16, // 43: crypto.v1.CryptoService.GetStats:output_type -> crypto.v1.GetStatsResponse
+	21, // 44: crypto.v1.CryptoService.WatchPrices:output_type -> crypto.v1.WatchPricesResponse
+	23, // 45: crypto.v1.ScheduleService.GetSchedule:output_type -> crypto.v1.Schedule
+	23, // 46: crypto.v1.ScheduleService.UpdateSchedule:output_type -> crypto.v1.Schedule
+	26, // 47: crypto.v1.ScheduleService.TriggerUpdate:output_type -> crypto.v1.TriggerUpdateResponse
+	37, // [37:48] is the sub-list for method output_type
+	26, // [26:37] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
+}
+
+func init() { file_crypto_v1_crypto_proto_init() }
+func file_crypto_v1_crypto_proto_init() {
+	if File_crypto_v1_crypto_proto != nil {
+		return
+	}
+	file_crypto_v1_crypto_proto_msgTypes[21].OneofWrappers = []any{
+		(*WatchPricesResponse_Tick)(nil),
+		(*WatchPricesResponse_Lagged)(nil),
+		(*WatchPricesResponse_Resync)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_crypto_v1_crypto_proto_rawDesc), len(file_crypto_v1_crypto_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   30,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_crypto_v1_crypto_proto_goTypes,
+		DependencyIndexes: file_crypto_v1_crypto_proto_depIdxs,
+		MessageInfos:      file_crypto_v1_crypto_proto_msgTypes,
+	}.Build()
+	File_crypto_v1_crypto_proto = out.File
+	file_crypto_v1_crypto_proto_goTypes = nil
+	file_crypto_v1_crypto_proto_depIdxs = nil
+}
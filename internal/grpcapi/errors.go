@@ -0,0 +1,56 @@
+package grpcapi
+
+import (
+	"RESTCryptoServer/internal/crypto"
+	"RESTCryptoServer/internal/provider"
+	"errors"
+	"log"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// invalidArguments are the service errors caused by the request.
+var invalidArguments = []error{
+	crypto.ErrUnsupportedCurrency,
+	crypto.ErrPriceUnavailable,
+	crypto.ErrInvalidSort,
+	crypto.ErrInvalidStatsWindow,
+	crypto.ErrInvalidTimeRange,
+	crypto.ErrInvalidInterval,
+	crypto.ErrInvalidLimit,
+	crypto.ErrInvalidCursor,
+	crypto.ErrInvalidBackfillPeriod,
+	crypto.ErrUnknownCoinID,
+	crypto.ErrSymbolMismatch,
+	provider.ErrCoinNotFound,
+}
+
+// statusError turns a service error into a gRPC status, as the REST handlers
+// turn it into a status code. Unexpected errors are logged and not shown.
+func statusError(err error) error {
+	for _, invalid := range invalidArguments {
+		if errors.Is(err, invalid) {
+			return status.Error(codes.InvalidArgument, invalid.Error())
+		}
+	}
+
+	switch {
+	case errors.Is(err, crypto.ErrCryptoNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, crypto.ErrNameConflict):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, provider.ErrRateLimited):
+		log.Println("price provider error: ", err)
+		return status.Error(codes.ResourceExhausted, "price provider rate limit exceeded")
+	case errors.Is(err, provider.ErrNotFound):
+		log.Println("price provider error: ", err)
+		return status.Error(codes.NotFound, "not found on price provider")
+	case errors.Is(err, provider.ErrUpstreamUnavailable):
+		log.Println("price provider error: ", err)
+		return status.Error(codes.Unavailable, "price provider unavailable")
+	}
+
+	log.Println("gRPC call error: ", err)
+	return status.Error(codes.Internal, "server error")
+}
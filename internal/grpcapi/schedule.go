@@ -0,0 +1,64 @@
+package grpcapi
+
+import (
+	"RESTCryptoServer/internal/grpcapi/cryptov1"
+	"RESTCryptoServer/internal/updater"
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type scheduleServer struct {
+	cryptov1.UnimplementedScheduleServiceServer
+
+	updater *updater.Updater
+}
+
+func (s *scheduleServer) schedule() *cryptov1.Schedule {
+	interval := s.updater.GetUpdateTime()
+	lastUpdate := s.updater.GetLastUpdate()
+	enabled := s.updater.IsEnabled()
+
+	schedule := &cryptov1.Schedule{
+		Enabled:         enabled,
+		IntervalSeconds: int32(interval),
+		LastUpdate:      timestamp(lastUpdate),
+	}
+	if enabled && !lastUpdate.IsZero() {
+		schedule.NextUpdate = timestamp(lastUpdate.Add(time.Duration(interval) * time.Second))
+	}
+	return schedule
+}
+
+func (s *scheduleServer) GetSchedule(context.Context, *cryptov1.GetScheduleRequest) (*cryptov1.Schedule, error) {
+	return s.schedule(), nil
+}
+
+func (s *scheduleServer) UpdateSchedule(_ context.Context, req *cryptov1.UpdateScheduleRequest) (*cryptov1.Schedule, error) {
+	interval := int(req.GetIntervalSeconds())
+	if interval < updater.MinIntervalSeconds || interval > updater.MaxIntervalSeconds {
+		return nil, status.Error(codes.InvalidArgument,
+			fmt.Sprintf("interval must be %d-%d seconds", updater.MinIntervalSeconds, updater.MaxIntervalSeconds))
+	}
+
+	if req.GetEnabled() {
+		s.updater.RestartUpdating(interval)
+	} else {
+		s.updater.Disable()
+	}
+	return s.schedule(), nil
+}
+
+func (s *scheduleServer) TriggerUpdate(ctx context.Context, _ *cryptov1.TriggerUpdateRequest) (*cryptov1.TriggerUpdateResponse, error) {
+	count, err := s.updater.Update(ctx)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &cryptov1.TriggerUpdateResponse{
+		UpdatedCount: int32(count),
+		Timestamp:    timestamp(time.Now()),
+	}, nil
+}
@@ -0,0 +1,87 @@
+// Package grpcapi serves the crypto and schedule operations over gRPC, on a
+// port of its own next to the REST API.
+package grpcapi
+
+import (
+	"RESTCryptoServer/internal/crypto"
+	"RESTCryptoServer/internal/grpcapi/cryptov1"
+	"RESTCryptoServer/internal/stream"
+	"RESTCryptoServer/internal/updater"
+	"context"
+	"log"
+	"net"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+const (
+	defaultAddr = ":50051"
+
+	// keepaliveTime pings idle connections, so proxies keep long WatchPrices
+	// streams open and dead clients are noticed.
+	keepaliveTime = 30 * time.Second
+)
+
+type Server struct {
+	addr   string
+	server *grpc.Server
+}
+
+// NewServer registers the services on a server listening on GRPC_ADDR. Every
+// call is authenticated with the JWTs of the REST API.
+func NewServer(cs *crypto.CryptoService, u *updater.Updater, hub *stream.Hub) *Server {
+	addr := os.Getenv("GRPC_ADDR")
+	if addr == "" {
+		addr = defaultAddr
+	}
+
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(recoverUnary, authUnary),
+		grpc.ChainStreamInterceptor(recoverStream, authStream),
+		grpc.KeepaliveParams(keepalive.ServerParameters{Time: keepaliveTime}),
+	)
+	cryptov1.RegisterCryptoServiceServer(server, &cryptoServer{
+		cryptoService: cs,
+		hub:           hub,
+		limit:         stream.MaxSubscriptions(),
+	})
+	cryptov1.RegisterScheduleServiceServer(server, &scheduleServer{updater: u})
+
+	return &Server{addr: addr, server: server}
+}
+
+// Start listens on the address and serves in the background.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("gRPC server listening on %s", s.addr)
+	go func() {
+		if err := s.server.Serve(listener); err != nil {
+			log.Printf("gRPC server failed: %v", err)
+		}
+	}()
+	return nil
+}
+
+// Stop lets running calls finish until ctx is done and then cancels them.
+// WatchPrices streams end once the hub is stopped.
+func (s *Server) Stop(ctx context.Context) {
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.server.Stop()
+	}
+	log.Println("gRPC server stopped")
+}
@@ -0,0 +1,100 @@
+package grpcapi
+
+import (
+	"RESTCryptoServer/internal/grpcapi/cryptov1"
+	"RESTCryptoServer/internal/stream"
+	"log"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func tickMessage(tick stream.Tick) *cryptov1.WatchPricesResponse {
+	return &cryptov1.WatchPricesResponse{
+		Event: &cryptov1.WatchPricesResponse_Tick{Tick: &cryptov1.PriceTick{
+			Id:        tick.ID,
+			Symbol:    tick.Symbol,
+			Name:      tick.Name,
+			Price:     tick.Price,
+			Prices:    tick.Prices,
+			Market:    marketMessage(tick.MarketData),
+			Timestamp: timestamp(tick.Timestamp),
+		}},
+	}
+}
+
+// WatchPrices streams the price writes of the requested symbols from the hub,
+// as the SSE handler does: buffered ticks after after_id first, then live
+// ticks, merged per symbol when the client falls behind.
+func (s *cryptoServer) WatchPrices(req *cryptov1.WatchPricesRequest, srv grpc.ServerStreamingServer[cryptov1.WatchPricesResponse]) error {
+	symbols, err := stream.NormalizeSymbols(req.GetSymbols())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if len(symbols) == 0 {
+		return status.Error(codes.InvalidArgument, stream.ErrMissingSymbols.Error())
+	}
+	if len(symbols) > s.limit {
+		return status.Error(codes.InvalidArgument, stream.ErrTooManySubscriptions.Error())
+	}
+
+	subscription, err := s.hub.Subscribe()
+	if err == stream.ErrTooManyConnections {
+		log.Println(err)
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	defer subscription.Close()
+	subscription.Add(symbols, s.limit)
+
+	// sent is the id of the latest replayed tick, so ticks both replayed and
+	// received live are sent once.
+	var sent int64
+	if afterID := req.GetAfterId(); afterID > 0 {
+		ticks, gap := s.hub.Since(afterID, symbols)
+		if gap {
+			resync := &cryptov1.WatchPricesResponse{
+				Event: &cryptov1.WatchPricesResponse_Resync{Resync: &cryptov1.Resync{AfterId: afterID}},
+			}
+			if err := srv.Send(resync); err != nil {
+				return err
+			}
+		}
+		for _, tick := range ticks {
+			if err := srv.Send(tickMessage(tick)); err != nil {
+				return err
+			}
+			sent = tick.ID
+		}
+	}
+
+	for {
+		select {
+		case <-subscription.Ready():
+			ticks, skipped := subscription.Take()
+			if skipped > 0 {
+				lagged := &cryptov1.WatchPricesResponse{
+					Event: &cryptov1.WatchPricesResponse_Lagged{Lagged: &cryptov1.Lagged{Skipped: int32(skipped)}},
+				}
+				if err := srv.Send(lagged); err != nil {
+					return err
+				}
+			}
+			for _, tick := range ticks {
+				if tick.ID <= sent {
+					continue
+				}
+				if err := srv.Send(tickMessage(tick)); err != nil {
+					return err
+				}
+				sent = tick.ID
+			}
+
+		case <-subscription.Closed():
+			return status.Error(codes.Unavailable, "server shutting down")
+
+		case <-srv.Context().Done():
+			return srv.Context().Err()
+		}
+	}
+}
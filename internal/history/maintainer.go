@@ -0,0 +1,156 @@
+package history
+
+import (
+	"RESTCryptoServer/internal/db"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Maintainer periodically rolls raw price history up into 1m/1h/1d buckets and
+// enforces the retention of every table.
+type Maintainer struct {
+	historyDB *db.HistoryDB
+	interval  time.Duration
+
+	rawRetention    time.Duration
+	rollupRetention map[db.Resolution]time.Duration
+
+	mu       sync.Mutex
+	lastRun  time.Time
+	stopChan chan struct{}
+}
+
+func NewMaintainer(historyDB *db.HistoryDB) *Maintainer {
+	interval := time.Duration(envInt("HISTORY_ROLLUP_INTERVAL_MINUTES", 5)) * time.Minute
+	if interval == 0 {
+		interval = 5 * time.Minute
+	}
+
+	return &Maintainer{
+		historyDB:    historyDB,
+		interval:     interval,
+		rawRetention: days(envInt("HISTORY_RAW_RETENTION_DAYS", 7)),
+		rollupRetention: map[db.Resolution]time.Duration{
+			db.ResolutionMinute: days(envInt("HISTORY_1M_RETENTION_DAYS", 30)),
+			db.ResolutionHour:   days(envInt("HISTORY_1H_RETENTION_DAYS", 365)),
+			db.ResolutionDay:    days(envInt("HISTORY_1D_RETENTION_DAYS", 0)),
+		},
+		stopChan: make(chan struct{}),
+	}
+}
+
+func envInt(name string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(name)); err == nil && value >= 0 {
+		return value
+	}
+	return fallback
+}
+
+func days(n int) time.Duration {
+	return time.Duration(n) * 24 * time.Hour
+}
+
+// RawRetention reports how long raw points are kept.
+func (m *Maintainer) RawRetention() time.Duration {
+	return m.rawRetention
+}
+
+// Run rolls up everything recorded since the previous run and then purges
+// expired rows. Raw points are only purged once the rollups count them, so
+// points backfilled while Run is in progress survive until the next run.
+func (m *Maintainer) Run() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	started := time.Now()
+	since := time.Unix(0, 0)
+	if !m.lastRun.IsZero() {
+		since = m.lastRun.Add(-time.Minute)
+	}
+
+	for _, resolution := range db.Resolutions {
+		buckets, err := m.historyDB.Rollup(resolution, since)
+		if err != nil {
+			log.Printf("History rollup %s failed: %v", resolution, err)
+			return err
+		}
+		if buckets > 0 {
+			log.Printf("History rollup %s: %d buckets updated", resolution, buckets)
+		}
+	}
+	m.lastRun = started
+
+	if m.rawRetention > 0 {
+		purged, err := m.historyDB.PurgeRaw(m.rawRetention, m.coveringResolutions())
+		if err != nil {
+			log.Printf("Failed to purge raw price history: %v", err)
+			return err
+		}
+		if purged > 0 {
+			log.Printf("Purged %d raw price history points", purged)
+		}
+	}
+
+	for _, resolution := range db.Resolutions {
+		retention := m.rollupRetention[resolution]
+		if retention == 0 {
+			continue
+		}
+		purged, err := m.historyDB.PurgeRollups(resolution, retention)
+		if err != nil {
+			log.Printf("Failed to purge %s rollups: %v", resolution, err)
+			return err
+		}
+		if purged > 0 {
+			log.Printf("Purged %d %s rollups", purged, resolution)
+		}
+	}
+
+	return nil
+}
+
+// coveringResolutions lists the rollups raw points must be counted in before
+// they are purged: those kept at least as long as the raw points, since the
+// others are gone by the time the raw points expire.
+func (m *Maintainer) coveringResolutions() []db.Resolution {
+	var resolutions []db.Resolution
+	for _, resolution := range db.Resolutions {
+		retention := m.rollupRetention[resolution]
+		if retention == 0 || retention > m.rawRetention {
+			resolutions = append(resolutions, resolution)
+		}
+	}
+	return resolutions
+}
+
+func (m *Maintainer) Start() {
+	log.Printf("History maintenance started with interval: %s", m.interval)
+
+	go func() {
+		m.Run()
+
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				m.Run()
+			case <-m.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+func (m *Maintainer) Stop() {
+	select {
+	case <-m.stopChan:
+	default:
+		close(m.stopChan)
+		log.Println("History maintenance stopped")
+	}
+}
@@ -0,0 +1,221 @@
+// Package indicators computes technical indicators over evenly spaced price
+// series. Every result has the length of its input and holds NaN where the
+// indicator is not defined yet (the warm-up at the start of the series).
+package indicators
+
+import (
+	"errors"
+	"math"
+)
+
+var ErrInvalidPeriod = errors.New("invalid indicator period")
+
+const (
+	MACDFast   = 12
+	MACDSlow   = 26
+	MACDSignal = 9
+
+	BollingerDeviations = 2.0
+)
+
+func undefined(n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = math.NaN()
+	}
+	return out
+}
+
+// SMA is the simple moving average of the last period values.
+func SMA(values []float64, period int) ([]float64, error) {
+	if period <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+
+	out := undefined(len(values))
+	var sum float64
+	for i, value := range values {
+		sum += value
+		if i >= period {
+			sum -= values[i-period]
+		}
+		if i >= period-1 {
+			out[i] = sum / float64(period)
+		}
+	}
+	return out, nil
+}
+
+// EMA is the exponential moving average with smoothing 2/(period+1), seeded
+// with the SMA of the first period values. Leading NaN values are skipped, so
+// an EMA can be taken of another indicator.
+func EMA(values []float64, period int) ([]float64, error) {
+	if period <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+
+	out := undefined(len(values))
+	start := 0
+	for start < len(values) && math.IsNaN(values[start]) {
+		start++
+	}
+	if len(values)-start < period {
+		return out, nil
+	}
+
+	var sum float64
+	for _, value := range values[start : start+period] {
+		sum += value
+	}
+	seed := start + period - 1
+	out[seed] = sum / float64(period)
+
+	alpha := 2 / float64(period+1)
+	for i := seed + 1; i < len(values); i++ {
+		out[i] = alpha*values[i] + (1-alpha)*out[i-1]
+	}
+	return out, nil
+}
+
+// RSI is Wilder's relative strength index between 0 and 100.
+func RSI(values []float64, period int) ([]float64, error) {
+	if period <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+
+	out := undefined(len(values))
+	if len(values) <= period {
+		return out, nil
+	}
+
+	var gain, loss float64
+	for i := 1; i <= period; i++ {
+		change := values[i] - values[i-1]
+		if change > 0 {
+			gain += change
+		} else {
+			loss -= change
+		}
+	}
+	gain /= float64(period)
+	loss /= float64(period)
+	out[period] = rsi(gain, loss)
+
+	for i := period + 1; i < len(values); i++ {
+		change := values[i] - values[i-1]
+		var up, down float64
+		if change > 0 {
+			up = change
+		} else {
+			down = -change
+		}
+		gain = (gain*float64(period-1) + up) / float64(period)
+		loss = (loss*float64(period-1) + down) / float64(period)
+		out[i] = rsi(gain, loss)
+	}
+	return out, nil
+}
+
+func rsi(gain float64, loss float64) float64 {
+	if loss == 0 {
+		if gain == 0 {
+			return 50
+		}
+		return 100
+	}
+	return 100 - 100/(1+gain/loss)
+}
+
+// MACD returns the MACD line (fast EMA minus slow EMA), its signal EMA and the
+// histogram (MACD minus signal).
+func MACD(values []float64, fast int, slow int, signal int) (macd []float64, signalLine []float64, histogram []float64, err error) {
+	if fast <= 0 || slow <= fast || signal <= 0 {
+		return nil, nil, nil, ErrInvalidPeriod
+	}
+
+	fastEMA, _ := EMA(values, fast)
+	slowEMA, _ := EMA(values, slow)
+
+	macd = make([]float64, len(values))
+	for i := range values {
+		macd[i] = fastEMA[i] - slowEMA[i]
+	}
+
+	signalLine, _ = EMA(macd, signal)
+
+	histogram = make([]float64, len(values))
+	for i := range values {
+		histogram[i] = macd[i] - signalLine[i]
+	}
+	return macd, signalLine, histogram, nil
+}
+
+// Bollinger returns the SMA of period values and the bands deviations
+// population standard deviations above and below it.
+func Bollinger(values []float64, period int, deviations float64) (middle []float64, upper []float64, lower []float64, err error) {
+	middle, err = SMA(values, period)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	upper = undefined(len(values))
+	lower = undefined(len(values))
+	for i := period - 1; i < len(values); i++ {
+		var squares float64
+		for _, value := range values[i-period+1 : i+1] {
+			squares += (value - middle[i]) * (value - middle[i])
+		}
+		width := deviations * math.Sqrt(squares/float64(period))
+		upper[i] = middle[i] + width
+		lower[i] = middle[i] - width
+	}
+	return middle, upper, lower, nil
+}
+
+// Returns are the simple returns between consecutive values; the first
+// element is NaN.
+func Returns(values []float64) []float64 {
+	out := undefined(len(values))
+	for i := 1; i < len(values); i++ {
+		if values[i-1] != 0 {
+			out[i] = values[i]/values[i-1] - 1
+		}
+	}
+	return out
+}
+
+// Correlation is the Pearson correlation coefficient of x and y over the
+// indexes where both are defined. It is NaN with fewer than two such pairs or
+// when either series is constant.
+func Correlation(x []float64, y []float64) float64 {
+	var n, sumX, sumY float64
+	for i := 0; i < len(x) && i < len(y); i++ {
+		if math.IsNaN(x[i]) || math.IsNaN(y[i]) {
+			continue
+		}
+		n++
+		sumX += x[i]
+		sumY += y[i]
+	}
+	if n < 2 {
+		return math.NaN()
+	}
+
+	meanX := sumX / n
+	meanY := sumY / n
+	var cov, varX, varY float64
+	for i := 0; i < len(x) && i < len(y); i++ {
+		if math.IsNaN(x[i]) || math.IsNaN(y[i]) {
+			continue
+		}
+		dx := x[i] - meanX
+		dy := y[i] - meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return math.NaN()
+	}
+	return cov / math.Sqrt(varX*varY)
+}
@@ -0,0 +1,245 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+var nan = math.NaN()
+
+func assertSeries(t *testing.T, name string, got []float64, want []float64) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("%s: got %d values, want %d", name, len(got), len(want))
+	}
+	for i := range want {
+		if math.IsNaN(want[i]) {
+			if !math.IsNaN(got[i]) {
+				t.Errorf("%s[%d] = %v, want NaN", name, i, got[i])
+			}
+			continue
+		}
+		if math.IsNaN(got[i]) || math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Errorf("%s[%d] = %v, want %v", name, i, got[i], want[i])
+		}
+	}
+}
+
+func TestSMA(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		period int
+		want   []float64
+	}{
+		{"period 3", []float64{1, 2, 3, 4, 5}, 3, []float64{nan, nan, 2, 3, 4}},
+		{"period 1", []float64{1, 2, 3}, 1, []float64{1, 2, 3}},
+		{"shorter than period", []float64{1, 2}, 3, []float64{nan, nan}},
+		{"empty", []float64{}, 3, []float64{}},
+		{"small prices", []float64{0.00001, 0.00002, 0.00003}, 2, []float64{nan, 0.000015, 0.000025}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SMA(tt.values, tt.period)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertSeries(t, "sma", got, tt.want)
+		})
+	}
+}
+
+func TestEMA(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		period int
+		want   []float64
+	}{
+		{"seeded with sma", []float64{1, 2, 3, 4, 5}, 3, []float64{nan, nan, 2, 3, 4}},
+		{"constant", []float64{7, 7, 7, 7}, 2, []float64{nan, 7, 7, 7}},
+		{"leading nan", []float64{nan, 1, 2, 3}, 2, []float64{nan, nan, 1.5, 2.5}},
+		{"shorter than period", []float64{1, 2}, 3, []float64{nan, nan}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EMA(tt.values, tt.period)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertSeries(t, "ema", got, tt.want)
+		})
+	}
+}
+
+func TestRSI(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		period int
+		want   []float64
+	}{
+		{"only gains", []float64{1, 2, 3, 4}, 2, []float64{nan, nan, 100, 100}},
+		{"only losses", []float64{4, 3, 2, 1}, 2, []float64{nan, nan, 0, 0}},
+		{"flat", []float64{5, 5, 5}, 2, []float64{nan, nan, 50}},
+		{"wilder smoothing", []float64{1, 2, 1, 2, 1}, 2, []float64{nan, nan, 50, 75, 37.5}},
+		{"shorter than period", []float64{1, 2}, 2, []float64{nan, nan}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RSI(tt.values, tt.period)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertSeries(t, "rsi", got, tt.want)
+		})
+	}
+}
+
+func TestMACD(t *testing.T) {
+	tests := []struct {
+		name                 string
+		values               []float64
+		fast, slow, signal   int
+		wantMACD, wantSignal []float64
+		wantHistogram        []float64
+	}{
+		{
+			name:   "linear trend",
+			values: []float64{1, 2, 3, 4, 5},
+			fast:   2, slow: 3, signal: 2,
+			wantMACD:      []float64{nan, nan, 0.5, 0.5, 0.5},
+			wantSignal:    []float64{nan, nan, nan, 0.5, 0.5},
+			wantHistogram: []float64{nan, nan, nan, 0, 0},
+		},
+		{
+			name:   "constant",
+			values: []float64{3, 3, 3, 3, 3},
+			fast:   2, slow: 3, signal: 2,
+			wantMACD:      []float64{nan, nan, 0, 0, 0},
+			wantSignal:    []float64{nan, nan, nan, 0, 0},
+			wantHistogram: []float64{nan, nan, nan, 0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			macd, signal, histogram, err := MACD(tt.values, tt.fast, tt.slow, tt.signal)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertSeries(t, "macd", macd, tt.wantMACD)
+			assertSeries(t, "signal", signal, tt.wantSignal)
+			assertSeries(t, "histogram", histogram, tt.wantHistogram)
+		})
+	}
+}
+
+func TestBollinger(t *testing.T) {
+	tests := []struct {
+		name       string
+		values     []float64
+		period     int
+		deviations float64
+		wantMiddle []float64
+		wantUpper  []float64
+		wantLower  []float64
+	}{
+		{
+			name:       "population deviation",
+			values:     []float64{2, 4, 4, 4, 5, 5, 7, 9},
+			period:     8,
+			deviations: 2,
+			wantMiddle: []float64{nan, nan, nan, nan, nan, nan, nan, 5},
+			wantUpper:  []float64{nan, nan, nan, nan, nan, nan, nan, 9},
+			wantLower:  []float64{nan, nan, nan, nan, nan, nan, nan, 1},
+		},
+		{
+			name:       "constant",
+			values:     []float64{1, 1, 1},
+			period:     2,
+			deviations: 2,
+			wantMiddle: []float64{nan, 1, 1},
+			wantUpper:  []float64{nan, 1, 1},
+			wantLower:  []float64{nan, 1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			middle, upper, lower, err := Bollinger(tt.values, tt.period, tt.deviations)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertSeries(t, "middle", middle, tt.wantMiddle)
+			assertSeries(t, "upper", upper, tt.wantUpper)
+			assertSeries(t, "lower", lower, tt.wantLower)
+		})
+	}
+}
+
+func TestInvalidPeriod(t *testing.T) {
+	values := []float64{1, 2, 3}
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"sma zero", func() error { _, err := SMA(values, 0); return err }},
+		{"ema negative", func() error { _, err := EMA(values, -1); return err }},
+		{"rsi zero", func() error { _, err := RSI(values, 0); return err }},
+		{"macd slow not above fast", func() error { _, _, _, err := MACD(values, 3, 3, 2); return err }},
+		{"bollinger zero", func() error { _, _, _, err := Bollinger(values, 0, 2); return err }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); err != ErrInvalidPeriod {
+				t.Errorf("got %v, want ErrInvalidPeriod", err)
+			}
+		})
+	}
+}
+
+func TestReturns(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   []float64
+	}{
+		{"growth", []float64{100, 110, 99}, []float64{nan, 0.1, -0.1}},
+		{"zero base", []float64{0, 1}, []float64{nan, nan}},
+		{"single", []float64{5}, []float64{nan}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertSeries(t, "returns", Returns(tt.values), tt.want)
+		})
+	}
+}
+
+func TestCorrelation(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y []float64
+		want float64
+	}{
+		{"perfect", []float64{1, 2, 3, 4}, []float64{2, 4, 6, 8}, 1},
+		{"inverse", []float64{1, 2, 3, 4}, []float64{8, 6, 4, 2}, -1},
+		{"uncorrelated", []float64{1, 2, 3, 4}, []float64{1, -1, -1, 1}, 0},
+		{"skips nan", []float64{nan, 1, 2, 3}, []float64{5, 1, 2, 3}, 1},
+		{"constant", []float64{1, 1, 1}, []float64{1, 2, 3}, nan},
+		{"too short", []float64{1}, []float64{1}, nan},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertSeries(t, "correlation", []float64{Correlation(tt.x, tt.y)}, []float64{tt.want})
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package portfolio
+
+import (
+	"RESTCryptoServer/internal/crypto"
+	"RESTCryptoServer/internal/db"
+	"errors"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var ErrInvalidYear = errors.New("invalid year")
+
+// GainsQuery selects the sales of one calendar year in Location.
+type GainsQuery struct {
+	Year     int
+	Method   string
+	Location *time.Location
+}
+
+type SymbolGains struct {
+	Symbol    string          `json:"symbol"`
+	Quantity  decimal.Decimal `json:"quantity"`
+	Proceeds  decimal.Decimal `json:"proceeds"`
+	CostBasis decimal.Decimal `json:"cost_basis"`
+	Gain      decimal.Decimal `json:"gain"`
+}
+
+type GainsTotals struct {
+	Proceeds  decimal.Decimal `json:"proceeds"`
+	CostBasis decimal.Decimal `json:"cost_basis"`
+	Gain      decimal.Decimal `json:"gain"`
+}
+
+type GainsReport struct {
+	PortfolioID int64         `json:"portfolio_id"`
+	Currency    string        `json:"currency"`
+	Year        int           `json:"year"`
+	Method      string        `json:"method"`
+	Timezone    string        `json:"timezone"`
+	Disposals   []Disposal    `json:"disposals"`
+	Symbols     []SymbolGains `json:"symbols"`
+	Totals      GainsTotals   `json:"totals"`
+}
+
+// ParseGainsQuery reads year, method and tz. The year defaults to the current
+// one and tz, an IANA zone name, to UTC.
+func ParseGainsQuery(values url.Values) (GainsQuery, error) {
+	query := GainsQuery{Year: time.Now().UTC().Year(), Location: time.UTC}
+
+	if value := values.Get("year"); value != "" {
+		year, err := strconv.Atoi(value)
+		if err != nil || year < 1970 || year > 9999 {
+			return GainsQuery{}, ErrInvalidYear
+		}
+		query.Year = year
+	}
+
+	var err error
+	if query.Method, err = ParseCostMethod(values.Get("method")); err != nil {
+		return GainsQuery{}, err
+	}
+
+	if tz := values.Get("tz"); tz != "" {
+		if query.Location, err = time.LoadLocation(tz); err != nil {
+			return GainsQuery{}, crypto.ErrInvalidTimezone
+		}
+	}
+	return query, nil
+}
+
+// Gains reports the realized gains of the sales made in the query year. Cost
+// basis comes from replaying the whole ledger, so earlier years count too.
+func (ps *PortfolioService) Gains(owner string, id int64, query GainsQuery) (*GainsReport, error) {
+	portfolio, l, err := ps.ledger(owner, id, query.Method)
+	if err != nil {
+		return nil, err
+	}
+	return gainsReport(portfolio, l, query), nil
+}
+
+// gainsReport totals the disposals of l that fall in the query year.
+func gainsReport(portfolio db.Portfolio, l *ledger, query GainsQuery) *GainsReport {
+	from := time.Date(query.Year, time.January, 1, 0, 0, 0, 0, query.Location)
+	to := from.AddDate(1, 0, 0)
+
+	report := &GainsReport{
+		PortfolioID: portfolio.ID,
+		Currency:    portfolio.Currency,
+		Year:        query.Year,
+		Method:      query.Method,
+		Timezone:    query.Location.String(),
+		Disposals:   []Disposal{},
+		Symbols:     []SymbolGains{},
+	}
+
+	bySymbol := make(map[string]*SymbolGains)
+	for _, disposal := range l.disposals {
+		if disposal.SoldAt.Before(from) || !disposal.SoldAt.Before(to) {
+			continue
+		}
+		report.Disposals = append(report.Disposals, disposal)
+
+		gains, exists := bySymbol[disposal.Symbol]
+		if !exists {
+			gains = &SymbolGains{Symbol: disposal.Symbol}
+			bySymbol[disposal.Symbol] = gains
+		}
+		gains.Quantity = gains.Quantity.Add(disposal.Quantity)
+		gains.Proceeds = gains.Proceeds.Add(disposal.Proceeds)
+		gains.CostBasis = gains.CostBasis.Add(disposal.CostBasis)
+		gains.Gain = gains.Gain.Add(disposal.Gain)
+
+		report.Totals.Proceeds = report.Totals.Proceeds.Add(disposal.Proceeds)
+		report.Totals.CostBasis = report.Totals.CostBasis.Add(disposal.CostBasis)
+		report.Totals.Gain = report.Totals.Gain.Add(disposal.Gain)
+	}
+
+	for _, symbol := range slices.Sorted(maps.Keys(bySymbol)) {
+		report.Symbols = append(report.Symbols, *bySymbol[symbol])
+	}
+
+	return report
+}
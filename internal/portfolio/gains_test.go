@@ -0,0 +1,151 @@
+package portfolio
+
+import (
+	"RESTCryptoServer/internal/crypto"
+	"RESTCryptoServer/internal/db"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestGainsReportYears(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	// Sales in 2023 and 2024, the btc one of 2024 split over two lots, and one
+	// early on New Year's Day 2025 UTC, which is still 2024 in New York.
+	transactions := []db.Transaction{
+		trade(1, db.TransactionBuy, "btc", "1", "100", "0", time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)),
+		trade(2, db.TransactionBuy, "btc", "1", "200", "0", time.Date(2023, time.July, 1, 0, 0, 0, 0, time.UTC)),
+		trade(3, db.TransactionBuy, "eth", "10", "10", "0", time.Date(2023, time.July, 1, 0, 0, 0, 0, time.UTC)),
+		trade(4, db.TransactionSell, "btc", "0.5", "300", "0", time.Date(2023, time.December, 31, 12, 0, 0, 0, time.UTC)),
+		trade(5, db.TransactionSell, "btc", "1", "400", "0", time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)),
+		trade(6, db.TransactionSell, "eth", "5", "8", "0", time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC)),
+		trade(7, db.TransactionSell, "btc", "0.5", "500", "0", time.Date(2025, time.January, 1, 2, 0, 0, 0, time.UTC)),
+	}
+
+	l, err := replay(nil, transactions, MethodFIFO)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		query         GainsQuery
+		wantSales     []int64
+		wantSymbols   map[string]string
+		wantProceeds  string
+		wantCostBasis string
+		wantGain      string
+	}{
+		{
+			name:         "2023",
+			query:        GainsQuery{Year: 2023, Method: MethodFIFO, Location: time.UTC},
+			wantSales:    []int64{4},
+			wantSymbols:  map[string]string{"btc": "100"},
+			wantProceeds: "150", wantCostBasis: "50", wantGain: "100",
+		},
+		{
+			name:         "2024 with cost basis carried over from 2023",
+			query:        GainsQuery{Year: 2024, Method: MethodFIFO, Location: time.UTC},
+			wantSales:    []int64{5, 5, 6},
+			wantSymbols:  map[string]string{"btc": "250", "eth": "-10"},
+			wantProceeds: "440", wantCostBasis: "200", wantGain: "240",
+		},
+		{
+			name:         "2024 in New York",
+			query:        GainsQuery{Year: 2024, Method: MethodFIFO, Location: newYork},
+			wantSales:    []int64{5, 5, 6, 7},
+			wantSymbols:  map[string]string{"btc": "400", "eth": "-10"},
+			wantProceeds: "690", wantCostBasis: "300", wantGain: "390",
+		},
+		{
+			name:         "2025",
+			query:        GainsQuery{Year: 2025, Method: MethodFIFO, Location: time.UTC},
+			wantSales:    []int64{7},
+			wantSymbols:  map[string]string{"btc": "150"},
+			wantProceeds: "250", wantCostBasis: "100", wantGain: "150",
+		},
+		{
+			name:         "year without sales",
+			query:        GainsQuery{Year: 2022, Method: MethodFIFO, Location: time.UTC},
+			wantSymbols:  map[string]string{},
+			wantProceeds: "0", wantCostBasis: "0", wantGain: "0",
+		},
+	}
+
+	portfolio := db.Portfolio{ID: 9, Currency: "usd"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := gainsReport(portfolio, l, tt.query)
+
+			if report.PortfolioID != 9 || report.Currency != "usd" || report.Year != tt.query.Year ||
+				report.Timezone != tt.query.Location.String() {
+				t.Errorf("report header = %+v", report)
+			}
+
+			if len(report.Disposals) != len(tt.wantSales) {
+				t.Fatalf("got %d disposals, want %d: %+v", len(report.Disposals), len(tt.wantSales), report.Disposals)
+			}
+			for i, id := range tt.wantSales {
+				if report.Disposals[i].TransactionID != id {
+					t.Errorf("disposal %d of transaction %d, want %d", i, report.Disposals[i].TransactionID, id)
+				}
+			}
+
+			if len(report.Symbols) != len(tt.wantSymbols) {
+				t.Fatalf("symbols = %+v, want %v", report.Symbols, tt.wantSymbols)
+			}
+			for i, gains := range report.Symbols {
+				if i > 0 && report.Symbols[i-1].Symbol >= gains.Symbol {
+					t.Errorf("symbols are not sorted: %+v", report.Symbols)
+				}
+				if want, exists := tt.wantSymbols[gains.Symbol]; !exists || !gains.Gain.Equal(dec(want)) {
+					t.Errorf("%s gains %s, want %s", gains.Symbol, gains.Gain, want)
+				}
+			}
+
+			totals := report.Totals
+			if !totals.Proceeds.Equal(dec(tt.wantProceeds)) || !totals.CostBasis.Equal(dec(tt.wantCostBasis)) ||
+				!totals.Gain.Equal(dec(tt.wantGain)) {
+				t.Errorf("totals = %+v, want proceeds %s cost %s gain %s", totals, tt.wantProceeds, tt.wantCostBasis, tt.wantGain)
+			}
+		})
+	}
+}
+
+func TestParseGainsQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		wantYear int
+		wantZone string
+		wantErr  error
+	}{
+		{"defaults", "", time.Now().UTC().Year(), "UTC", nil},
+		{"year and zone", "year=2024&method=lifo&tz=Europe/Berlin", 2024, "Europe/Berlin", nil},
+		{"invalid year", "year=24k", 0, "", ErrInvalidYear},
+		{"year before 1970", "year=1969", 0, "", ErrInvalidYear},
+		{"invalid method", "method=hifo", 0, "", ErrInvalidMethod},
+		{"invalid zone", "tz=Mars/Olympus", 0, "", crypto.ErrInvalidTimezone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, _ := url.ParseQuery(tt.query)
+			query, err := ParseGainsQuery(values)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if query.Year != tt.wantYear || query.Location.String() != tt.wantZone {
+				t.Errorf("query = %d in %s, want %d in %s", query.Year, query.Location, tt.wantYear, tt.wantZone)
+			}
+		})
+	}
+}
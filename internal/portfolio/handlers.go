@@ -0,0 +1,467 @@
+package portfolio
+
+import (
+	"RESTCryptoServer/internal/auth"
+	"RESTCryptoServer/internal/crypto"
+	"RESTCryptoServer/internal/db"
+	"RESTCryptoServer/internal/provider"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/shopspring/decimal"
+)
+
+type PortfolioJSON struct {
+	Name     string `json:"name"`
+	Currency string `json:"currency"`
+}
+
+// PositionJSON accepts quantity and cost_basis as JSON numbers or strings.
+type PositionJSON struct {
+	Symbol    string          `json:"symbol"`
+	CoinID    string          `json:"coin_id"`
+	Quantity  decimal.Decimal `json:"quantity"`
+	CostBasis decimal.Decimal `json:"cost_basis"`
+}
+
+// maxImportBytes caps the size of an uploaded CSV file.
+const maxImportBytes = 1 << 20
+
+func portfolioID(r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	return id, err == nil && id > 0
+}
+
+func transactionID(r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "transactionID"), 10, 64)
+	return id, err == nil && id > 0
+}
+
+// writePortfolioError maps the portfolio errors shared by every handler and
+// reports whether err was handled.
+func writePortfolioError(w http.ResponseWriter, err error) bool {
+	if errors.Is(err, ErrInsufficientHoldings) {
+		log.Println(err)
+		http.Error(w, `Conflict - `+err.Error(), http.StatusConflict)
+		return true
+	}
+	if errors.Is(err, ErrInvalidCSV) {
+		log.Println(err)
+		http.Error(w, `Bad Request - `+err.Error(), http.StatusBadRequest)
+		return true
+	}
+
+	switch err {
+	case nil:
+		return false
+	case db.ErrUnknownPortfolio, db.ErrUnknownPosition, db.ErrUnknownTransaction:
+		log.Println(err)
+		http.Error(w, `Not Found`, http.StatusNotFound)
+	case db.ErrPortfolioNameUsed, db.ErrPositionExists:
+		log.Println(err)
+		http.Error(w, `Name conflict`, http.StatusConflict)
+	case ErrInvalidName, ErrInvalidQuantity, ErrInvalidCostBasis, ErrInvalidMethod, ErrInvalidYear,
+		ErrInvalidType, ErrInvalidSymbol, ErrInvalidPrice, ErrInvalidFee, ErrTransferOutAmount,
+		ErrInvalidExecutedAt, ErrInvalidNote, ErrTooManyRows:
+		log.Println(err)
+		http.Error(w, `Bad Request - `+err.Error(), http.StatusBadRequest)
+	case crypto.ErrUnsupportedCurrency:
+		log.Println(err)
+		http.Error(w, `Bad Request - unsupported currency`, http.StatusBadRequest)
+	case crypto.ErrInvalidTimezone:
+		log.Println(err)
+		http.Error(w, `Bad Request - invalid time zone`, http.StatusBadRequest)
+	default:
+		return false
+	}
+	return true
+}
+
+func GETPortfoliosHandler(ps *PortfolioService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		portfolios, err := ps.List(auth.Username(r.Context()))
+		if err != nil {
+			log.Println("error during listing portfolios: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(portfolios)
+	}
+}
+
+func POSTPortfolioHandler(ps *PortfolioService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var portfolioJSON PortfolioJSON
+		if err := json.NewDecoder(r.Body).Decode(&portfolioJSON); err != nil {
+			log.Println("Error during JSON parsing: ", err)
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		portfolio, err := ps.Create(auth.Username(r.Context()), portfolioJSON.Name, portfolioJSON.Currency)
+		if writePortfolioError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during creating portfolio: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		json.NewEncoder(w).Encode(portfolio)
+	}
+}
+
+func GETPortfolioHandler(ps *PortfolioService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := portfolioID(r)
+		if !ok {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		method, err := ParseCostMethod(r.URL.Query().Get("method"))
+		if writePortfolioError(w, err) {
+			return
+		}
+
+		resp, err := ps.Get(auth.Username(r.Context()), id, method)
+		if writePortfolioError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during valuing portfolio: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+func PUTPortfolioHandler(ps *PortfolioService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := portfolioID(r)
+		if !ok {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		var portfolioJSON PortfolioJSON
+		if err := json.NewDecoder(r.Body).Decode(&portfolioJSON); err != nil {
+			log.Println("Error during JSON parsing: ", err)
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		portfolio, err := ps.Rename(auth.Username(r.Context()), id, portfolioJSON.Name)
+		if writePortfolioError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during renaming portfolio: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(portfolio)
+	}
+}
+
+func DELETEPortfolioHandler(ps *PortfolioService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := portfolioID(r)
+		if !ok {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		err := ps.Delete(auth.Username(r.Context()), id)
+		if writePortfolioError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during deleting portfolio: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	}
+}
+
+func POSTPositionHandler(ps *PortfolioService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := portfolioID(r)
+		if !ok {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		var positionJSON PositionJSON
+		if err := json.NewDecoder(r.Body).Decode(&positionJSON); err != nil {
+			log.Println("Error during JSON parsing: ", err)
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+		if positionJSON.Symbol == "" {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		position, err := ps.AddPosition(r.Context(), auth.Username(r.Context()), id, positionJSON.Symbol, positionJSON.CoinID, positionJSON.Quantity, positionJSON.CostBasis)
+		if writePortfolioError(w, err) {
+			return
+		}
+		if writeTrackingError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during adding position: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		json.NewEncoder(w).Encode(position)
+	}
+}
+
+func PUTPositionHandler(ps *PortfolioService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := portfolioID(r)
+		if !ok {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		var positionJSON PositionJSON
+		if err := json.NewDecoder(r.Body).Decode(&positionJSON); err != nil {
+			log.Println("Error during JSON parsing: ", err)
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		position, err := ps.UpdatePosition(auth.Username(r.Context()), id, chi.URLParam(r, "symbol"), positionJSON.Quantity, positionJSON.CostBasis)
+		if writePortfolioError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during updating position: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(position)
+	}
+}
+
+func DELETEPositionHandler(ps *PortfolioService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := portfolioID(r)
+		if !ok {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		err := ps.DeletePosition(auth.Username(r.Context()), id, chi.URLParam(r, "symbol"))
+		if writePortfolioError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during deleting position: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	}
+}
+
+func GETTransactionsHandler(ps *PortfolioService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := portfolioID(r)
+		if !ok {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		transactions, err := ps.ListTransactions(auth.Username(r.Context()), id, r.URL.Query().Get("symbol"))
+		if writePortfolioError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during listing transactions: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(transactions)
+	}
+}
+
+// writeTrackingError maps the errors of starting to track a symbol and reports
+// whether err was handled.
+func writeTrackingError(w http.ResponseWriter, err error) bool {
+	if err == crypto.ErrUnknownCoinID || err == crypto.ErrSymbolMismatch || errors.Is(err, provider.ErrCoinNotFound) {
+		log.Println(err)
+		http.Error(w, `Bad Request`, http.StatusBadRequest)
+		return true
+	}
+	return provider.WriteHTTPError(w, err)
+}
+
+func POSTTransactionHandler(ps *PortfolioService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := portfolioID(r)
+		if !ok {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		var input TransactionInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			log.Println("Error during JSON parsing: ", err)
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		transaction, err := ps.AddTransaction(r.Context(), auth.Username(r.Context()), id, input)
+		if writePortfolioError(w, err) || writeTrackingError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during adding transaction: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		json.NewEncoder(w).Encode(transaction)
+	}
+}
+
+func POSTTransactionImportHandler(ps *PortfolioService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := portfolioID(r)
+		if !ok {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		body := http.MaxBytesReader(w, r.Body, maxImportBytes)
+		transactions, err := ps.ImportTransactions(r.Context(), auth.Username(r.Context()), id, body)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			log.Println(err)
+			http.Error(w, `Request Entity Too Large`, http.StatusRequestEntityTooLarge)
+			return
+		}
+		if writePortfolioError(w, err) || writeTrackingError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during importing transactions: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		json.NewEncoder(w).Encode(map[string]any{
+			"imported":     len(transactions),
+			"transactions": transactions,
+		})
+	}
+}
+
+func DELETETransactionHandler(ps *PortfolioService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := portfolioID(r)
+		if !ok {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+		transactionID, ok := transactionID(r)
+		if !ok {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		err := ps.DeleteTransaction(auth.Username(r.Context()), id, transactionID)
+		if writePortfolioError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during deleting transaction: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	}
+}
+
+func GETGainsHandler(ps *PortfolioService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := portfolioID(r)
+		if !ok {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		query, err := ParseGainsQuery(r.URL.Query())
+		if writePortfolioError(w, err) {
+			return
+		}
+
+		report, err := ps.Gains(auth.Username(r.Context()), id, query)
+		if writePortfolioError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during building gains report: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(report)
+	}
+}
@@ -0,0 +1,232 @@
+package portfolio
+
+import (
+	"RESTCryptoServer/internal/db"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	MethodFIFO    = "fifo"
+	MethodLIFO    = "lifo"
+	MethodAverage = "average"
+
+	// costPlaces is the scale of cost basis split off a lot, matching the
+	// NUMERIC(38, 18) columns.
+	costPlaces = 18
+)
+
+var (
+	ErrInvalidMethod        = errors.New("method must be fifo, lifo or average")
+	ErrInsufficientHoldings = errors.New("insufficient holdings")
+)
+
+// ParseCostMethod reads the cost basis method. An empty value means FIFO.
+func ParseCostMethod(value string) (string, error) {
+	switch method := strings.ToLower(value); method {
+	case "":
+		return MethodFIFO, nil
+	case MethodFIFO, MethodLIFO, MethodAverage:
+		return method, nil
+	default:
+		return "", ErrInvalidMethod
+	}
+}
+
+// lot is a quantity acquired at one time. Under the average method a holding
+// keeps a single pooled lot without an acquisition time.
+type lot struct {
+	quantity decimal.Decimal
+	cost     decimal.Decimal
+	acquired *time.Time
+}
+
+// holding is the replayed state of one symbol.
+type holding struct {
+	symbol   string
+	lots     []lot
+	realized decimal.Decimal
+	first    time.Time
+	last     time.Time
+}
+
+func (h *holding) quantity() decimal.Decimal {
+	total := decimal.Zero
+	for _, l := range h.lots {
+		total = total.Add(l.quantity)
+	}
+	return total
+}
+
+func (h *holding) cost() decimal.Decimal {
+	total := decimal.Zero
+	for _, l := range h.lots {
+		total = total.Add(l.cost)
+	}
+	return total
+}
+
+// Disposal is the part of a sale matched against one lot, or against the
+// pooled cost under the average method, where AcquiredAt is null.
+type Disposal struct {
+	TransactionID int64           `json:"transaction_id"`
+	Symbol        string          `json:"symbol"`
+	SoldAt        time.Time       `json:"sold_at"`
+	AcquiredAt    *time.Time      `json:"acquired_at"`
+	Quantity      decimal.Decimal `json:"quantity"`
+	Proceeds      decimal.Decimal `json:"proceeds"`
+	CostBasis     decimal.Decimal `json:"cost_basis"`
+	Gain          decimal.Decimal `json:"gain"`
+}
+
+type ledger struct {
+	method    string
+	holdings  map[string]*holding
+	disposals []Disposal
+}
+
+type ledgerEntry struct {
+	at       time.Time
+	opening  *db.Position
+	trade    *db.Transaction
+	sequence int
+}
+
+// replay applies the opening positions and then the transactions in execution
+// order. It fails when a transaction removes more than is held at that time.
+func replay(positions []db.Position, transactions []db.Transaction, method string) (*ledger, error) {
+	entries := make([]ledgerEntry, 0, len(positions)+len(transactions))
+	for i := range positions {
+		entries = append(entries, ledgerEntry{at: positions[i].CreatedAt, opening: &positions[i], sequence: len(entries)})
+	}
+	for i := range transactions {
+		entries = append(entries, ledgerEntry{at: transactions[i].ExecutedAt, trade: &transactions[i], sequence: len(entries)})
+	}
+	// Opening positions come first and transactions keep their order on ties.
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].at.Equal(entries[j].at) {
+			return entries[i].at.Before(entries[j].at)
+		}
+		return entries[i].sequence < entries[j].sequence
+	})
+
+	l := &ledger{method: method, holdings: make(map[string]*holding)}
+	for _, entry := range entries {
+		if entry.opening != nil {
+			l.acquire(entry.opening.Symbol, entry.at, entry.opening.Quantity, entry.opening.CostBasis)
+			continue
+		}
+
+		t := entry.trade
+		switch t.Type {
+		case db.TransactionBuy, db.TransactionTransferIn:
+			l.acquire(t.Symbol, t.ExecutedAt, t.Quantity, t.Quantity.Mul(t.Price).Add(t.Fee))
+		case db.TransactionSell, db.TransactionTransferOut:
+			if err := l.remove(t); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("%w: %q", ErrInvalidType, t.Type)
+		}
+	}
+	return l, nil
+}
+
+func (l *ledger) holding(symbol string, at time.Time) *holding {
+	h, exists := l.holdings[symbol]
+	if !exists {
+		h = &holding{symbol: symbol, first: at}
+		l.holdings[symbol] = h
+	}
+	h.last = at
+	return h
+}
+
+func (l *ledger) acquire(symbol string, at time.Time, quantity decimal.Decimal, cost decimal.Decimal) {
+	h := l.holding(symbol, at)
+	if l.method == MethodAverage {
+		if len(h.lots) == 0 {
+			h.lots = []lot{{}}
+		}
+		h.lots[0].quantity = h.lots[0].quantity.Add(quantity)
+		h.lots[0].cost = h.lots[0].cost.Add(cost)
+		return
+	}
+
+	acquired := at
+	h.lots = append(h.lots, lot{quantity: quantity, cost: cost, acquired: &acquired})
+}
+
+// remove takes the quantity of a sell or transfer_out from the lots picked by
+// the method. Sells realize the difference between proceeds net of the fee and
+// the cost removed; transfers move the cost out without a gain.
+func (l *ledger) remove(t *db.Transaction) error {
+	h := l.holding(t.Symbol, t.ExecutedAt)
+	if held := h.quantity(); held.LessThan(t.Quantity) {
+		return fmt.Errorf("%w: %s %s of %s on %s, holding %s", ErrInsufficientHoldings,
+			t.Type, t.Quantity, t.Symbol, t.ExecutedAt.Format(time.RFC3339), held)
+	}
+
+	sale := t.Type == db.TransactionSell
+	proceeds := t.Quantity.Mul(t.Price).Sub(t.Fee)
+	remaining := t.Quantity
+	allocated := decimal.Zero
+
+	for remaining.IsPositive() {
+		index := 0
+		if l.method == MethodLIFO {
+			index = len(h.lots) - 1
+		}
+		current := &h.lots[index]
+
+		take := decimal.Min(remaining, current.quantity)
+		acquired := current.acquired
+		cost := current.cost
+		if take.LessThan(current.quantity) {
+			cost = current.cost.Mul(take).DivRound(current.quantity, costPlaces)
+		}
+		current.quantity = current.quantity.Sub(take)
+		current.cost = current.cost.Sub(cost)
+		if current.quantity.IsZero() {
+			h.lots = append(h.lots[:index], h.lots[index+1:]...)
+		}
+		remaining = remaining.Sub(take)
+
+		if !sale {
+			continue
+		}
+
+		// The last slice takes the rest of the proceeds so they add up exactly.
+		share := proceeds.Sub(allocated)
+		if remaining.IsPositive() {
+			share = proceeds.Mul(take).DivRound(t.Quantity, costPlaces)
+		}
+		allocated = allocated.Add(share)
+
+		gain := share.Sub(cost)
+		h.realized = h.realized.Add(gain)
+		l.disposals = append(l.disposals, Disposal{
+			TransactionID: t.ID,
+			Symbol:        t.Symbol,
+			SoldAt:        t.ExecutedAt,
+			AcquiredAt:    acquired,
+			Quantity:      take,
+			Proceeds:      share,
+			CostBasis:     cost,
+			Gain:          gain,
+		})
+	}
+	return nil
+}
+
+// checkLedger is the db.LedgerCheck of every change. Whether a removal is
+// covered does not depend on the method, so replaying under FIFO is enough.
+func checkLedger(positions []db.Position, transactions []db.Transaction) error {
+	_, err := replay(positions, transactions, MethodFIFO)
+	return err
+}
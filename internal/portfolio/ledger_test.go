@@ -0,0 +1,252 @@
+package portfolio
+
+import (
+	"RESTCryptoServer/internal/db"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var day0 = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+func day(n int) time.Time {
+	return day0.AddDate(0, 0, n)
+}
+
+func dec(value string) decimal.Decimal {
+	return decimal.RequireFromString(value)
+}
+
+func trade(id int64, kind string, symbol string, quantity string, price string, fee string, at time.Time) db.Transaction {
+	return db.Transaction{ID: id, Type: kind, Symbol: symbol, Quantity: dec(quantity), Price: dec(price), Fee: dec(fee), ExecutedAt: at}
+}
+
+// wantDisposal is a disposal without its sale details, which are the same
+// for every slice of a sale.
+type wantDisposal struct {
+	acquired  *time.Time
+	quantity  string
+	proceeds  string
+	costBasis string
+	gain      string
+}
+
+func TestReplayMethods(t *testing.T) {
+	first, second := day(1), day(2)
+
+	// Two lots costing 100 and 200 with fees, one and a half sold at 300 with a fee
+	// of 3, leaving part of a lot.
+	transactions := []db.Transaction{
+		trade(3, db.TransactionSell, "btc", "1.5", "300", "3", day(3)),
+		trade(1, db.TransactionBuy, "btc", "1", "100", "0", first),
+		trade(2, db.TransactionBuy, "btc", "1", "199", "1", second),
+	}
+
+	tests := []struct {
+		method       string
+		disposals    []wantDisposal
+		wantQuantity string
+		wantCost     string
+		wantRealized string
+	}{
+		{
+			method: MethodFIFO,
+			disposals: []wantDisposal{
+				{&first, "1", "298", "100", "198"},
+				{&second, "0.5", "149", "100", "49"},
+			},
+			wantQuantity: "0.5", wantCost: "100", wantRealized: "247",
+		},
+		{
+			method: MethodLIFO,
+			disposals: []wantDisposal{
+				{&second, "1", "298", "200", "98"},
+				{&first, "0.5", "149", "50", "99"},
+			},
+			wantQuantity: "0.5", wantCost: "50", wantRealized: "197",
+		},
+		{
+			method: MethodAverage,
+			disposals: []wantDisposal{
+				{nil, "1.5", "447", "225", "222"},
+			},
+			wantQuantity: "0.5", wantCost: "75", wantRealized: "222",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			l, err := replay(nil, transactions, tt.method)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(l.disposals) != len(tt.disposals) {
+				t.Fatalf("got %d disposals, want %d: %+v", len(l.disposals), len(tt.disposals), l.disposals)
+			}
+			for i, want := range tt.disposals {
+				got := l.disposals[i]
+				if got.TransactionID != 3 || got.Symbol != "btc" || !got.SoldAt.Equal(day(3)) {
+					t.Errorf("disposal %d sale = %d %s %v, want transaction 3", i, got.TransactionID, got.Symbol, got.SoldAt)
+				}
+				if (got.AcquiredAt == nil) != (want.acquired == nil) ||
+					(got.AcquiredAt != nil && !got.AcquiredAt.Equal(*want.acquired)) {
+					t.Errorf("disposal %d acquired at %v, want %v", i, got.AcquiredAt, want.acquired)
+				}
+				if !got.Quantity.Equal(dec(want.quantity)) || !got.Proceeds.Equal(dec(want.proceeds)) ||
+					!got.CostBasis.Equal(dec(want.costBasis)) || !got.Gain.Equal(dec(want.gain)) {
+					t.Errorf("disposal %d = %s for %s at cost %s gains %s, want %s for %s at cost %s gains %s", i,
+						got.Quantity, got.Proceeds, got.CostBasis, got.Gain, want.quantity, want.proceeds, want.costBasis, want.gain)
+				}
+			}
+
+			h := l.holdings["btc"]
+			if !h.quantity().Equal(dec(tt.wantQuantity)) || !h.cost().Equal(dec(tt.wantCost)) || !h.realized.Equal(dec(tt.wantRealized)) {
+				t.Errorf("holding = %s at cost %s realized %s, want %s at cost %s realized %s",
+					h.quantity(), h.cost(), h.realized, tt.wantQuantity, tt.wantCost, tt.wantRealized)
+			}
+		})
+	}
+}
+
+func TestReplaySplitsProceedsExactly(t *testing.T) {
+	transactions := []db.Transaction{
+		trade(1, db.TransactionBuy, "eth", "1", "10", "0", day(1)),
+		trade(2, db.TransactionBuy, "eth", "1", "10", "0", day(2)),
+		trade(3, db.TransactionBuy, "eth", "1", "10", "0", day(3)),
+		trade(4, db.TransactionSell, "eth", "3", "100", "1", day(4)),
+	}
+
+	l, err := replay(nil, transactions, MethodFIFO)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	total := decimal.Zero
+	for _, disposal := range l.disposals {
+		total = total.Add(disposal.Proceeds)
+	}
+	if len(l.disposals) != 3 || !total.Equal(dec("299")) {
+		t.Errorf("proceeds of %d disposals add up to %s, want 3 adding up to 299", len(l.disposals), total)
+	}
+}
+
+func TestReplayTransferOut(t *testing.T) {
+	positions := []db.Position{{Symbol: "eth", Quantity: dec("2"), CostBasis: dec("1000"), CreatedAt: day(0)}}
+	transactions := []db.Transaction{
+		trade(1, db.TransactionTransferOut, "eth", "0.5", "0", "0", day(1)),
+		trade(2, db.TransactionSell, "eth", "0.5", "800", "0", day(2)),
+		trade(3, db.TransactionTransferIn, "eth", "1", "900", "0", day(3)),
+	}
+
+	for _, method := range []string{MethodFIFO, MethodLIFO, MethodAverage} {
+		t.Run(method, func(t *testing.T) {
+			l, err := replay(positions, transactions, method)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// The transfer moves its share of the cost out without a disposal.
+			if len(l.disposals) != 1 || l.disposals[0].TransactionID != 2 {
+				t.Fatalf("disposals = %+v, want the sell only", l.disposals)
+			}
+			if got := l.disposals[0]; !got.CostBasis.Equal(dec("250")) || !got.Gain.Equal(dec("150")) {
+				t.Errorf("sell at cost %s gains %s, want cost 250 and gain 150", got.CostBasis, got.Gain)
+			}
+
+			h := l.holdings["eth"]
+			if !h.quantity().Equal(dec("2")) || !h.cost().Equal(dec("1400")) || !h.realized.Equal(dec("150")) {
+				t.Errorf("holding = %s at cost %s realized %s, want 2 at cost 1400 realized 150", h.quantity(), h.cost(), h.realized)
+			}
+			if !h.first.Equal(day(0)) || !h.last.Equal(day(3)) {
+				t.Errorf("holding spans %v to %v, want %v to %v", h.first, h.last, day(0), day(3))
+			}
+		})
+	}
+}
+
+func TestReplayRejects(t *testing.T) {
+	tests := []struct {
+		name         string
+		positions    []db.Position
+		transactions []db.Transaction
+		wantErr      error
+	}{
+		{
+			name: "oversell",
+			transactions: []db.Transaction{
+				trade(1, db.TransactionBuy, "btc", "1", "100", "0", day(1)),
+				trade(2, db.TransactionSell, "btc", "1.5", "100", "0", day(2)),
+			},
+			wantErr: ErrInsufficientHoldings,
+		},
+		{
+			name: "sell before the buy",
+			transactions: []db.Transaction{
+				trade(1, db.TransactionBuy, "btc", "1", "100", "0", day(2)),
+				trade(2, db.TransactionSell, "btc", "1", "100", "0", day(1)),
+			},
+			wantErr: ErrInsufficientHoldings,
+		},
+		{
+			name:      "transfer out beyond the opening position",
+			positions: []db.Position{{Symbol: "eth", Quantity: dec("1"), CostBasis: dec("500"), CreatedAt: day(0)}},
+			transactions: []db.Transaction{
+				trade(1, db.TransactionTransferOut, "eth", "1.01", "0", "0", day(1)),
+			},
+			wantErr: ErrInsufficientHoldings,
+		},
+		{
+			name: "other symbol does not cover the sale",
+			transactions: []db.Transaction{
+				trade(1, db.TransactionBuy, "eth", "5", "100", "0", day(1)),
+				trade(2, db.TransactionSell, "btc", "1", "100", "0", day(2)),
+			},
+			wantErr: ErrInsufficientHoldings,
+		},
+		{
+			name: "unknown type",
+			transactions: []db.Transaction{
+				trade(1, "stake", "eth", "1", "100", "0", day(1)),
+			},
+			wantErr: ErrInvalidType,
+		},
+		{
+			name: "sell at the time of the buy",
+			transactions: []db.Transaction{
+				trade(1, db.TransactionBuy, "btc", "1", "100", "0", day(1)),
+				trade(2, db.TransactionSell, "btc", "1", "100", "0", day(1)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkLedger(tt.positions, tt.transactions); !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkLedger error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseCostMethod(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr error
+	}{
+		{"", MethodFIFO, nil},
+		{"LIFO", MethodLIFO, nil},
+		{"average", MethodAverage, nil},
+		{"hifo", "", ErrInvalidMethod},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseCostMethod(tt.value)
+		if got != tt.want || !errors.Is(err, tt.wantErr) {
+			t.Errorf("ParseCostMethod(%q) = %q, %v, want %q, %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package portfolio
+
+import (
+	"RESTCryptoServer/internal/crypto"
+	"RESTCryptoServer/internal/db"
+	"context"
+	"errors"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	maxNameLength = 100
+	percentPlaces = 4
+)
+
+var (
+	ErrInvalidName      = errors.New("portfolio name must be 1 to 100 characters")
+	ErrInvalidQuantity  = errors.New("quantity must be positive")
+	ErrInvalidCostBasis = errors.New("cost basis must not be negative")
+)
+
+var hundred = decimal.NewFromInt(100)
+
+// PositionValuation is an open position replayed from the ledger and valued at
+// the current price. CreatedAt and UpdatedAt are its first and last ledger
+// entries. The valuation fields are null when the symbol has no stored price in
+// the portfolio currency.
+type PositionValuation struct {
+	db.Position
+	RealizedPnL          decimal.Decimal  `json:"realized_pnl"`
+	Price                *decimal.Decimal `json:"price"`
+	MarketValue          *decimal.Decimal `json:"market_value"`
+	UnrealizedPnL        *decimal.Decimal `json:"unrealized_pnl"`
+	UnrealizedPnLPercent *decimal.Decimal `json:"unrealized_pnl_percent"`
+	AllocationPercent    *decimal.Decimal `json:"allocation_percent"`
+	PriceUpdated         *time.Time       `json:"price_updated,omitempty"`
+}
+
+// PortfolioTotals sums the priced positions; Unpriced lists the others.
+// RealizedPnL covers every sale, including those of closed positions.
+type PortfolioTotals struct {
+	MarketValue          decimal.Decimal  `json:"market_value"`
+	CostBasis            decimal.Decimal  `json:"cost_basis"`
+	UnrealizedPnL        decimal.Decimal  `json:"unrealized_pnl"`
+	UnrealizedPnLPercent *decimal.Decimal `json:"unrealized_pnl_percent"`
+	RealizedPnL          decimal.Decimal  `json:"realized_pnl"`
+	Unpriced             []string         `json:"unpriced"`
+}
+
+type PortfolioResponse struct {
+	db.Portfolio
+	Method    string              `json:"method"`
+	Positions []PositionValuation `json:"positions"`
+	Totals    PortfolioTotals     `json:"totals"`
+}
+
+type PortfolioService struct {
+	portfolioDB   *db.PortfolioDB
+	cryptoService *crypto.CryptoService
+}
+
+func NewPortfolioService(portfolioDB *db.PortfolioDB, cryptoService *crypto.CryptoService) *PortfolioService {
+	return &PortfolioService{
+		portfolioDB:   portfolioDB,
+		cryptoService: cryptoService,
+	}
+}
+
+func validName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxNameLength {
+		return "", ErrInvalidName
+	}
+	return name, nil
+}
+
+func validAmounts(quantity decimal.Decimal, costBasis decimal.Decimal) error {
+	if !quantity.IsPositive() {
+		return ErrInvalidQuantity
+	}
+	if costBasis.IsNegative() {
+		return ErrInvalidCostBasis
+	}
+	return nil
+}
+
+// percentOf returns part as a percentage of whole, or nil when whole is zero.
+func percentOf(part decimal.Decimal, whole decimal.Decimal) *decimal.Decimal {
+	if whole.IsZero() {
+		return nil
+	}
+	percent := part.Mul(hundred).DivRound(whole, percentPlaces)
+	return &percent
+}
+
+func (ps *PortfolioService) List(owner string) ([]db.Portfolio, error) {
+	return ps.portfolioDB.List(owner)
+}
+
+func (ps *PortfolioService) Create(owner string, name string, currency string) (*db.Portfolio, error) {
+	name, err := validName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	currencies := ps.cryptoService.QuoteCurrencies()
+	currency = strings.ToLower(strings.TrimSpace(currency))
+	if currency == "" {
+		currency = currencies[0]
+	}
+	if !slices.Contains(currencies, currency) {
+		return nil, crypto.ErrUnsupportedCurrency
+	}
+
+	portfolio, err := ps.portfolioDB.Create(owner, name, currency)
+	if err != nil {
+		return nil, err
+	}
+	return &portfolio, nil
+}
+
+// ledger replays the opening positions and transactions of a portfolio.
+func (ps *PortfolioService) ledger(owner string, id int64, method string) (db.Portfolio, *ledger, error) {
+	portfolio, err := ps.portfolioDB.Get(owner, id)
+	if err != nil {
+		return db.Portfolio{}, nil, err
+	}
+
+	positions, err := ps.portfolioDB.Positions(id)
+	if err != nil {
+		return db.Portfolio{}, nil, err
+	}
+	transactions, err := ps.portfolioDB.Transactions(id, "")
+	if err != nil {
+		return db.Portfolio{}, nil, err
+	}
+
+	l, err := replay(positions, transactions, method)
+	if err != nil {
+		return db.Portfolio{}, nil, err
+	}
+	return portfolio, l, nil
+}
+
+// Get values every open position of a portfolio at the current price in the
+// portfolio currency, with cost basis and realized P&L from method.
+func (ps *PortfolioService) Get(owner string, id int64, method string) (*PortfolioResponse, error) {
+	portfolio, l, err := ps.ledger(owner, id, method)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &PortfolioResponse{
+		Portfolio: portfolio,
+		Method:    method,
+		Positions: make([]PositionValuation, 0, len(l.holdings)),
+		Totals:    PortfolioTotals{Unpriced: []string{}},
+	}
+
+	symbols := slices.Sorted(maps.Keys(l.holdings))
+	for _, symbol := range symbols {
+		h := l.holdings[symbol]
+		resp.Totals.RealizedPnL = resp.Totals.RealizedPnL.Add(h.realized)
+
+		quantity := h.quantity()
+		if quantity.IsZero() {
+			continue
+		}
+		position := db.Position{
+			Symbol:    symbol,
+			Quantity:  quantity,
+			CostBasis: h.cost(),
+			CreatedAt: h.first,
+			UpdatedAt: h.last,
+		}
+		valuation := PositionValuation{Position: position, RealizedPnL: h.realized}
+
+		coin, err := ps.cryptoService.GetCrypto(position.Symbol, portfolio.Currency)
+		if err == crypto.ErrCryptoNotFound || err == crypto.ErrPriceUnavailable || err == crypto.ErrUnsupportedCurrency {
+			log.Printf("Portfolio %d: no %s price for %s: %v", id, portfolio.Currency, position.Symbol, err)
+			resp.Totals.Unpriced = append(resp.Totals.Unpriced, position.Symbol)
+			resp.Positions = append(resp.Positions, valuation)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		price := decimal.NewFromFloat(coin.CurrentPrice)
+		value := position.Quantity.Mul(price)
+		pnl := value.Sub(position.CostBasis)
+
+		valuation.Price = &price
+		valuation.MarketValue = &value
+		valuation.UnrealizedPnL = &pnl
+		valuation.UnrealizedPnLPercent = percentOf(pnl, position.CostBasis)
+		valuation.PriceUpdated = &coin.LastUpdated
+
+		resp.Totals.MarketValue = resp.Totals.MarketValue.Add(value)
+		resp.Totals.CostBasis = resp.Totals.CostBasis.Add(position.CostBasis)
+		resp.Positions = append(resp.Positions, valuation)
+	}
+
+	resp.Totals.UnrealizedPnL = resp.Totals.MarketValue.Sub(resp.Totals.CostBasis)
+	resp.Totals.UnrealizedPnLPercent = percentOf(resp.Totals.UnrealizedPnL, resp.Totals.CostBasis)
+	for i := range resp.Positions {
+		if value := resp.Positions[i].MarketValue; value != nil {
+			resp.Positions[i].AllocationPercent = percentOf(*value, resp.Totals.MarketValue)
+		}
+	}
+
+	return resp, nil
+}
+
+func (ps *PortfolioService) Rename(owner string, id int64, name string) (*db.Portfolio, error) {
+	name, err := validName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ps.portfolioDB.Rename(owner, id, name); err != nil {
+		return nil, err
+	}
+
+	portfolio, err := ps.portfolioDB.Get(owner, id)
+	if err != nil {
+		return nil, err
+	}
+	return &portfolio, nil
+}
+
+func (ps *PortfolioService) Delete(owner string, id int64) error {
+	return ps.portfolioDB.Delete(owner, id)
+}
+
+// AddPosition adds an opening balance to a portfolio, starting to track its
+// symbol first if needed.
+func (ps *PortfolioService) AddPosition(ctx context.Context, owner string, id int64, symbol string, coinID string, quantity decimal.Decimal, costBasis decimal.Decimal) (*db.Position, error) {
+	symbol = strings.ToLower(strings.TrimSpace(symbol))
+
+	if err := validAmounts(quantity, costBasis); err != nil {
+		return nil, err
+	}
+
+	if _, err := ps.portfolioDB.Get(owner, id); err != nil {
+		return nil, err
+	}
+
+	if err := ps.cryptoService.EnsureTracked(ctx, symbol, coinID); err != nil {
+		return nil, err
+	}
+
+	position, err := ps.portfolioDB.AddPosition(owner, id, db.Position{
+		Symbol:    symbol,
+		Quantity:  quantity,
+		CostBasis: costBasis,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &position, nil
+}
+
+func (ps *PortfolioService) UpdatePosition(owner string, id int64, symbol string, quantity decimal.Decimal, costBasis decimal.Decimal) (*db.Position, error) {
+	if err := validAmounts(quantity, costBasis); err != nil {
+		return nil, err
+	}
+
+	position, err := ps.portfolioDB.UpdatePosition(owner, id, db.Position{
+		Symbol:    strings.ToLower(symbol),
+		Quantity:  quantity,
+		CostBasis: costBasis,
+	}, checkLedger)
+	if err != nil {
+		return nil, err
+	}
+	return &position, nil
+}
+
+func (ps *PortfolioService) DeletePosition(owner string, id int64, symbol string) error {
+	return ps.portfolioDB.DeletePosition(owner, id, strings.ToLower(symbol), checkLedger)
+}
@@ -0,0 +1,258 @@
+package portfolio
+
+import (
+	"RESTCryptoServer/internal/db"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	MaxImportRows = 5000
+	maxNoteLength = 500
+)
+
+var (
+	ErrInvalidType       = errors.New("type must be buy, sell, transfer_in or transfer_out")
+	ErrInvalidSymbol     = errors.New("symbol is required")
+	ErrInvalidPrice      = errors.New("price must not be negative")
+	ErrInvalidFee        = errors.New("fee must not be negative")
+	ErrTransferOutAmount = errors.New("price and fee are not allowed on transfer_out")
+	ErrInvalidExecutedAt = errors.New("executed_at must be an RFC 3339 time or a date, not in the future")
+	ErrInvalidNote       = errors.New("note must be at most 500 characters")
+	ErrInvalidCSV        = errors.New("invalid CSV")
+	ErrTooManyRows       = errors.New("CSV has more than 5000 rows")
+)
+
+var transactionTypes = []string{db.TransactionBuy, db.TransactionSell, db.TransactionTransferIn, db.TransactionTransferOut}
+
+// TransactionInput is a ledger entry as entered. Price is per unit and Fee is a
+// total, both in the portfolio currency. An empty ExecutedAt means now.
+type TransactionInput struct {
+	Type       string          `json:"type"`
+	Symbol     string          `json:"symbol"`
+	CoinID     string          `json:"coin_id"`
+	Quantity   decimal.Decimal `json:"quantity"`
+	Price      decimal.Decimal `json:"price"`
+	Fee        decimal.Decimal `json:"fee"`
+	ExecutedAt string          `json:"executed_at"`
+	Note       string          `json:"note"`
+}
+
+// parseExecutedAt reads an RFC 3339 time or a date, which means midnight UTC.
+func parseExecutedAt(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return now, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t, err = time.Parse(time.DateOnly, value)
+	}
+	if err != nil || t.After(now) {
+		return time.Time{}, ErrInvalidExecutedAt
+	}
+	return t.UTC(), nil
+}
+
+func validTransaction(input TransactionInput, now time.Time) (db.Transaction, error) {
+	t := db.Transaction{
+		Type:     strings.ToLower(strings.TrimSpace(input.Type)),
+		Symbol:   strings.ToLower(strings.TrimSpace(input.Symbol)),
+		Quantity: input.Quantity,
+		Price:    input.Price,
+		Fee:      input.Fee,
+		Note:     strings.TrimSpace(input.Note),
+	}
+
+	if !slices.Contains(transactionTypes, t.Type) {
+		return db.Transaction{}, ErrInvalidType
+	}
+	if t.Symbol == "" {
+		return db.Transaction{}, ErrInvalidSymbol
+	}
+	if !t.Quantity.IsPositive() {
+		return db.Transaction{}, ErrInvalidQuantity
+	}
+	if t.Price.IsNegative() {
+		return db.Transaction{}, ErrInvalidPrice
+	}
+	if t.Fee.IsNegative() {
+		return db.Transaction{}, ErrInvalidFee
+	}
+	if t.Type == db.TransactionTransferOut && !(t.Price.IsZero() && t.Fee.IsZero()) {
+		return db.Transaction{}, ErrTransferOutAmount
+	}
+	if utf8.RuneCountInString(t.Note) > maxNoteLength {
+		return db.Transaction{}, ErrInvalidNote
+	}
+
+	executedAt, err := parseExecutedAt(strings.TrimSpace(input.ExecutedAt), now)
+	if err != nil {
+		return db.Transaction{}, err
+	}
+	t.ExecutedAt = executedAt
+
+	return t, nil
+}
+
+func (ps *PortfolioService) ListTransactions(owner string, id int64, symbol string) ([]db.Transaction, error) {
+	if _, err := ps.portfolioDB.Get(owner, id); err != nil {
+		return nil, err
+	}
+	return ps.portfolioDB.Transactions(id, strings.ToLower(symbol))
+}
+
+// AddTransaction records one ledger entry. Acquisitions start tracking their
+// symbol if needed; removals must be covered by the holding at that time.
+func (ps *PortfolioService) AddTransaction(ctx context.Context, owner string, id int64, input TransactionInput) (*db.Transaction, error) {
+	t, err := validTransaction(input, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := ps.addTransactions(ctx, owner, id, []db.Transaction{t}, map[string]string{t.Symbol: input.CoinID})
+	if err != nil {
+		return nil, err
+	}
+	return &transactions[0], nil
+}
+
+// ImportTransactions records the rows of a CSV file with a header naming the
+// type, symbol, quantity and executed_at columns and optionally price, fee,
+// note and coin_id. Either every row is recorded or none.
+func (ps *PortfolioService) ImportTransactions(ctx context.Context, owner string, id int64, r io.Reader) ([]db.Transaction, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrInvalidCSV, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range []string{"type", "symbol", "quantity", "executed_at"} {
+		if _, exists := columns[name]; !exists {
+			return nil, fmt.Errorf("%w: missing column %q", ErrInvalidCSV, name)
+		}
+	}
+
+	now := time.Now().UTC()
+	transactions := []db.Transaction{}
+	coinIDs := map[string]string{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+		}
+		line, _ := reader.FieldPos(0)
+		if len(transactions) == MaxImportRows {
+			return nil, ErrTooManyRows
+		}
+
+		field := func(name string) string {
+			if i, exists := columns[name]; exists {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		amount := func(name string) (decimal.Decimal, error) {
+			value := field(name)
+			if value == "" {
+				return decimal.Zero, nil
+			}
+			return decimal.NewFromString(value)
+		}
+
+		input := TransactionInput{
+			Type:       field("type"),
+			Symbol:     field("symbol"),
+			CoinID:     field("coin_id"),
+			ExecutedAt: field("executed_at"),
+			Note:       field("note"),
+		}
+		if input.ExecutedAt == "" {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidCSV, line, ErrInvalidExecutedAt)
+		}
+		if input.Quantity, err = amount("quantity"); err != nil {
+			return nil, fmt.Errorf("%w: line %d: invalid quantity", ErrInvalidCSV, line)
+		}
+		if input.Price, err = amount("price"); err != nil {
+			return nil, fmt.Errorf("%w: line %d: invalid price", ErrInvalidCSV, line)
+		}
+		if input.Fee, err = amount("fee"); err != nil {
+			return nil, fmt.Errorf("%w: line %d: invalid fee", ErrInvalidCSV, line)
+		}
+
+		t, err := validTransaction(input, now)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidCSV, line, err)
+		}
+		transactions = append(transactions, t)
+		if coinIDs[t.Symbol] == "" {
+			coinIDs[t.Symbol] = input.CoinID
+		}
+	}
+	if len(transactions) == 0 {
+		return nil, fmt.Errorf("%w: no rows", ErrInvalidCSV)
+	}
+
+	// Rows of a file are usually in execution order already; sorting keeps the
+	// ledger check independent of how the export was ordered.
+	slices.SortStableFunc(transactions, func(a, b db.Transaction) int {
+		return a.ExecutedAt.Compare(b.ExecutedAt)
+	})
+
+	return ps.addTransactions(ctx, owner, id, transactions, coinIDs)
+}
+
+// addTransactions checks the ledger with the new entries before the bought
+// coins are tracked, so a rejected import tracks nothing. The insert checks
+// the ledger again under the portfolio lock.
+func (ps *PortfolioService) addTransactions(ctx context.Context, owner string, id int64, transactions []db.Transaction, coinIDs map[string]string) ([]db.Transaction, error) {
+	if _, err := ps.portfolioDB.Get(owner, id); err != nil {
+		return nil, err
+	}
+
+	positions, err := ps.portfolioDB.Positions(id)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := ps.portfolioDB.Transactions(id, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := checkLedger(positions, append(existing, transactions...)); err != nil {
+		return nil, err
+	}
+
+	tracked := map[string]bool{}
+	for _, t := range transactions {
+		if tracked[t.Symbol] || (t.Type != db.TransactionBuy && t.Type != db.TransactionTransferIn) {
+			continue
+		}
+		if err := ps.cryptoService.EnsureTracked(ctx, t.Symbol, coinIDs[t.Symbol]); err != nil {
+			return nil, err
+		}
+		tracked[t.Symbol] = true
+	}
+
+	return ps.portfolioDB.AddTransactions(owner, id, transactions, checkLedger)
+}
+
+func (ps *PortfolioService) DeleteTransaction(owner string, id int64, transactionID int64) error {
+	return ps.portfolioDB.DeleteTransaction(owner, id, transactionID, checkLedger)
+}
@@ -0,0 +1,191 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	binanceDefaultURL        = "https://api.binance.com"
+	binanceQuoteAsset        = "USDT"
+	binanceChunkSize         = 100
+	binanceRequestsPerMinute = 1200
+)
+
+type binanceExchangeInfo struct {
+	Symbols []struct {
+		Symbol     string `json:"symbol"`
+		Status     string `json:"status"`
+		BaseAsset  string `json:"baseAsset"`
+		QuoteAsset string `json:"quoteAsset"`
+	} `json:"symbols"`
+}
+
+type binanceTicker24h struct {
+	Symbol             string `json:"symbol"`
+	LastPrice          string `json:"lastPrice"`
+	HighPrice          string `json:"highPrice"`
+	LowPrice           string `json:"lowPrice"`
+	PriceChangePercent string `json:"priceChangePercent"`
+	QuoteVolume        string `json:"quoteVolume"`
+}
+
+// Binance quotes every coin against USDT, which is reported as "usd". Coin IDs
+// are trading pair names such as BTCUSDT.
+type Binance struct {
+	baseURL string
+	client  *Client
+}
+
+func NewBinance(baseURL string) *Binance {
+	if baseURL == "" {
+		baseURL = binanceDefaultURL
+	}
+
+	return &Binance{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  NewClient("binance", binanceRequestsPerMinute),
+	}
+}
+
+func (b *Binance) Name() string {
+	return "binance"
+}
+
+func (b *Binance) ListCoins(ctx context.Context) ([]CoinInfo, error) {
+	var info binanceExchangeInfo
+	if err := b.client.GetJSON(ctx, b.baseURL+"/api/v3/exchangeInfo", &info); err != nil {
+		log.Println("error during getting binance exchange info: ", err)
+		return nil, err
+	}
+
+	var coins []CoinInfo
+	for _, s := range info.Symbols {
+		if s.QuoteAsset != binanceQuoteAsset || s.Status != "TRADING" {
+			continue
+		}
+		coins = append(coins, CoinInfo{
+			ID:     s.Symbol,
+			Symbol: strings.ToLower(s.BaseAsset),
+			Name:   s.BaseAsset,
+		})
+	}
+	return coins, nil
+}
+
+func (b *Binance) MatchSymbol(symbol string, coins []CoinInfo) (CoinInfo, error) {
+	symbol = strings.ToLower(symbol)
+	for _, coin := range coins {
+		if coin.Symbol == symbol {
+			return coin, nil
+		}
+	}
+
+	return CoinInfo{}, fmt.Errorf("%w: symbol %s", ErrCoinNotFound, symbol)
+}
+
+func (b *Binance) ResolveSymbol(ctx context.Context, symbol string) (CoinInfo, error) {
+	coins, err := b.ListCoins(ctx)
+	if err != nil {
+		return CoinInfo{}, fmt.Errorf("failed to get coin list: %w", err)
+	}
+	return b.MatchSymbol(symbol, coins)
+}
+
+func (b *Binance) ResolveSymbols(ctx context.Context, symbols []string) (map[string]CoinInfo, error) {
+	coins, err := b.ListCoins(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get coin list: %w", err)
+	}
+	return MatchSymbols(b, coins, symbols), nil
+}
+
+// GetQuotes only supports "usd"; other requested currencies are ignored.
+func (b *Binance) GetQuotes(ctx context.Context, ids []string, currencies []string) (map[string]Quote, error) {
+	quotes := make(map[string]Quote)
+	for _, batch := range chunk(ids, binanceChunkSize) {
+		if err := b.getQuotes(ctx, batch, quotes); err != nil {
+			return nil, err
+		}
+	}
+	return quotes, nil
+}
+
+func (b *Binance) getQuotes(ctx context.Context, ids []string, quotes map[string]Quote) error {
+	symbols, err := json.Marshal(ids)
+	if err != nil {
+		return err
+	}
+
+	var tickers []binanceTicker24h
+	endpoint := b.baseURL + "/api/v3/ticker/24hr?symbols=" + url.QueryEscape(string(symbols))
+	if err := b.client.GetJSON(ctx, endpoint, &tickers); err != nil {
+		log.Println("error during getting binance prices: ", err)
+		return err
+	}
+
+	for _, ticker := range tickers {
+		price, err := strconv.ParseFloat(ticker.LastPrice, 64)
+		if err != nil {
+			log.Printf("Warning: could not parse price for %s: %v", ticker.Symbol, err)
+			continue
+		}
+
+		// Binance does not report market cap.
+		market := MarketData{}
+		market.Volume24h, _ = strconv.ParseFloat(ticker.QuoteVolume, 64)
+		market.High24h, _ = strconv.ParseFloat(ticker.HighPrice, 64)
+		market.Low24h, _ = strconv.ParseFloat(ticker.LowPrice, 64)
+		market.PriceChangePercent24h, _ = strconv.ParseFloat(ticker.PriceChangePercent, 64)
+
+		quotes[ticker.Symbol] = Quote{Prices: map[string]float64{"usd": price}, Market: market}
+	}
+	return nil
+}
+
+// GetMarketChart reads hourly klines for up to 41 days and daily klines beyond,
+// staying within the 1000 candle limit of a single request.
+func (b *Binance) GetMarketChart(ctx context.Context, id string, days int) ([]PricePoint, error) {
+	interval, step := "1h", time.Hour
+	if days > 41 {
+		interval, step = "1d", 24*time.Hour
+	}
+
+	start := time.Now().Add(-time.Duration(days) * 24 * time.Hour)
+	endpoint := fmt.Sprintf("%s/api/v3/klines?symbol=%s&interval=%s&startTime=%d&limit=1000",
+		b.baseURL, url.QueryEscape(id), interval, start.UnixMilli())
+
+	var klines [][]any
+	if err := b.client.GetJSON(ctx, endpoint, &klines); err != nil {
+		log.Println("error during getting binance klines: ", err)
+		return nil, err
+	}
+
+	points := make([]PricePoint, 0, len(klines))
+	for _, kline := range klines {
+		if len(kline) < 5 {
+			continue
+		}
+		openTime, err := parsePrice(kline[0])
+		if err != nil {
+			continue
+		}
+		price, err := parsePrice(kline[4])
+		if err != nil {
+			log.Printf("Warning: could not parse close price for %s: %v", id, err)
+			continue
+		}
+
+		points = append(points, PricePoint{
+			Timestamp: time.UnixMilli(int64(openTime)).Add(step).UTC(),
+			Price:     price,
+		})
+	}
+	return points, nil
+}
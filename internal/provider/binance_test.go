@@ -0,0 +1,146 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func newTestBinance(t *testing.T, routes map[string]http.HandlerFunc) *Binance {
+	t.Helper()
+
+	b := NewBinance(newTestServer(t, routes).URL)
+	b.client = newTestClient(0)
+	return b
+}
+
+const binanceExchangeInfoBody = `{"symbols": [
+	{"symbol": "BTCUSDT", "status": "TRADING", "baseAsset": "BTC", "quoteAsset": "USDT"},
+	{"symbol": "BTCEUR", "status": "TRADING", "baseAsset": "BTC", "quoteAsset": "EUR"},
+	{"symbol": "ETHUSDT", "status": "TRADING", "baseAsset": "ETH", "quoteAsset": "USDT"},
+	{"symbol": "LUNAUSDT", "status": "BREAK", "baseAsset": "LUNA", "quoteAsset": "USDT"}
+]}`
+
+func TestBinanceResolveSymbol(t *testing.T) {
+	b := newTestBinance(t, map[string]http.HandlerFunc{
+		"/api/v3/exchangeInfo": respond(binanceExchangeInfoBody),
+	})
+
+	tests := []struct {
+		name    string
+		symbol  string
+		wantID  string
+		wantErr error
+	}{
+		{"usdt pair", "btc", "BTCUSDT", nil},
+		{"upper case symbol", "ETH", "ETHUSDT", nil},
+		{"pair not trading", "luna", "", ErrCoinNotFound},
+		{"unknown symbol", "nope", "", ErrCoinNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coin, err := b.ResolveSymbol(context.Background(), tt.symbol)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if coin.ID != tt.wantID {
+				t.Errorf("id = %q, want %q", coin.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestBinanceListCoins(t *testing.T) {
+	b := newTestBinance(t, map[string]http.HandlerFunc{
+		"/api/v3/exchangeInfo": respond(binanceExchangeInfoBody),
+	})
+
+	coins, err := b.ListCoins(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []CoinInfo{
+		{ID: "BTCUSDT", Symbol: "btc", Name: "BTC"},
+		{ID: "ETHUSDT", Symbol: "eth", Name: "ETH"},
+	}
+	if len(coins) != len(want) {
+		t.Fatalf("coins = %v, want %v", coins, want)
+	}
+	for i := range want {
+		if coins[i] != want[i] {
+			t.Errorf("coin %d = %+v, want %+v", i, coins[i], want[i])
+		}
+	}
+}
+
+func TestBinanceGetQuotes(t *testing.T) {
+	b := newTestBinance(t, map[string]http.HandlerFunc{
+		"/api/v3/ticker/24hr": func(w http.ResponseWriter, r *http.Request) {
+			if got := r.URL.Query().Get("symbols"); got != `["BTCUSDT","ETHUSDT"]` {
+				t.Errorf("symbols = %q, want a JSON array of the pairs", got)
+			}
+			respond(`[
+				{"symbol": "BTCUSDT", "lastPrice": "60000.50", "highPrice": "61000", "lowPrice": "59000",
+				 "priceChangePercent": "-1.25", "quoteVolume": "123456.7"},
+				{"symbol": "ETHUSDT", "lastPrice": "not a number"}
+			]`)(w, r)
+		},
+	})
+
+	quotes, err := b.GetQuotes(context.Background(), []string{"BTCUSDT", "ETHUSDT"}, []string{"usd", "eur"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(quotes) != 1 {
+		t.Fatalf("quotes = %v, want BTCUSDT only", quotes)
+	}
+	quote := quotes["BTCUSDT"]
+	if len(quote.Prices) != 1 || quote.Prices["usd"] != 60000.50 {
+		t.Errorf("prices = %v, want usd 60000.50 only", quote.Prices)
+	}
+	want := MarketData{Volume24h: 123456.7, High24h: 61000, Low24h: 59000, PriceChangePercent24h: -1.25}
+	if quote.Market != want {
+		t.Errorf("market = %+v, want %+v", quote.Market, want)
+	}
+}
+
+func TestBinanceGetMarketChart(t *testing.T) {
+	tests := []struct {
+		name         string
+		days         int
+		wantInterval string
+		wantStep     time.Duration
+	}{
+		{"hourly klines", 7, "1h", time.Hour},
+		{"daily klines", 90, "1d", 24 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBinance(t, map[string]http.HandlerFunc{
+				"/api/v3/klines": func(w http.ResponseWriter, r *http.Request) {
+					query := r.URL.Query()
+					if query.Get("symbol") != "BTCUSDT" || query.Get("interval") != tt.wantInterval {
+						t.Errorf("query = %v, want BTCUSDT at %s", query, tt.wantInterval)
+					}
+					respond(`[[1700000000000, "100", "110", "90", "105", "1"], [1700003600000, "105"]]`)(w, r)
+				},
+			})
+
+			points, err := b.GetMarketChart(context.Background(), "BTCUSDT", tt.days)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			want := PricePoint{Timestamp: time.UnixMilli(1700000000000).Add(tt.wantStep).UTC(), Price: 105}
+			if len(points) != 1 || points[0] != want {
+				t.Errorf("points = %+v, want the close at the end of the kline %+v", points, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package provider
+
+import (
+	"RESTCryptoServer/monitoring"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("price provider circuit open")
+
+type BreakerState int
+
+const (
+	BreakerClosed BreakerState = iota
+	BreakerHalfOpen
+	BreakerOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerHalfOpen:
+		return "half_open"
+	default:
+		return "open"
+	}
+}
+
+// CircuitBreaker wraps a provider and stops calling it after repeated upstream
+// failures. While open, calls fail immediately with ErrCircuitOpen, which also
+// matches ErrUpstreamUnavailable. After the cool-down a single trial call is let
+// through (half-open) and its outcome closes or re-opens the circuit.
+type CircuitBreaker struct {
+	PriceProvider
+
+	failureThreshold int
+	openDuration     time.Duration
+
+	mu          sync.Mutex
+	state       BreakerState
+	failures    int
+	openedAt    time.Time
+	trialActive bool
+}
+
+func NewCircuitBreaker(priceProvider PriceProvider) *CircuitBreaker {
+	threshold := 3
+	if value, err := strconv.Atoi(os.Getenv("BREAKER_FAILURE_THRESHOLD")); err == nil && value > 0 {
+		threshold = value
+	}
+
+	openDuration := 60 * time.Second
+	if seconds, err := strconv.Atoi(os.Getenv("BREAKER_OPEN_SECONDS")); err == nil && seconds > 0 {
+		openDuration = time.Duration(seconds) * time.Second
+	}
+
+	cb := &CircuitBreaker{
+		PriceProvider:    priceProvider,
+		failureThreshold: threshold,
+		openDuration:     openDuration,
+	}
+	monitoring.SetCircuitBreakerState(priceProvider.Name(), int(BreakerClosed))
+	return cb
+}
+
+func (cb *CircuitBreaker) State() BreakerState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == BreakerOpen && time.Since(cb.openedAt) >= cb.openDuration {
+		return BreakerHalfOpen
+	}
+	return cb.state
+}
+
+// RetryAt reports when an open circuit lets the next trial call through.
+func (cb *CircuitBreaker) RetryAt() time.Time {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state != BreakerOpen {
+		return time.Time{}
+	}
+	return cb.openedAt.Add(cb.openDuration)
+}
+
+func (cb *CircuitBreaker) HealthCheck() monitoring.CheckResult {
+	state := cb.State()
+	if state == BreakerClosed {
+		return monitoring.CheckResult{Status: "healthy"}
+	}
+
+	message := fmt.Sprintf("%s circuit %s", cb.PriceProvider.Name(), state)
+	if retryAt := cb.RetryAt(); !retryAt.IsZero() && state == BreakerOpen {
+		message += ", next attempt at " + retryAt.UTC().Format(time.RFC3339)
+	}
+	return monitoring.CheckResult{Status: "degraded", Message: message}
+}
+
+func (cb *CircuitBreaker) allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case BreakerClosed:
+		return nil
+	case BreakerOpen:
+		if time.Since(cb.openedAt) < cb.openDuration {
+			return fmt.Errorf("%w: %w", ErrUpstreamUnavailable, ErrCircuitOpen)
+		}
+		cb.setState(BreakerHalfOpen)
+	}
+
+	if cb.trialActive {
+		return fmt.Errorf("%w: %w", ErrUpstreamUnavailable, ErrCircuitOpen)
+	}
+	cb.trialActive = true
+	return nil
+}
+
+func (cb *CircuitBreaker) record(err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.trialActive = false
+
+	// A cancelled call says nothing about the upstream, so it leaves the
+	// circuit as it is.
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+
+	if err == nil || !retryable(err) {
+		cb.failures = 0
+		if cb.state != BreakerClosed {
+			cb.setState(BreakerClosed)
+		}
+		return
+	}
+
+	cb.failures++
+	if cb.state == BreakerHalfOpen || cb.failures >= cb.failureThreshold {
+		cb.openedAt = time.Now()
+		cb.setState(BreakerOpen)
+	}
+}
+
+func (cb *CircuitBreaker) setState(state BreakerState) {
+	if cb.state == state {
+		return
+	}
+
+	log.Printf("Circuit breaker for %s: %s -> %s", cb.PriceProvider.Name(), cb.state, state)
+	cb.state = state
+	monitoring.SetCircuitBreakerState(cb.PriceProvider.Name(), int(state))
+	monitoring.RecordCircuitBreakerTransition(cb.PriceProvider.Name(), state.String())
+}
+
+func (cb *CircuitBreaker) ListCoins(ctx context.Context) ([]CoinInfo, error) {
+	if err := cb.allow(); err != nil {
+		return nil, err
+	}
+
+	coins, err := cb.PriceProvider.ListCoins(ctx)
+	cb.record(err)
+	return coins, err
+}
+
+func (cb *CircuitBreaker) ResolveSymbol(ctx context.Context, symbol string) (CoinInfo, error) {
+	if err := cb.allow(); err != nil {
+		return CoinInfo{}, err
+	}
+
+	coin, err := cb.PriceProvider.ResolveSymbol(ctx, symbol)
+	cb.record(err)
+	return coin, err
+}
+
+func (cb *CircuitBreaker) ResolveSymbols(ctx context.Context, symbols []string) (map[string]CoinInfo, error) {
+	if err := cb.allow(); err != nil {
+		return nil, err
+	}
+
+	coins, err := cb.PriceProvider.ResolveSymbols(ctx, symbols)
+	cb.record(err)
+	return coins, err
+}
+
+func (cb *CircuitBreaker) GetQuotes(ctx context.Context, ids []string, currencies []string) (map[string]Quote, error) {
+	if err := cb.allow(); err != nil {
+		return nil, err
+	}
+
+	quotes, err := cb.PriceProvider.GetQuotes(ctx, ids, currencies)
+	cb.record(err)
+	return quotes, err
+}
+
+func (cb *CircuitBreaker) GetMarketChart(ctx context.Context, id string, days int) ([]PricePoint, error) {
+	if err := cb.allow(); err != nil {
+		return nil, err
+	}
+
+	points, err := cb.PriceProvider.GetMarketChart(ctx, id, days)
+	cb.record(err)
+	return points, err
+}
@@ -0,0 +1,289 @@
+package provider
+
+import (
+	"RESTCryptoServer/monitoring"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	ErrRateLimited         = errors.New("upstream rate limit exceeded")
+	ErrNotFound            = errors.New("upstream resource not found")
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+)
+
+// UpstreamError carries the HTTP status of a failed upstream call. It unwraps to
+// ErrRateLimited, ErrNotFound or ErrUpstreamUnavailable.
+type UpstreamError struct {
+	StatusCode int
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *UpstreamError) Error() string {
+	if e.StatusCode == 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%v (status %d)", e.Err, e.StatusCode)
+}
+
+func (e *UpstreamError) Unwrap() error {
+	return e.Err
+}
+
+// RetryAfter reports how long the upstream asked us to back off, if it did.
+func RetryAfter(err error) time.Duration {
+	var upstreamErr *UpstreamError
+	if errors.As(err, &upstreamErr) {
+		return upstreamErr.RetryAfter
+	}
+	return 0
+}
+
+const maxRetryAfter = 30 * time.Second
+
+type Client struct {
+	name        string
+	httpClient  *http.Client
+	limiter     *tokenBucket
+	timeout     time.Duration
+	maxRetries  int
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+}
+
+// NewClient builds an upstream client for the named provider. requestsPerMinute is
+// the provider default and can be overridden with PROVIDER_RATE_LIMIT_PER_MINUTE.
+func NewClient(name string, requestsPerMinute int) *Client {
+	timeout := 10 * time.Second
+	if seconds, err := strconv.Atoi(os.Getenv("PROVIDER_TIMEOUT_SECONDS")); err == nil && seconds > 0 {
+		timeout = time.Duration(seconds) * time.Second
+	}
+
+	maxRetries := 3
+	if retries, err := strconv.Atoi(os.Getenv("PROVIDER_MAX_RETRIES")); err == nil && retries >= 0 {
+		maxRetries = retries
+	}
+
+	if limit, err := strconv.Atoi(os.Getenv("PROVIDER_RATE_LIMIT_PER_MINUTE")); err == nil && limit > 0 {
+		requestsPerMinute = limit
+	}
+
+	return &Client{
+		name:        name,
+		httpClient:  &http.Client{},
+		limiter:     newTokenBucket(requestsPerMinute),
+		timeout:     timeout,
+		maxRetries:  maxRetries,
+		baseBackoff: 500 * time.Millisecond,
+		maxBackoff:  10 * time.Second,
+	}
+}
+
+// GetJSON fetches url into target, retrying rate limits and upstream failures.
+// Cancelling ctx aborts the request and any wait between attempts, and returns
+// the context error.
+func (c *Client) GetJSON(ctx context.Context, url string, target any) error {
+	var lastErr error
+
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			wait := c.backoff(attempt)
+			if retryAfter := RetryAfter(lastErr); retryAfter > 0 {
+				wait = retryAfter
+			}
+			if err := sleep(ctx, wait); err != nil {
+				return err
+			}
+		}
+
+		ok, err := c.limiter.Wait(ctx, c.timeout)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return &UpstreamError{Err: fmt.Errorf("%w: client-side limit for %s", ErrRateLimited, c.name)}
+		}
+
+		err = c.get(ctx, url, target)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		if !retryable(err) {
+			return err
+		}
+		if RetryAfter(err) > maxRetryAfter {
+			return err
+		}
+	}
+
+	return lastErr
+}
+
+func (c *Client) get(ctx context.Context, url string, target any) error {
+	requestCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(requestCtx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		monitoring.LogExternalAPICall(c.name, req.URL.Path, 0, time.Since(start), err)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return &UpstreamError{Err: fmt.Errorf("%w: %v", ErrUpstreamUnavailable, err)}
+	}
+	defer resp.Body.Close()
+
+	upstreamErr := statusError(resp)
+	if upstreamErr != nil {
+		io.Copy(io.Discard, resp.Body)
+		monitoring.LogExternalAPICall(c.name, req.URL.Path, resp.StatusCode, time.Since(start), upstreamErr)
+		return upstreamErr
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		err = &UpstreamError{StatusCode: resp.StatusCode, Err: fmt.Errorf("%w: invalid response: %v", ErrUpstreamUnavailable, err)}
+		monitoring.LogExternalAPICall(c.name, req.URL.Path, resp.StatusCode, time.Since(start), err)
+		return err
+	}
+
+	monitoring.LogExternalAPICall(c.name, req.URL.Path, resp.StatusCode, time.Since(start), nil)
+	return nil
+}
+
+// sleep waits for d or until ctx is done, returning the context error then.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func statusError(resp *http.Response) *UpstreamError {
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return &UpstreamError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+			Err:        ErrRateLimited,
+		}
+	case resp.StatusCode == http.StatusNotFound:
+		return &UpstreamError{StatusCode: resp.StatusCode, Err: ErrNotFound}
+	case resp.StatusCode >= 500:
+		return &UpstreamError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+			Err:        ErrUpstreamUnavailable,
+		}
+	default:
+		return &UpstreamError{StatusCode: resp.StatusCode, Err: fmt.Errorf("unexpected upstream status")}
+	}
+}
+
+func retryable(err error) bool {
+	return errors.Is(err, ErrRateLimited) || errors.Is(err, ErrUpstreamUnavailable)
+}
+
+// backoff returns an exponential delay with full jitter for the given attempt.
+func (c *Client) backoff(attempt int) time.Duration {
+	ceiling := c.baseBackoff << (attempt - 1)
+	if ceiling <= 0 || ceiling > c.maxBackoff {
+		ceiling = c.maxBackoff
+	}
+	return time.Duration(rand.Int63n(int64(ceiling))) + time.Millisecond
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
+// tokenBucket keeps us under the upstream request quota.
+type tokenBucket struct {
+	mu       sync.Mutex
+	capacity float64
+	tokens   float64
+	rate     float64
+	last     time.Time
+}
+
+func newTokenBucket(requestsPerMinute int) *tokenBucket {
+	capacity := float64(requestsPerMinute)
+	return &tokenBucket{
+		capacity: capacity,
+		tokens:   capacity,
+		rate:     capacity / 60,
+		last:     time.Now(),
+	}
+}
+
+// Wait takes a token, sleeping until one is available. It gives up and returns
+// false when that would take longer than maxWait, and returns the context
+// error when ctx is done first.
+func (tb *tokenBucket) Wait(ctx context.Context, maxWait time.Duration) (bool, error) {
+	tb.mu.Lock()
+
+	now := time.Now()
+	tb.tokens = min(tb.capacity, tb.tokens+now.Sub(tb.last).Seconds()*tb.rate)
+	tb.last = now
+
+	tb.tokens--
+	if tb.tokens >= 0 {
+		tb.mu.Unlock()
+		return true, nil
+	}
+
+	wait := time.Duration(-tb.tokens / tb.rate * float64(time.Second))
+	if wait > maxWait {
+		tb.tokens++
+		tb.mu.Unlock()
+		return false, nil
+	}
+	tb.mu.Unlock()
+
+	if err := sleep(ctx, wait); err != nil {
+		tb.mu.Lock()
+		tb.tokens++
+		tb.mu.Unlock()
+		return false, err
+	}
+	return true, nil
+}
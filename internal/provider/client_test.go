@@ -0,0 +1,253 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client with millisecond backoff and no practical
+// rate limit, so retries do not slow the tests down.
+func newTestClient(maxRetries int) *Client {
+	return &Client{
+		name:        "test",
+		httpClient:  &http.Client{},
+		limiter:     newTokenBucket(60000),
+		timeout:     5 * time.Second,
+		maxRetries:  maxRetries,
+		baseBackoff: time.Millisecond,
+		maxBackoff:  5 * time.Millisecond,
+	}
+}
+
+// countingServer answers every request with handler, which is passed the
+// number of the request starting at 1, and counts the requests.
+func countingServer(t *testing.T, handler func(w http.ResponseWriter, r *http.Request, call int32)) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(w, r, calls.Add(1))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestClientGetJSONErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		wantErr   error
+		wantCalls int32
+	}{
+		{"not found is not retried", http.StatusNotFound, `{}`, ErrNotFound, 1},
+		{"rate limit is retried", http.StatusTooManyRequests, `{}`, ErrRateLimited, 3},
+		{"server error is retried", http.StatusServiceUnavailable, `{}`, ErrUpstreamUnavailable, 3},
+		{"invalid body is retried", http.StatusOK, `{"id":`, ErrUpstreamUnavailable, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := countingServer(t, func(w http.ResponseWriter, r *http.Request, call int32) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			var target map[string]any
+			err := newTestClient(2).GetJSON(context.Background(), server.URL, &target)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestClientGetJSONUnexpectedStatus(t *testing.T) {
+	server, calls := countingServer(t, func(w http.ResponseWriter, r *http.Request, call int32) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	var target map[string]any
+	err := newTestClient(2).GetJSON(context.Background(), server.URL, &target)
+
+	var upstreamErr *UpstreamError
+	if !errors.As(err, &upstreamErr) || upstreamErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("error = %v, want an UpstreamError with status 400", err)
+	}
+	if retryable(err) {
+		t.Errorf("error %v should not be retryable", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
+
+func TestClientGetJSONRecovers(t *testing.T) {
+	server, calls := countingServer(t, func(w http.ResponseWriter, r *http.Request, call int32) {
+		if r.Header.Get("Accept") != "application/json" {
+			t.Errorf("Accept = %q, want application/json", r.Header.Get("Accept"))
+		}
+		if call < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"price": 42}`))
+	})
+
+	var target struct {
+		Price float64 `json:"price"`
+	}
+	if err := newTestClient(3).GetJSON(context.Background(), server.URL, &target); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if target.Price != 42 {
+		t.Errorf("price = %v, want 42", target.Price)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("calls = %d, want 3", got)
+	}
+}
+
+func TestClientRetryAfter(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         int
+		retryAfter     string
+		wantErr        error
+		wantCalls      int32
+		wantRetryAfter time.Duration
+	}{
+		{"short delay is honoured", http.StatusTooManyRequests, "0", nil, 2, 0},
+		{"long delay is not waited for", http.StatusTooManyRequests, "120", ErrRateLimited, 1, 120 * time.Second},
+		{"long delay on server error", http.StatusServiceUnavailable, "3600", ErrUpstreamUnavailable, 1, time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := countingServer(t, func(w http.ResponseWriter, r *http.Request, call int32) {
+				if call > 1 {
+					w.Write([]byte(`{}`))
+					return
+				}
+				w.Header().Set("Retry-After", tt.retryAfter)
+				w.WriteHeader(tt.status)
+			})
+
+			var target map[string]any
+			err := newTestClient(2).GetJSON(context.Background(), server.URL, &target)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if got := RetryAfter(err); got != tt.wantRetryAfter {
+				t.Errorf("RetryAfter = %v, want %v", got, tt.wantRetryAfter)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		min   time.Duration
+		max   time.Duration
+	}{
+		{"empty", "", 0, 0},
+		{"seconds", "7", 7 * time.Second, 7 * time.Second},
+		{"negative seconds", "-3", 0, 0},
+		{"garbage", "soon", 0, 0},
+		{"http date", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat), 58 * time.Second, time.Minute},
+		{"past http date", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseRetryAfter(tt.value)
+			if got < tt.min || got > tt.max {
+				t.Errorf("parseRetryAfter(%q) = %v, want between %v and %v", tt.value, got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestClientBackoff(t *testing.T) {
+	c := &Client{baseBackoff: 500 * time.Millisecond, maxBackoff: 10 * time.Second}
+
+	tests := []struct {
+		attempt int
+		ceiling time.Duration
+	}{
+		{1, 500 * time.Millisecond},
+		{2, time.Second},
+		{3, 2 * time.Second},
+		{5, 8 * time.Second},
+		{6, 10 * time.Second},
+		{70, 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			got := c.backoff(tt.attempt)
+			if got <= 0 || got > tt.ceiling+time.Millisecond {
+				t.Fatalf("backoff(%d) = %v, want in (0, %v]", tt.attempt, got, tt.ceiling+time.Millisecond)
+			}
+		}
+	}
+}
+
+func TestClientGetJSONCancel(t *testing.T) {
+	server, _ := countingServer(t, func(w http.ResponseWriter, r *http.Request, call int32) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	c := newTestClient(3)
+	c.baseBackoff = time.Hour
+	c.maxBackoff = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	var target map[string]any
+	err := c.GetJSON(ctx, server.URL, &target)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("GetJSON returned after %v, want it to stop with the context", elapsed)
+	}
+}
+
+func TestTokenBucketWait(t *testing.T) {
+	tb := newTokenBucket(60)
+	for i := 0; i < 60; i++ {
+		if ok, err := tb.Wait(context.Background(), 0); !ok || err != nil {
+			t.Fatalf("token %d: Wait = %v, %v, want a token", i, ok, err)
+		}
+	}
+
+	if ok, err := tb.Wait(context.Background(), 100*time.Millisecond); ok || err != nil {
+		t.Errorf("empty bucket: Wait = %v, %v, want false without waiting a second", ok, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if ok, err := tb.Wait(ctx, 5*time.Second); ok || !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled: Wait = %v, %v, want %v", ok, err, context.Canceled)
+	}
+
+	if ok, err := tb.Wait(context.Background(), 5*time.Second); !ok || err != nil {
+		t.Errorf("Wait = %v, %v, want a token after about a second", ok, err)
+	}
+}
@@ -0,0 +1,314 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	coinGeckoDefaultURL = "https://api.coingecko.com/api/v3"
+	coinGeckoChunkSize  = 250
+	// Public API free tier allows roughly 30 calls per minute.
+	coinGeckoRequestsPerMinute = 30
+)
+
+var popularCryptoMap = map[string]string{
+	"btc":  "bitcoin",
+	"eth":  "ethereum",
+	"usdt": "tether",
+	"bnb":  "binancecoin",
+	"sol":  "solana",
+	"usdc": "usd-coin",
+	"xrp":  "ripple",
+	"doge": "dogecoin",
+	"ada":  "cardano",
+	"trx":  "tron",
+	"avax": "avalanche-2",
+	"shib": "shiba-inu",
+	"dot":  "polkadot",
+	"link": "chainlink",
+	"ltc":  "litecoin",
+}
+
+func IsPopularCoin(id string) bool {
+	for _, popularID := range popularCryptoMap {
+		if popularID == id {
+			return true
+		}
+	}
+	return false
+}
+
+type CoinGecko struct {
+	baseURL string
+	client  *Client
+}
+
+func NewCoinGecko(baseURL string) *CoinGecko {
+	if baseURL == "" {
+		baseURL = coinGeckoDefaultURL
+	}
+
+	return &CoinGecko{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  NewClient("coingecko", coinGeckoRequestsPerMinute),
+	}
+}
+
+func (cg *CoinGecko) Name() string {
+	return "coingecko"
+}
+
+func (cg *CoinGecko) ListCoins(ctx context.Context) ([]CoinInfo, error) {
+	var coins []CoinInfo
+	if err := cg.client.GetJSON(ctx, cg.baseURL+"/coins/list", &coins); err != nil {
+		log.Println("error during getting coin list: ", err)
+		return nil, err
+	}
+	return coins, nil
+}
+
+func (cg *CoinGecko) MatchSymbol(symbol string, coins []CoinInfo) (CoinInfo, error) {
+	coinID, err := GetIDBySymbol(symbol, coins)
+	if err != nil {
+		return CoinInfo{}, err
+	}
+
+	for _, coin := range coins {
+		if coin.ID == coinID {
+			return coin, nil
+		}
+	}
+
+	return CoinInfo{ID: coinID, Symbol: strings.ToLower(symbol), Name: strings.ToUpper(symbol)}, nil
+}
+
+func (cg *CoinGecko) ResolveSymbol(ctx context.Context, symbol string) (CoinInfo, error) {
+	coins, err := cg.ListCoins(ctx)
+	if err != nil {
+		return CoinInfo{}, fmt.Errorf("failed to get coin list: %w", err)
+	}
+	return cg.MatchSymbol(symbol, coins)
+}
+
+func (cg *CoinGecko) ResolveSymbols(ctx context.Context, symbols []string) (map[string]CoinInfo, error) {
+	coins, err := cg.ListCoins(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get coin list: %w", err)
+	}
+	return MatchSymbols(cg, coins, symbols), nil
+}
+
+func GetIDBySymbol(symbol string, coins []CoinInfo) (string, error) {
+	symbol = strings.ToLower(symbol)
+
+	if popularID, exists := popularCryptoMap[symbol]; exists {
+		log.Printf("Using predefined mapping for %s -> %s", symbol, popularID)
+		return popularID, nil
+	}
+
+	var matches []CoinInfo
+	for _, coin := range coins {
+		if coin.Symbol == symbol {
+			matches = append(matches, coin)
+		}
+	}
+
+	if len(matches) == 0 {
+		return "", fmt.Errorf("%w: symbol %s", ErrCoinNotFound, symbol)
+	}
+
+	if len(matches) > 1 {
+		log.Printf("Found %d coins with symbol %s:", len(matches), symbol)
+		for _, match := range matches {
+			log.Printf("  - ID: %s, Name: %s", match.ID, match.Name)
+		}
+
+		for _, match := range matches {
+			lowerName := strings.ToLower(match.Name)
+			if symbol == "btc" && strings.Contains(lowerName, "bitcoin") {
+				log.Printf("Selected Bitcoin: %s", match.ID)
+				return match.ID, nil
+			}
+			if symbol == "eth" && strings.Contains(lowerName, "ethereum") {
+				log.Printf("Selected Ethereum: %s", match.ID)
+				return match.ID, nil
+			}
+		}
+	}
+
+	log.Printf("Using first match for %s: %s (%s)", symbol, matches[0].ID, matches[0].Name)
+	return matches[0].ID, nil
+}
+
+func parsePrice(value any) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	default:
+		return 0, fmt.Errorf("cannot convert %T to float64", value)
+	}
+}
+
+func (cg *CoinGecko) GetQuotes(ctx context.Context, ids []string, currencies []string) (map[string]Quote, error) {
+	quotes := make(map[string]Quote)
+	for _, batch := range chunk(ids, coinGeckoChunkSize) {
+		if err := cg.getQuotes(ctx, batch, currencies, quotes); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Printf("Parsed prices for %d of %d coins", len(quotes), len(ids))
+	return quotes, nil
+}
+
+type coinGeckoMarket struct {
+	ID                       string  `json:"id"`
+	CurrentPrice             float64 `json:"current_price"`
+	MarketCap                float64 `json:"market_cap"`
+	TotalVolume              float64 `json:"total_volume"`
+	High24h                  float64 `json:"high_24h"`
+	Low24h                   float64 `json:"low_24h"`
+	PriceChangePercentage24h float64 `json:"price_change_percentage_24h"`
+}
+
+func (cg *CoinGecko) getQuotes(ctx context.Context, ids []string, currencies []string, quotes map[string]Quote) error {
+	var others []string
+	withUSD := false
+	for _, currency := range currencies {
+		if currency == "usd" {
+			withUSD = true
+			continue
+		}
+		others = append(others, currency)
+	}
+
+	if withUSD {
+		if err := cg.getMarkets(ctx, ids, quotes); err != nil {
+			return err
+		}
+	}
+	if len(others) == 0 {
+		return nil
+	}
+	return cg.getPrices(ctx, ids, others, quotes)
+}
+
+// getMarkets fetches the USD price together with the 24h market data.
+func (cg *CoinGecko) getMarkets(ctx context.Context, ids []string, quotes map[string]Quote) error {
+	url := fmt.Sprintf("%s/coins/markets?vs_currency=usd&ids=%s&per_page=%d", cg.baseURL, strings.Join(ids, ","), coinGeckoChunkSize)
+
+	log.Printf("Fetching markets from: %s", url)
+
+	var markets []coinGeckoMarket
+	if err := cg.client.GetJSON(ctx, url, &markets); err != nil {
+		log.Println("error during getting coin markets: ", err)
+		return err
+	}
+
+	for _, market := range markets {
+		quote := quotes[market.ID]
+		if quote.Prices == nil {
+			quote.Prices = make(map[string]float64)
+		}
+		quote.Prices["usd"] = market.CurrentPrice
+		quote.Market = MarketData{
+			MarketCap:             market.MarketCap,
+			Volume24h:             market.TotalVolume,
+			High24h:               market.High24h,
+			Low24h:                market.Low24h,
+			PriceChangePercent24h: market.PriceChangePercentage24h,
+		}
+		quotes[market.ID] = quote
+	}
+
+	return nil
+}
+
+func (cg *CoinGecko) getPrices(ctx context.Context, ids []string, currencies []string, quotes map[string]Quote) error {
+	url := fmt.Sprintf("%s/simple/price?ids=%s&vs_currencies=%s", cg.baseURL, strings.Join(ids, ","), strings.Join(currencies, ","))
+
+	log.Printf("Fetching price from: %s", url)
+
+	var rawResult map[string]map[string]any
+	if err := cg.client.GetJSON(ctx, url, &rawResult); err != nil {
+		log.Println("error during getting coin: ", err)
+		return err
+	}
+
+	for _, id := range ids {
+		coinData, exists := rawResult[id]
+		if !exists {
+			log.Printf("Warning: no price data found for ID: %s", id)
+			continue
+		}
+
+		quote := quotes[id]
+		if quote.Prices == nil {
+			quote.Prices = make(map[string]float64)
+		}
+		for currency, priceValue := range coinData {
+			price, err := parsePrice(priceValue)
+			if err != nil {
+				log.Printf("Warning: could not parse price for %s/%s: %v", id, currency, err)
+				continue
+			}
+			quote.Prices[currency] = price
+		}
+
+		if len(quote.Prices) == 0 {
+			log.Printf("Warning: no valid price data found for %s", id)
+			continue
+		}
+
+		quotes[id] = quote
+	}
+
+	return nil
+}
+
+type coinGeckoMarketChart struct {
+	Prices       [][2]float64 `json:"prices"`
+	MarketCaps   [][2]float64 `json:"market_caps"`
+	TotalVolumes [][2]float64 `json:"total_volumes"`
+}
+
+// GetMarketChart uses CoinGecko's automatic granularity: hourly points for up to
+// 90 days, daily points beyond that.
+func (cg *CoinGecko) GetMarketChart(ctx context.Context, id string, days int) ([]PricePoint, error) {
+	url := fmt.Sprintf("%s/coins/%s/market_chart?vs_currency=usd&days=%d", cg.baseURL, id, days)
+
+	log.Printf("Fetching market chart from: %s", url)
+
+	var chart coinGeckoMarketChart
+	if err := cg.client.GetJSON(ctx, url, &chart); err != nil {
+		log.Println("error during getting market chart: ", err)
+		return nil, err
+	}
+
+	points := make([]PricePoint, 0, len(chart.Prices))
+	for i, price := range chart.Prices {
+		point := PricePoint{
+			Timestamp: time.UnixMilli(int64(price[0])).UTC(),
+			Price:     price[1],
+		}
+		if i < len(chart.MarketCaps) {
+			point.MarketCap = chart.MarketCaps[i][1]
+		}
+		if i < len(chart.TotalVolumes) {
+			point.Volume24h = chart.TotalVolumes[i][1]
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
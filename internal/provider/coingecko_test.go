@@ -0,0 +1,228 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTestServer serves routes by path and fails the test on any other path.
+func newTestServer(t *testing.T, routes map[string]http.HandlerFunc) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler, exists := routes[r.URL.Path]
+		if !exists {
+			t.Errorf("unexpected request to %s", r.URL)
+			http.NotFound(w, r)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func respond(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}
+}
+
+func newTestCoinGecko(t *testing.T, routes map[string]http.HandlerFunc) *CoinGecko {
+	t.Helper()
+
+	cg := NewCoinGecko(newTestServer(t, routes).URL)
+	cg.client = newTestClient(0)
+	return cg
+}
+
+const coinGeckoCoinList = `[
+	{"id": "bitcoin", "symbol": "btc", "name": "Bitcoin"},
+	{"id": "batcat", "symbol": "btc", "name": "BatCat"},
+	{"id": "uniswap", "symbol": "uni", "name": "Uniswap"},
+	{"id": "unicorn-token", "symbol": "uni", "name": "Unicorn"},
+	{"id": "pepe", "symbol": "pepe", "name": "Pepe"}
+]`
+
+func TestCoinGeckoResolveSymbol(t *testing.T) {
+	cg := newTestCoinGecko(t, map[string]http.HandlerFunc{
+		"/coins/list": respond(coinGeckoCoinList),
+	})
+
+	tests := []struct {
+		name    string
+		symbol  string
+		wantID  string
+		wantErr error
+	}{
+		{"popular symbol uses the predefined id", "BTC", "bitcoin", nil},
+		{"unique symbol", "pepe", "pepe", nil},
+		{"ambiguous symbol takes the first match", "uni", "uniswap", nil},
+		{"unknown symbol", "nope", "", ErrCoinNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coin, err := cg.ResolveSymbol(context.Background(), tt.symbol)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if coin.ID != tt.wantID {
+				t.Errorf("id = %q, want %q", coin.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestCoinGeckoResolveSymbols(t *testing.T) {
+	cg := newTestCoinGecko(t, map[string]http.HandlerFunc{
+		"/coins/list": respond(coinGeckoCoinList),
+	})
+
+	coins, err := cg.ResolveSymbols(context.Background(), []string{"BTC", "pepe", "nope"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(coins) != 2 || coins["btc"].ID != "bitcoin" || coins["pepe"].Name != "Pepe" {
+		t.Errorf("coins = %v, want btc and pepe only", coins)
+	}
+}
+
+func TestCoinGeckoGetQuotes(t *testing.T) {
+	markets := func(t *testing.T) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if got := r.URL.Query().Get("vs_currency"); got != "usd" {
+				t.Errorf("vs_currency = %q, want usd", got)
+			}
+			if got := r.URL.Query().Get("ids"); got != "bitcoin,pepe" {
+				t.Errorf("ids = %q, want bitcoin,pepe", got)
+			}
+			respond(`[{"id": "bitcoin", "current_price": 60000, "market_cap": 1.2e12, "total_volume": 3e10,
+				"high_24h": 61000, "low_24h": 59000, "price_change_percentage_24h": -1.5}]`)(w, r)
+		}
+	}
+	prices := func(t *testing.T, currencies string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if got := r.URL.Query().Get("vs_currencies"); got != currencies {
+				t.Errorf("vs_currencies = %q, want %q", got, currencies)
+			}
+			respond(`{"bitcoin": {"eur": 55000, "btc": "1"}, "pepe": {"eur": "0.00001", "btc": "n/a"}}`)(w, r)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		currencies []string
+		routes     func(t *testing.T) map[string]http.HandlerFunc
+		want       map[string]map[string]float64
+	}{
+		{
+			name:       "usd from markets",
+			currencies: []string{"usd"},
+			routes: func(t *testing.T) map[string]http.HandlerFunc {
+				return map[string]http.HandlerFunc{"/coins/markets": markets(t)}
+			},
+			want: map[string]map[string]float64{"bitcoin": {"usd": 60000}},
+		},
+		{
+			name:       "other currencies from simple price",
+			currencies: []string{"eur", "btc"},
+			routes: func(t *testing.T) map[string]http.HandlerFunc {
+				return map[string]http.HandlerFunc{"/simple/price": prices(t, "eur,btc")}
+			},
+			want: map[string]map[string]float64{
+				"bitcoin": {"eur": 55000, "btc": 1},
+				"pepe":    {"eur": 0.00001},
+			},
+		},
+		{
+			name:       "usd and other currencies merged",
+			currencies: []string{"usd", "eur", "btc"},
+			routes: func(t *testing.T) map[string]http.HandlerFunc {
+				return map[string]http.HandlerFunc{"/coins/markets": markets(t), "/simple/price": prices(t, "eur,btc")}
+			},
+			want: map[string]map[string]float64{
+				"bitcoin": {"usd": 60000, "eur": 55000, "btc": 1},
+				"pepe":    {"eur": 0.00001},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cg := newTestCoinGecko(t, tt.routes(t))
+
+			quotes, err := cg.GetQuotes(context.Background(), []string{"bitcoin", "pepe"}, tt.currencies)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(quotes) != len(tt.want) {
+				t.Fatalf("got quotes for %d coins, want %d: %v", len(quotes), len(tt.want), quotes)
+			}
+			for id, want := range tt.want {
+				got := quotes[id].Prices
+				if len(got) != len(want) {
+					t.Errorf("%s prices = %v, want %v", id, got, want)
+					continue
+				}
+				for currency, price := range want {
+					if got[currency] != price {
+						t.Errorf("%s %s = %v, want %v", id, currency, got[currency], price)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCoinGeckoGetQuotesMarketData(t *testing.T) {
+	cg := newTestCoinGecko(t, map[string]http.HandlerFunc{
+		"/coins/markets": respond(`[{"id": "bitcoin", "current_price": 60000, "market_cap": 1.2e12,
+			"total_volume": 3e10, "high_24h": 61000, "low_24h": 59000, "price_change_percentage_24h": -1.5}]`),
+	})
+
+	quotes, err := cg.GetQuotes(context.Background(), []string{"bitcoin"}, []string{"usd"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := MarketData{MarketCap: 1.2e12, Volume24h: 3e10, High24h: 61000, Low24h: 59000, PriceChangePercent24h: -1.5}
+	if got := quotes["bitcoin"].Market; got != want {
+		t.Errorf("market = %+v, want %+v", got, want)
+	}
+}
+
+func TestCoinGeckoGetMarketChart(t *testing.T) {
+	cg := newTestCoinGecko(t, map[string]http.HandlerFunc{
+		"/coins/bitcoin/market_chart": func(w http.ResponseWriter, r *http.Request) {
+			if got := r.URL.Query().Get("days"); got != "2" {
+				t.Errorf("days = %q, want 2", got)
+			}
+			respond(`{"prices": [[1700000000000, 100], [1700003600000, 101]],
+				"market_caps": [[1700000000000, 5000]], "total_volumes": [[1700000000000, 70], [1700003600000, 71]]}`)(w, r)
+		},
+	})
+
+	points, err := cg.GetMarketChart(context.Background(), "bitcoin", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []PricePoint{
+		{Timestamp: time.UnixMilli(1700000000000).UTC(), Price: 100, MarketCap: 5000, Volume24h: 70},
+		{Timestamp: time.UnixMilli(1700003600000).UTC(), Price: 101, Volume24h: 71},
+	}
+	if len(points) != len(want) {
+		t.Fatalf("got %d points, want %d", len(points), len(want))
+	}
+	for i := range want {
+		if points[i] != want[i] {
+			t.Errorf("point %d = %+v, want %+v", i, points[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package provider
+
+import (
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+// WriteHTTPError maps typed upstream errors to an HTTP response. It returns false
+// when err is not an upstream error and the caller has to respond itself.
+func WriteHTTPError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, ErrRateLimited):
+		if retryAfter := RetryAfter(err); retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		}
+		http.Error(w, `Price provider rate limit exceeded`, http.StatusTooManyRequests)
+	case errors.Is(err, ErrNotFound):
+		http.Error(w, `Not found on price provider`, http.StatusNotFound)
+	case errors.Is(err, ErrUpstreamUnavailable):
+		http.Error(w, `Price provider unavailable`, http.StatusServiceUnavailable)
+	default:
+		return false
+	}
+
+	log.Println("price provider error: ", err)
+	return true
+}
@@ -0,0 +1,240 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	krakenDefaultURL        = "https://api.kraken.com"
+	krakenQuoteAsset        = "USD"
+	krakenChunkSize         = 50
+	krakenRequestsPerMinute = 60
+)
+
+// Kraken uses its own asset codes for a few well known coins.
+var krakenSymbolMap = map[string]string{
+	"xbt": "btc",
+	"xdg": "doge",
+}
+
+type krakenAssetPairs struct {
+	Error  []string `json:"error"`
+	Result map[string]struct {
+		Wsname string `json:"wsname"`
+	} `json:"result"`
+}
+
+type krakenTicker struct {
+	Error  []string `json:"error"`
+	Result map[string]struct {
+		LastTrade []string `json:"c"`
+		Volume    []string `json:"v"`
+		VWAP      []string `json:"p"`
+		High      []string `json:"h"`
+		Low       []string `json:"l"`
+		Open      string   `json:"o"`
+	} `json:"result"`
+}
+
+// Kraken quotes every coin against USD. Coin IDs are Kraken pair names such as
+// XXBTZUSD.
+type Kraken struct {
+	baseURL string
+	client  *Client
+}
+
+func NewKraken(baseURL string) *Kraken {
+	if baseURL == "" {
+		baseURL = krakenDefaultURL
+	}
+
+	return &Kraken{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  NewClient("kraken", krakenRequestsPerMinute),
+	}
+}
+
+func (k *Kraken) Name() string {
+	return "kraken"
+}
+
+func (k *Kraken) ListCoins(ctx context.Context) ([]CoinInfo, error) {
+	var pairs krakenAssetPairs
+	if err := k.client.GetJSON(ctx, k.baseURL+"/0/public/AssetPairs", &pairs); err != nil {
+		log.Println("error during getting kraken asset pairs: ", err)
+		return nil, err
+	}
+	if len(pairs.Error) > 0 {
+		return nil, fmt.Errorf("kraken error: %s", strings.Join(pairs.Error, "; "))
+	}
+
+	var coins []CoinInfo
+	for id, pair := range pairs.Result {
+		base, quote, ok := strings.Cut(pair.Wsname, "/")
+		if !ok || quote != krakenQuoteAsset {
+			continue
+		}
+
+		symbol := strings.ToLower(base)
+		if mapped, exists := krakenSymbolMap[symbol]; exists {
+			symbol = mapped
+		}
+
+		coins = append(coins, CoinInfo{
+			ID:     id,
+			Symbol: symbol,
+			Name:   strings.ToUpper(symbol),
+		})
+	}
+	return coins, nil
+}
+
+func (k *Kraken) MatchSymbol(symbol string, coins []CoinInfo) (CoinInfo, error) {
+	symbol = strings.ToLower(symbol)
+	for _, coin := range coins {
+		if coin.Symbol == symbol {
+			return coin, nil
+		}
+	}
+
+	return CoinInfo{}, fmt.Errorf("%w: symbol %s", ErrCoinNotFound, symbol)
+}
+
+func (k *Kraken) ResolveSymbol(ctx context.Context, symbol string) (CoinInfo, error) {
+	coins, err := k.ListCoins(ctx)
+	if err != nil {
+		return CoinInfo{}, fmt.Errorf("failed to get coin list: %w", err)
+	}
+	return k.MatchSymbol(symbol, coins)
+}
+
+func (k *Kraken) ResolveSymbols(ctx context.Context, symbols []string) (map[string]CoinInfo, error) {
+	coins, err := k.ListCoins(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get coin list: %w", err)
+	}
+	return MatchSymbols(k, coins, symbols), nil
+}
+
+// GetQuotes only supports "usd"; other requested currencies are ignored.
+func (k *Kraken) GetQuotes(ctx context.Context, ids []string, currencies []string) (map[string]Quote, error) {
+	quotes := make(map[string]Quote)
+	for _, batch := range chunk(ids, krakenChunkSize) {
+		if err := k.getQuotes(ctx, batch, quotes); err != nil {
+			return nil, err
+		}
+	}
+	return quotes, nil
+}
+
+func (k *Kraken) getQuotes(ctx context.Context, ids []string, quotes map[string]Quote) error {
+	var ticker krakenTicker
+	endpoint := k.baseURL + "/0/public/Ticker?pair=" + strings.Join(ids, ",")
+	if err := k.client.GetJSON(ctx, endpoint, &ticker); err != nil {
+		log.Println("error during getting kraken prices: ", err)
+		return err
+	}
+	if len(ticker.Error) > 0 {
+		return fmt.Errorf("kraken error: %s", strings.Join(ticker.Error, "; "))
+	}
+
+	for id, data := range ticker.Result {
+		if len(data.LastTrade) == 0 {
+			continue
+		}
+		price, err := strconv.ParseFloat(data.LastTrade[0], 64)
+		if err != nil {
+			log.Printf("Warning: could not parse price for %s: %v", id, err)
+			continue
+		}
+		quotes[id] = Quote{
+			Prices: map[string]float64{"usd": price},
+			Market: krakenMarketData(price, data.Volume, data.VWAP, data.High, data.Low, data.Open),
+		}
+	}
+	return nil
+}
+
+// krakenMarketData reads the rolling 24h values (index 1) of the ticker arrays.
+// Kraken has no market cap, reports volume in the base asset and only exposes
+// today's opening price, so the change is measured from midnight UTC.
+func krakenMarketData(price float64, volume, vwap, high, low []string, open string) MarketData {
+	last24h := func(values []string) float64 {
+		if len(values) < 2 {
+			return 0
+		}
+		value, _ := strconv.ParseFloat(values[1], 64)
+		return value
+	}
+
+	market := MarketData{
+		Volume24h: last24h(volume) * last24h(vwap),
+		High24h:   last24h(high),
+		Low24h:    last24h(low),
+	}
+	if openPrice, err := strconv.ParseFloat(open, 64); err == nil && openPrice > 0 {
+		market.PriceChangePercent24h = (price - openPrice) / openPrice * 100
+	}
+	return market
+}
+
+type krakenOHLC struct {
+	Error  []string                   `json:"error"`
+	Result map[string]json.RawMessage `json:"result"`
+}
+
+// GetMarketChart reads OHLC candles. Kraken only serves the latest 720 candles
+// per interval, so hourly candles cover 30 days and daily candles the rest.
+func (k *Kraken) GetMarketChart(ctx context.Context, id string, days int) ([]PricePoint, error) {
+	interval, step := 60, time.Hour
+	if days > 30 {
+		interval, step = 1440, 24*time.Hour
+	}
+
+	since := time.Now().Add(-time.Duration(days) * 24 * time.Hour)
+	endpoint := fmt.Sprintf("%s/0/public/OHLC?pair=%s&interval=%d&since=%d", k.baseURL, id, interval, since.Unix())
+
+	var ohlc krakenOHLC
+	if err := k.client.GetJSON(ctx, endpoint, &ohlc); err != nil {
+		log.Println("error during getting kraken OHLC: ", err)
+		return nil, err
+	}
+	if len(ohlc.Error) > 0 {
+		return nil, fmt.Errorf("kraken error: %s", strings.Join(ohlc.Error, "; "))
+	}
+
+	var candles [][]any
+	if raw, exists := ohlc.Result[id]; exists {
+		if err := json.Unmarshal(raw, &candles); err != nil {
+			return nil, fmt.Errorf("%w: invalid OHLC response: %v", ErrUpstreamUnavailable, err)
+		}
+	}
+
+	points := make([]PricePoint, 0, len(candles))
+	for _, candle := range candles {
+		if len(candle) < 5 {
+			continue
+		}
+		openTime, err := parsePrice(candle[0])
+		if err != nil {
+			continue
+		}
+		price, err := parsePrice(candle[4])
+		if err != nil {
+			log.Printf("Warning: could not parse close price for %s: %v", id, err)
+			continue
+		}
+
+		points = append(points, PricePoint{
+			Timestamp: time.Unix(int64(openTime), 0).Add(step).UTC(),
+			Price:     price,
+		})
+	}
+	return points, nil
+}
@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func newTestKraken(t *testing.T, routes map[string]http.HandlerFunc) *Kraken {
+	t.Helper()
+
+	k := NewKraken(newTestServer(t, routes).URL)
+	k.client = newTestClient(0)
+	return k
+}
+
+const krakenAssetPairsBody = `{"error": [], "result": {
+	"XXBTZUSD": {"wsname": "XBT/USD"},
+	"XXBTZEUR": {"wsname": "XBT/EUR"},
+	"XDGUSD": {"wsname": "XDG/USD"},
+	"XETHZUSD": {"wsname": "ETH/USD"},
+	"SOLUSD": {"wsname": "SOL/USD"},
+	"LEGACY": {}
+}}`
+
+func TestKrakenResolveSymbol(t *testing.T) {
+	k := newTestKraken(t, map[string]http.HandlerFunc{
+		"/0/public/AssetPairs": respond(krakenAssetPairsBody),
+	})
+
+	tests := []struct {
+		name     string
+		symbol   string
+		wantID   string
+		wantName string
+		wantErr  error
+	}{
+		{"xbt is bitcoin", "btc", "XXBTZUSD", "BTC", nil},
+		{"xdg is dogecoin", "DOGE", "XDGUSD", "DOGE", nil},
+		{"prefixed pair name", "eth", "XETHZUSD", "ETH", nil},
+		{"plain pair name", "sol", "SOLUSD", "SOL", nil},
+		{"kraken asset code is not a symbol", "xbt", "", "", ErrCoinNotFound},
+		{"unknown symbol", "nope", "", "", ErrCoinNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coin, err := k.ResolveSymbol(context.Background(), tt.symbol)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if coin.ID != tt.wantID || coin.Name != tt.wantName {
+				t.Errorf("coin = %+v, want id %q and name %q", coin, tt.wantID, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestKrakenAPIError(t *testing.T) {
+	k := newTestKraken(t, map[string]http.HandlerFunc{
+		"/0/public/AssetPairs": respond(`{"error": ["EGeneral:Temporary lockout"]}`),
+		"/0/public/Ticker":     respond(`{"error": ["EQuery:Unknown asset pair"]}`),
+	})
+
+	if _, err := k.ListCoins(context.Background()); err == nil {
+		t.Error("ListCoins: expected the kraken error")
+	}
+	if _, err := k.GetQuotes(context.Background(), []string{"NOPE"}, []string{"usd"}); err == nil {
+		t.Error("GetQuotes: expected the kraken error")
+	}
+}
+
+func TestKrakenGetQuotes(t *testing.T) {
+	k := newTestKraken(t, map[string]http.HandlerFunc{
+		"/0/public/Ticker": func(w http.ResponseWriter, r *http.Request) {
+			if got := r.URL.Query().Get("pair"); got != "XXBTZUSD,XETHZUSD" {
+				t.Errorf("pair = %q, want XXBTZUSD,XETHZUSD", got)
+			}
+			respond(`{"error": [], "result": {
+				"XXBTZUSD": {"c": ["60000.0", "0.1"], "v": ["10", "200"], "p": ["59000", "59500"],
+				             "h": ["60500", "61000"], "l": ["58000", "57000"], "o": "50000.0"},
+				"XETHZUSD": {"c": []}
+			}}`)(w, r)
+		},
+	})
+
+	quotes, err := k.GetQuotes(context.Background(), []string{"XXBTZUSD", "XETHZUSD"}, []string{"usd"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(quotes) != 1 {
+		t.Fatalf("quotes = %v, want XXBTZUSD only", quotes)
+	}
+	quote := quotes["XXBTZUSD"]
+	if quote.Prices["usd"] != 60000 {
+		t.Errorf("usd = %v, want 60000", quote.Prices["usd"])
+	}
+
+	// The rolling 24h values are the second array entries; volume is in BTC and
+	// converted with the 24h VWAP.
+	want := MarketData{Volume24h: 200 * 59500, High24h: 61000, Low24h: 57000, PriceChangePercent24h: 20}
+	got := quote.Market
+	if got.Volume24h != want.Volume24h || got.High24h != want.High24h || got.Low24h != want.Low24h ||
+		math.Abs(got.PriceChangePercent24h-want.PriceChangePercent24h) > 1e-9 {
+		t.Errorf("market = %+v, want %+v", got, want)
+	}
+}
+
+func TestKrakenGetMarketChart(t *testing.T) {
+	tests := []struct {
+		name         string
+		days         int
+		wantInterval string
+		wantStep     time.Duration
+	}{
+		{"hourly candles", 7, "60", time.Hour},
+		{"daily candles", 90, "1440", 24 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := newTestKraken(t, map[string]http.HandlerFunc{
+				"/0/public/OHLC": func(w http.ResponseWriter, r *http.Request) {
+					query := r.URL.Query()
+					if query.Get("pair") != "XXBTZUSD" || query.Get("interval") != tt.wantInterval {
+						t.Errorf("query = %v, want XXBTZUSD at %s", query, tt.wantInterval)
+					}
+					respond(`{"error": [], "result": {
+						"XXBTZUSD": [[1700000000, "100", "110", "90", "105", "104", "5", 10]],
+						"last": 1700000000
+					}}`)(w, r)
+				},
+			})
+
+			points, err := k.GetMarketChart(context.Background(), "XXBTZUSD", tt.days)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			want := PricePoint{Timestamp: time.Unix(1700000000, 0).Add(tt.wantStep).UTC(), Price: 105}
+			if len(points) != 1 || points[0] != want {
+				t.Errorf("points = %+v, want the close at the end of the candle %+v", points, want)
+			}
+		})
+	}
+}
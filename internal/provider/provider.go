@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnknownProvider = errors.New("unknown price provider")
+	ErrCoinNotFound    = errors.New("coin not found")
+)
+
+type CoinInfo struct {
+	ID     string `json:"id"`
+	Symbol string `json:"symbol"`
+	Name   string `json:"name"`
+}
+
+// MarketData holds 24h market figures in USD. Fields a provider does not report
+// are left at zero.
+type MarketData struct {
+	MarketCap             float64 `json:"market_cap"`
+	Volume24h             float64 `json:"volume_24h"`
+	High24h               float64 `json:"high_24h"`
+	Low24h                float64 `json:"low_24h"`
+	PriceChangePercent24h float64 `json:"price_change_percent_24h"`
+}
+
+// Quote holds the prices of a single coin keyed by lower-case quote currency ("usd").
+type Quote struct {
+	Prices map[string]float64 `json:"prices"`
+	Market MarketData         `json:"market"`
+}
+
+// PricePoint is a single historical USD observation. Fields a provider does not
+// report are left at zero.
+type PricePoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Price     float64   `json:"price"`
+	MarketCap float64   `json:"market_cap"`
+	Volume24h float64   `json:"volume_24h"`
+}
+
+type PriceProvider interface {
+	Name() string
+	ListCoins(ctx context.Context) ([]CoinInfo, error)
+	// MatchSymbol picks the coin for symbol out of an already downloaded coin list.
+	MatchSymbol(symbol string, coins []CoinInfo) (CoinInfo, error)
+	ResolveSymbol(ctx context.Context, symbol string) (CoinInfo, error)
+	// ResolveSymbols resolves many symbols with a single coin list download.
+	// Symbols that cannot be resolved are left out of the result.
+	ResolveSymbols(ctx context.Context, symbols []string) (map[string]CoinInfo, error)
+	// GetQuotes fetches all ids in as few upstream calls as the provider allows.
+	// Currencies the provider cannot quote are left out of Quote.Prices.
+	GetQuotes(ctx context.Context, ids []string, currencies []string) (map[string]Quote, error)
+	// GetMarketChart returns USD price points for the last days, oldest first.
+	GetMarketChart(ctx context.Context, id string, days int) ([]PricePoint, error)
+}
+
+func NewPriceProvider() (PriceProvider, error) {
+	return New(os.Getenv("PRICE_PROVIDER"), os.Getenv("PRICE_PROVIDER_URL"))
+}
+
+func New(name string, baseURL string) (PriceProvider, error) {
+	switch strings.ToLower(name) {
+	case "", "coingecko":
+		return NewCoinGecko(baseURL), nil
+	case "binance":
+		return NewBinance(baseURL), nil
+	case "kraken":
+		return NewKraken(baseURL), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, name)
+	}
+}
+
+func chunk(ids []string, size int) [][]string {
+	var chunks [][]string
+	for size < len(ids) {
+		ids, chunks = ids[size:], append(chunks, ids[:size])
+	}
+	if len(ids) > 0 {
+		chunks = append(chunks, ids)
+	}
+	return chunks
+}
+
+func MatchSymbols(p PriceProvider, coins []CoinInfo, symbols []string) map[string]CoinInfo {
+	result := make(map[string]CoinInfo)
+	for _, symbol := range symbols {
+		symbol = strings.ToLower(symbol)
+
+		coin, err := p.MatchSymbol(symbol, coins)
+		if err != nil {
+			log.Printf("Warning: %v", err)
+			continue
+		}
+		result[symbol] = coin
+	}
+	return result
+}
@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestProviderTypedErrors(t *testing.T) {
+	t.Setenv("PROVIDER_MAX_RETRIES", "0")
+
+	statuses := []struct {
+		status  int
+		wantErr error
+	}{
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusServiceUnavailable, ErrUpstreamUnavailable},
+	}
+
+	for _, name := range []string{"coingecko", "binance", "kraken"} {
+		for _, tt := range statuses {
+			t.Run(name+"/"+http.StatusText(tt.status), func(t *testing.T) {
+				server, _ := countingServer(t, func(w http.ResponseWriter, r *http.Request, call int32) {
+					w.WriteHeader(tt.status)
+				})
+
+				p, err := New(name, server.URL)
+				if err != nil {
+					t.Fatalf("New(%q): %v", name, err)
+				}
+
+				if _, err := p.ListCoins(context.Background()); !errors.Is(err, tt.wantErr) {
+					t.Errorf("ListCoins error = %v, want %v", err, tt.wantErr)
+				}
+				if _, err := p.GetMarketChart(context.Background(), "id", 1); !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetMarketChart error = %v, want %v", err, tt.wantErr)
+				}
+			})
+		}
+	}
+}
+
+func TestNewUnknownProvider(t *testing.T) {
+	if _, err := New("bitstamp", ""); !errors.Is(err, ErrUnknownProvider) {
+		t.Errorf("error = %v, want %v", err, ErrUnknownProvider)
+	}
+}
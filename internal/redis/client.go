@@ -11,11 +11,25 @@ import (
     "github.com/redis/go-redis/v9"
 )
 
+// MarketData holds the 24h market figures recorded with a history entry, in USD.
+type MarketData struct {
+    MarketCap             float64 `json:"market_cap,omitempty"`
+    Volume24h             float64 `json:"volume_24h,omitempty"`
+    High24h               float64 `json:"high_24h,omitempty"`
+    Low24h                float64 `json:"low_24h,omitempty"`
+    PriceChangePercent24h float64 `json:"price_change_percent_24h,omitempty"`
+}
+
 type PriceHistoryEntry struct {
-    Price     float64   `json:"price"`
-    Timestamp time.Time `json:"timestamp"`
+    Price     float64            `json:"price"`
+    Prices    map[string]float64 `json:"prices,omitempty"`
+    MarketData
+    Timestamp time.Time          `json:"timestamp"`
 }
 
+// HistoryLimit is the number of entries kept per symbol.
+const HistoryLimit = 100
+
 type RedisClient struct {
     client *redis.Client
     ctx    context.Context
@@ -55,13 +69,12 @@ func NewRedisClient() (*RedisClient, error) {
     }, nil
 }
 
-func (r *RedisClient) AddPriceHistory(symbol string, price float64) error {
+func (r *RedisClient) AddPriceHistory(symbol string, entry PriceHistoryEntry) error {
 	key := fmt.Sprintf("price_history:%s", symbol)
+	price := entry.Prices["usd"]
 
-	entry := PriceHistoryEntry{
-        Price:     price,
-        Timestamp: time.Now().UTC(),
-    }
+	entry.Price = price
+	entry.Timestamp = time.Now().UTC()
 
 	data, err := json.Marshal(entry)
     if err != nil {
@@ -73,7 +86,7 @@ func (r *RedisClient) AddPriceHistory(symbol string, price float64) error {
         return fmt.Errorf("failed to add price to history: %w", err)
     }
 
-	err = r.client.LTrim(r.ctx, key, 0, 99).Err()
+	err = r.client.LTrim(r.ctx, key, 0, HistoryLimit-1).Err()
     if err != nil {
         return fmt.Errorf("failed to trim price history: %w", err)
     }
@@ -82,6 +95,33 @@ func (r *RedisClient) AddPriceHistory(symbol string, price float64) error {
     return nil
 }
 
+func (r *RedisClient) AddPriceHistoryBatch(entries map[string]PriceHistoryEntry) error {
+    timestamp := time.Now().UTC()
+
+    pipe := r.client.Pipeline()
+    for symbol, entry := range entries {
+        key := fmt.Sprintf("price_history:%s", symbol)
+
+        entry.Price = entry.Prices["usd"]
+        entry.Timestamp = timestamp
+
+        data, err := json.Marshal(entry)
+        if err != nil {
+            return fmt.Errorf("failed to marshal price entry: %w", err)
+        }
+
+        pipe.LPush(r.ctx, key, string(data))
+        pipe.LTrim(r.ctx, key, 0, HistoryLimit-1)
+    }
+
+    if _, err := pipe.Exec(r.ctx); err != nil {
+        return fmt.Errorf("failed to add price history batch: %w", err)
+    }
+
+    log.Printf("Added price history for %d symbols", len(entries))
+    return nil
+}
+
 func (r *RedisClient) GetLatestPrice(symbol string) (*PriceHistoryEntry, error) {
     key := fmt.Sprintf("price_history:%s", symbol)
     
@@ -125,8 +165,8 @@ func (r *RedisClient) GetHistoryCount(symbol string) (int, error) {
 }
 
 func (r *RedisClient) GetPriceHistory(symbol string, limit int) ([]PriceHistoryEntry, error) {
-    if limit <= 0 || limit > 100 {
-        limit = 100
+    if limit <= 0 || limit > HistoryLimit {
+        limit = HistoryLimit
     }
     
     key := fmt.Sprintf("price_history:%s", symbol)
@@ -149,6 +189,55 @@ func (r *RedisClient) GetPriceHistory(symbol string, limit int) ([]PriceHistoryE
     return history, nil
 }
 
+// Increment adds n to the counter at key and returns the new value.
+func (r *RedisClient) Increment(key string, n int64) (int64, error) {
+    value, err := r.client.IncrBy(r.ctx, key, n).Result()
+    if err != nil {
+        return 0, fmt.Errorf("failed to increment %s: %w", key, err)
+    }
+    return value, nil
+}
+
+// Publish sends message to every subscriber of channel, on any server.
+func (r *RedisClient) Publish(channel string, message []byte) error {
+    if err := r.client.Publish(r.ctx, channel, message).Err(); err != nil {
+        return fmt.Errorf("failed to publish to %s: %w", channel, err)
+    }
+    return nil
+}
+
+// Subscribe delivers the messages published on channel until ctx is done.
+// The subscription reconnects by itself when the connection to Redis drops;
+// messages published in the meantime are lost.
+func (r *RedisClient) Subscribe(ctx context.Context, channel string) <-chan string {
+    pubsub := r.client.Subscribe(ctx, channel)
+    messages := make(chan string)
+
+    go func() {
+        defer close(messages)
+        defer pubsub.Close()
+
+        incoming := pubsub.Channel()
+        for {
+            select {
+            case msg, ok := <-incoming:
+                if !ok {
+                    return
+                }
+                select {
+                case messages <- msg.Payload:
+                case <-ctx.Done():
+                    return
+                }
+            case <-ctx.Done():
+                return
+            }
+        }
+    }()
+
+    return messages
+}
+
 func (r *RedisClient) Close() error {
     if r.client != nil {
         return r.client.Close()
@@ -0,0 +1,352 @@
+package stream
+
+import (
+	"RESTCryptoServer/internal/crypto"
+	"RESTCryptoServer/internal/redis"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PriceChannel is the Redis channel price writes are published on. Every
+// server subscribes to it, so clients receive the writes of all replicas.
+const PriceChannel = "price_updates"
+
+// sequenceKey numbers the published ticks, so a tick has the same id on every
+// replica and streams can resume on another one.
+const sequenceKey = "price_updates:seq"
+
+const (
+	maxSymbolLength   = 20
+	defaultMaxConns   = 1000
+	defaultReplaySize = 1000
+)
+
+var (
+	ErrTooManyConnections   = errors.New("too many streaming connections")
+	ErrTooManySubscriptions = errors.New("too many subscribed symbols")
+	ErrInvalidSymbol        = errors.New("symbols must be 1 to 20 letters, digits, '-' or '.'")
+)
+
+// Tick is one published price write with its id.
+type Tick struct {
+	ID int64 `json:"id"`
+	crypto.PriceUpdate
+}
+
+// Hub publishes the price writes of this server to Redis and fans the writes
+// of every server out to the local subscriptions. It keeps the latest ticks
+// so streams can replay what a reconnecting client missed.
+type Hub struct {
+	cache          *redis.RedisClient
+	maxConnections int
+	replaySize     int
+
+	mu            sync.RWMutex
+	subscriptions map[*Subscription]struct{}
+	replay        []Tick
+
+	cancel context.CancelFunc
+}
+
+func envPositive(name string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(name)); err == nil && value > 0 {
+		return value
+	}
+	return fallback
+}
+
+func NewHub(cache *redis.RedisClient) *Hub {
+	return &Hub{
+		cache:          cache,
+		maxConnections: envPositive("STREAM_MAX_CONNECTIONS", defaultMaxConns),
+		replaySize:     envPositive("STREAM_REPLAY_SIZE", defaultReplaySize),
+		subscriptions:  make(map[*Subscription]struct{}),
+	}
+}
+
+// NormalizeSymbols lowercases and deduplicates symbols and rejects malformed
+// ones. Symbols do not have to be tracked yet.
+func NormalizeSymbols(symbols []string) ([]string, error) {
+	normalized := make([]string, 0, len(symbols))
+	seen := make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		symbol = strings.ToLower(strings.TrimSpace(symbol))
+		if symbol == "" || len(symbol) > maxSymbolLength {
+			return nil, ErrInvalidSymbol
+		}
+		for _, r := range symbol {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '.' {
+				return nil, ErrInvalidSymbol
+			}
+		}
+		if !seen[symbol] {
+			seen[symbol] = true
+			normalized = append(normalized, symbol)
+		}
+	}
+	return normalized, nil
+}
+
+// Publish numbers a batch of written prices and sends it to every server. It
+// is a crypto.PriceListener.
+func (h *Hub) Publish(updates []crypto.PriceUpdate) {
+	if len(updates) == 0 {
+		return
+	}
+
+	last, err := h.cache.Increment(sequenceKey, int64(len(updates)))
+	if err != nil {
+		log.Printf("Failed to number price updates: %v", err)
+		return
+	}
+
+	ticks := make([]Tick, len(updates))
+	for i, update := range updates {
+		ticks[i] = Tick{ID: last - int64(len(updates)-1-i), PriceUpdate: update}
+	}
+
+	message, err := json.Marshal(ticks)
+	if err != nil {
+		log.Printf("Failed to encode price updates: %v", err)
+		return
+	}
+	if err := h.cache.Publish(PriceChannel, message); err != nil {
+		log.Printf("Failed to publish price updates: %v", err)
+	}
+}
+
+func (h *Hub) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+
+	messages := h.cache.Subscribe(ctx, PriceChannel)
+	log.Printf("Price stream hub started, at most %d connections", h.maxConnections)
+
+	go func() {
+		for message := range messages {
+			var ticks []Tick
+			if err := json.Unmarshal([]byte(message), &ticks); err != nil {
+				log.Printf("Skipping malformed price update message: %v", err)
+				continue
+			}
+			h.record(ticks)
+			h.dispatch(ticks)
+		}
+	}()
+}
+
+// Stop ends the Redis subscription and closes every subscription, so open
+// streams end with the server.
+func (h *Hub) Stop() {
+	if h.cancel != nil {
+		h.cancel()
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for subscription := range h.subscriptions {
+		subscription.close()
+		delete(h.subscriptions, subscription)
+	}
+	log.Println("Price stream hub stopped")
+}
+
+// record appends ticks to the replay buffer, dropping the oldest beyond
+// replaySize.
+func (h *Hub) record(ticks []Tick) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.replay = append(h.replay, ticks...)
+	if len(h.replay) > h.replaySize {
+		h.replay = h.replay[len(h.replay)-h.replaySize:]
+	}
+}
+
+// Since returns the buffered ticks of symbols after the tick with id
+// lastID. gap reports that older ticks after lastID already left the buffer.
+func (h *Hub) Since(lastID int64, symbols []string) (ticks []Tick, gap bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if len(h.replay) > 0 && lastID+1 < h.replay[0].ID {
+		gap = true
+	}
+	for _, tick := range h.replay {
+		if tick.ID > lastID && slices.Contains(symbols, tick.Symbol) {
+			ticks = append(ticks, tick)
+		}
+	}
+	return ticks, gap
+}
+
+func (h *Hub) dispatch(ticks []Tick) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for subscription := range h.subscriptions {
+		subscription.offer(ticks)
+	}
+}
+
+// Subscribe opens a subscription without symbols. It fails when the server
+// already serves maxConnections streams.
+func (h *Hub) Subscribe() (*Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.subscriptions) >= h.maxConnections {
+		return nil, ErrTooManyConnections
+	}
+
+	subscription := &Subscription{
+		hub:     h,
+		symbols: make(map[string]bool),
+		pending: make(map[string]Tick),
+		ready:   make(chan struct{}, 1),
+		closed:  make(chan struct{}),
+	}
+	h.subscriptions[subscription] = struct{}{}
+	return subscription, nil
+}
+
+func (h *Hub) unsubscribe(subscription *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.subscriptions, subscription)
+}
+
+// Subscription receives the price writes of its symbols. Writes are conflated
+// per symbol: a reader that falls behind gets the latest price of every symbol
+// it missed instead of blocking the hub and the other subscriptions.
+type Subscription struct {
+	hub *Hub
+
+	mu      sync.Mutex
+	symbols map[string]bool
+	pending map[string]Tick
+	skipped int
+
+	ready     chan struct{}
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (s *Subscription) offer(ticks []Tick) {
+	s.mu.Lock()
+	queued := false
+	for _, tick := range ticks {
+		if !s.symbols[tick.Symbol] {
+			continue
+		}
+		if _, exists := s.pending[tick.Symbol]; exists {
+			s.skipped++
+		}
+		s.pending[tick.Symbol] = tick
+		queued = true
+	}
+	s.mu.Unlock()
+
+	if queued {
+		select {
+		case s.ready <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Ready is signalled when Take has updates.
+func (s *Subscription) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Closed is closed when the hub ends the subscription on shutdown.
+func (s *Subscription) Closed() <-chan struct{} {
+	return s.closed
+}
+
+// Take returns the pending ticks in id order, and how many older ticks were
+// replaced by newer ones since the previous call.
+func (s *Subscription) Take() ([]Tick, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ticks := make([]Tick, 0, len(s.pending))
+	for _, tick := range s.pending {
+		ticks = append(ticks, tick)
+	}
+	slices.SortFunc(ticks, func(a, b Tick) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	skipped := s.skipped
+
+	clear(s.pending)
+	s.skipped = 0
+	return ticks, skipped
+}
+
+// Add subscribes to symbols unless that makes more than limit symbols.
+func (s *Subscription) Add(symbols []string, limit int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := len(s.symbols)
+	for _, symbol := range symbols {
+		if !s.symbols[symbol] {
+			count++
+		}
+	}
+	if count > limit {
+		return ErrTooManySubscriptions
+	}
+
+	for _, symbol := range symbols {
+		s.symbols[symbol] = true
+	}
+	return nil
+}
+
+// Remove unsubscribes from symbols and drops their pending updates.
+func (s *Subscription) Remove(symbols []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, symbol := range symbols {
+		delete(s.symbols, symbol)
+		delete(s.pending, symbol)
+	}
+}
+
+// Symbols returns the subscribed symbols in alphabetical order.
+func (s *Subscription) Symbols() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	symbols := make([]string, 0, len(s.symbols))
+	for symbol := range s.symbols {
+		symbols = append(symbols, symbol)
+	}
+	slices.Sort(symbols)
+	return symbols
+}
+
+// Close ends the subscription and frees its connection slot.
+func (s *Subscription) Close() {
+	s.hub.unsubscribe(s)
+}
+
+func (s *Subscription) close() {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+	})
+}
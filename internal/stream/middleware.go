@@ -0,0 +1,25 @@
+package stream
+
+import (
+	"net/http"
+	"slices"
+)
+
+// streamPaths are the routes that hold their connection open.
+var streamPaths = []string{"/ws", "/crypto/stream"}
+
+// ExceptStreams applies middleware to every request except those of the
+// streaming routes, e.g. so a concurrency throttle is not exhausted by
+// connections that stay open for hours.
+func ExceptStreams(middleware func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		wrapped := middleware(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if slices.Contains(streamPaths, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			wrapped.ServeHTTP(w, r)
+		})
+	}
+}
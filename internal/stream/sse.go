@@ -0,0 +1,176 @@
+package stream
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	sseHeartbeat = 15 * time.Second
+	sseRetry     = 3 * time.Second
+)
+
+var (
+	ErrMissingSymbols = errors.New("symbols is required")
+	ErrInvalidEventID = errors.New("invalid Last-Event-ID, expected a tick id")
+)
+
+// writeEvent writes one server-sent event. Events without an id leave the
+// last event id of the client unchanged.
+func writeEvent(w io.Writer, id int64, event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if id > 0 {
+		if _, err := fmt.Fprintf(w, "id: %d\n", id); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	return err
+}
+
+// lastEventID reads the id a reconnecting client resumes after, sent by
+// EventSource in the Last-Event-ID header or given as last_event_id.
+func lastEventID(r *http.Request) (int64, bool, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+	}
+	if value == "" {
+		return 0, false, nil
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 0 {
+		return 0, false, ErrInvalidEventID
+	}
+	return id, true, nil
+}
+
+// SSEHandler streams the price writes of the symbols query parameter as
+// server-sent price events. A resuming client first gets the buffered ticks
+// after its last event id, preceded by a resync event when some of them
+// already left the buffer.
+func SSEHandler(hub *Hub) http.HandlerFunc {
+	limit := MaxSubscriptions()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		value := r.URL.Query().Get("symbols")
+		if value == "" {
+			http.Error(w, `Bad Request - `+ErrMissingSymbols.Error(), http.StatusBadRequest)
+			return
+		}
+		symbols, err := NormalizeSymbols(strings.Split(value, ","))
+		if err != nil {
+			http.Error(w, `Bad Request - `+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if len(symbols) > limit {
+			http.Error(w, `Bad Request - `+ErrTooManySubscriptions.Error(), http.StatusBadRequest)
+			return
+		}
+
+		lastID, resume, err := lastEventID(r)
+		if err != nil {
+			http.Error(w, `Bad Request - `+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		subscription, err := hub.Subscribe()
+		if err == ErrTooManyConnections {
+			log.Println(err)
+			http.Error(w, `Service Unavailable - too many streaming connections`, http.StatusServiceUnavailable)
+			return
+		}
+		defer subscription.Close()
+		subscription.Add(symbols, limit)
+
+		// The server WriteTimeout would end the stream after a few seconds, so
+		// every write gets its own deadline instead.
+		rc := http.NewResponseController(w)
+		if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+			log.Println("error during clearing stream write deadline: ", err)
+		}
+		flush := func() error {
+			rc.SetWriteDeadline(time.Now().Add(writeWait))
+			return rc.Flush()
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		fmt.Fprintf(w, "retry: %d\n\n", sseRetry.Milliseconds())
+
+		// sent is the id of the latest replayed tick, so ticks both replayed
+		// and received live are written once.
+		var sent int64
+		if resume {
+			ticks, gap := hub.Since(lastID, symbols)
+			if gap {
+				if err := writeEvent(w, 0, "resync", map[string]int64{"last_event_id": lastID}); err != nil {
+					return
+				}
+			}
+			for _, tick := range ticks {
+				if err := writeEvent(w, tick.ID, "price", tick.PriceUpdate); err != nil {
+					return
+				}
+				sent = tick.ID
+			}
+		}
+		if err := flush(); err != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(sseHeartbeat)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-subscription.Ready():
+				ticks, skipped := subscription.Take()
+				if skipped > 0 {
+					if err := writeEvent(w, 0, "lagged", map[string]int{"skipped": skipped}); err != nil {
+						return
+					}
+				}
+				for _, tick := range ticks {
+					if tick.ID <= sent {
+						continue
+					}
+					if err := writeEvent(w, tick.ID, "price", tick.PriceUpdate); err != nil {
+						return
+					}
+					sent = tick.ID
+				}
+
+			case <-heartbeat.C:
+				// Comments keep proxies from closing an idle stream.
+				if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+					return
+				}
+
+			case <-subscription.Closed():
+				return
+
+			case <-r.Context().Done():
+				return
+			}
+
+			if err := flush(); err != nil {
+				return
+			}
+		}
+	}
+}
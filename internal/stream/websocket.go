@@ -0,0 +1,246 @@
+package stream
+
+import (
+	"RESTCryptoServer/internal/auth"
+	"RESTCryptoServer/internal/crypto"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = 25 * time.Second
+	maxMessageSize = 4096
+	pendingReplies = 16
+
+	defaultMaxSubscriptions = 50
+)
+
+const (
+	ActionSubscribe   = "subscribe"
+	ActionUnsubscribe = "unsubscribe"
+	ActionPing        = "ping"
+)
+
+// ClientMessage is a message sent by a WebSocket client.
+type ClientMessage struct {
+	Action  string   `json:"action"`
+	Symbols []string `json:"symbols"`
+}
+
+// ServerMessage is a message sent to a WebSocket client. Type is subscribed
+// (with the current Symbols), price (with the tick ID and Data), lagged (with
+// the number of Skipped updates), pong or error (with Message).
+type ServerMessage struct {
+	Type    string              `json:"type"`
+	ID      int64               `json:"id,omitempty"`
+	Symbols []string            `json:"symbols,omitempty"`
+	Data    *crypto.PriceUpdate `json:"data,omitempty"`
+	Skipped int                 `json:"skipped,omitempty"`
+	Message string              `json:"message,omitempty"`
+}
+
+// The token, not a cookie, authenticates the connection, so any origin may
+// connect, as with the CORS headers of the REST API.
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 4096,
+	CheckOrigin:     func(*http.Request) bool { return true },
+}
+
+// MaxSubscriptions is the number of symbols one stream may subscribe to.
+func MaxSubscriptions() int {
+	if value, err := strconv.Atoi(os.Getenv("WS_MAX_SUBSCRIPTIONS")); err == nil && value > 0 {
+		return value
+	}
+	return defaultMaxSubscriptions
+}
+
+// wsClient is one WebSocket connection. Its read loop handles client
+// messages; its write loop owns all writes to the connection.
+type wsClient struct {
+	conn         *websocket.Conn
+	subscription *Subscription
+	username     string
+	limit        int
+	replies      chan ServerMessage
+	done         chan struct{}
+}
+
+// WSHandler upgrades to a WebSocket that pushes every price write of the
+// subscribed symbols. Initial symbols may be given in the symbols query
+// parameter, comma separated.
+func WSHandler(hub *Hub) http.HandlerFunc {
+	limit := MaxSubscriptions()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var symbols []string
+		if value := r.URL.Query().Get("symbols"); value != "" {
+			var err error
+			if symbols, err = NormalizeSymbols(strings.Split(value, ",")); err != nil {
+				http.Error(w, `Bad Request - `+err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		if len(symbols) > limit {
+			http.Error(w, `Bad Request - `+ErrTooManySubscriptions.Error(), http.StatusBadRequest)
+			return
+		}
+
+		subscription, err := hub.Subscribe()
+		if err == ErrTooManyConnections {
+			log.Println(err)
+			http.Error(w, `Service Unavailable - too many streaming connections`, http.StatusServiceUnavailable)
+			return
+		}
+		subscription.Add(symbols, limit)
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			log.Println("error during websocket upgrade: ", err)
+			subscription.Close()
+			return
+		}
+
+		client := &wsClient{
+			conn:         conn,
+			subscription: subscription,
+			username:     auth.Username(r.Context()),
+			limit:        limit,
+			replies:      make(chan ServerMessage, pendingReplies),
+			done:         make(chan struct{}),
+		}
+		client.reply(ServerMessage{Type: "subscribed", Symbols: subscription.Symbols()})
+
+		go client.writeLoop()
+		client.readLoop()
+	}
+}
+
+// reply queues a message for the write loop. A client that keeps sending
+// without reading the replies is disconnected.
+func (c *wsClient) reply(message ServerMessage) bool {
+	select {
+	case c.replies <- message:
+		return true
+	default:
+		log.Printf("Closing websocket of %s: replies not read", c.username)
+		return false
+	}
+}
+
+func (c *wsClient) readLoop() {
+	defer func() {
+		c.subscription.Close()
+		close(c.done)
+	}()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				log.Printf("Websocket of %s closed: %v", c.username, err)
+			}
+			return
+		}
+		// Any message counts as a sign of life, as pongs do.
+		c.conn.SetReadDeadline(time.Now().Add(pongWait))
+
+		if !c.reply(c.handle(data)) {
+			return
+		}
+	}
+}
+
+// handle applies one client message and returns the reply to it.
+func (c *wsClient) handle(data []byte) ServerMessage {
+	var message ClientMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		return ServerMessage{Type: "error", Message: "invalid message"}
+	}
+
+	switch message.Action {
+	case ActionPing:
+		return ServerMessage{Type: "pong"}
+
+	case ActionSubscribe, ActionUnsubscribe:
+		symbols, err := NormalizeSymbols(message.Symbols)
+		if err != nil {
+			return ServerMessage{Type: "error", Message: err.Error()}
+		}
+		if message.Action == ActionUnsubscribe {
+			c.subscription.Remove(symbols)
+		} else if err := c.subscription.Add(symbols, c.limit); err != nil {
+			return ServerMessage{Type: "error", Message: "at most " + strconv.Itoa(c.limit) + " symbols per connection"}
+		}
+		return ServerMessage{Type: "subscribed", Symbols: c.subscription.Symbols()}
+	}
+
+	return ServerMessage{Type: "error", Message: "action must be subscribe, unsubscribe or ping"}
+}
+
+func (c *wsClient) write(message ServerMessage) error {
+	c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+	return c.conn.WriteJSON(message)
+}
+
+// writeLoop sends replies, price updates and pings until the read loop ends,
+// the hub closes the subscription or a write does not finish within
+// writeWait. A write that slow means the client cannot keep up and the
+// connection is dropped.
+func (c *wsClient) writeLoop() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case message := <-c.replies:
+			if err := c.write(message); err != nil {
+				return
+			}
+
+		case <-c.subscription.Ready():
+			ticks, skipped := c.subscription.Take()
+			if skipped > 0 {
+				if err := c.write(ServerMessage{Type: "lagged", Skipped: skipped}); err != nil {
+					return
+				}
+			}
+			for i := range ticks {
+				if err := c.write(ServerMessage{Type: "price", ID: ticks[i].ID, Data: &ticks[i].PriceUpdate}); err != nil {
+					return
+				}
+			}
+
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				return
+			}
+
+		case <-c.subscription.Closed():
+			c.conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"), time.Now().Add(writeWait))
+			return
+
+		case <-c.done:
+			return
+		}
+	}
+}
@@ -1,12 +1,18 @@
 package updater
 
 import (
+	"RESTCryptoServer/internal/provider"
 	"encoding/json"
 	"log"
 	"net/http"
 	"time"
 )
 
+const (
+	MinIntervalSeconds = 10
+	MaxIntervalSeconds = 3600
+)
+
 type ScheduleSubParams struct {
 	Enabled         bool `json:"enabled"`
 	IntervalSeconds int  `json:"interval_seconds"`
@@ -60,7 +66,7 @@ func PUTScheduleParamsHandler(u *Updater) http.HandlerFunc {
 		var putRequest PUTRequest
 		
 		err := json.NewDecoder(r.Body).Decode(&putRequest)
-		if err != nil || !(putRequest.IntervalSeconds <= 3600 && putRequest.IntervalSeconds >= 10) {
+		if err != nil || !(putRequest.IntervalSeconds <= MaxIntervalSeconds && putRequest.IntervalSeconds >= MinIntervalSeconds) {
 			log.Println("Error during JSON parsing or invalid interval: ", err)
 			http.Error(w, `{"error": "Bad Request - interval must be 10-3600 seconds"}`, http.StatusBadRequest)
 			return
@@ -69,10 +75,7 @@ func PUTScheduleParamsHandler(u *Updater) http.HandlerFunc {
 		if putRequest.Enabled {
 			u.RestartUpdating(putRequest.IntervalSeconds)
 		} else {
-			u.mu.Lock()
-			u.Enabled = false
-			u.mu.Unlock()
-			u.EndUpdating()
+			u.Disable()
 		}
 
 		response := ScheduleSubParams{
@@ -88,7 +91,10 @@ func PUTScheduleParamsHandler(u *Updater) http.HandlerFunc {
 
 func POSTScheduleTriggerHandler(u *Updater) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		cnt, err := u.Update()
+		cnt, err := u.Update(r.Context())
+		if provider.WriteHTTPError(w, err) {
+			return
+		}
 		if err != nil {
 			log.Printf("Manual trigger failed: %v", err)
 			http.Error(w, `Server error`, http.StatusInternalServerError)
@@ -2,6 +2,7 @@ package updater
 
 import (
 	"RESTCryptoServer/internal/crypto"
+	"context"
 	"log"
 	"sync"
 	"time"
@@ -12,6 +13,8 @@ type Updater struct {
 	CryptoService *crypto.CryptoService
 	mu            sync.Mutex
 	StopChan      chan struct{}
+	// cancel aborts the update of the running ticker loop when it is stopped.
+	cancel        context.CancelFunc
 	LastUpdate    time.Time 
 	Enabled       bool
 }
@@ -40,6 +43,9 @@ func (u *Updater) StartUpdating() {
 
 	log.Printf("Updater started with interval: %s", u.UpdateTime)
 
+	ctx, cancel := context.WithCancel(context.Background())
+	u.cancel = cancel
+
 	go func() {
 		ticker := time.NewTicker(u.UpdateTime)
 		defer ticker.Stop()
@@ -47,7 +53,7 @@ func (u *Updater) StartUpdating() {
 		for {
 			select {
 			case <- ticker.C:
-				u.CryptoService.UpdateAllCryptos()
+				u.CryptoService.UpdateAllCryptos(ctx)
 				u.LastUpdate = time.Now()
 			case <- u.StopChan:
 				return
@@ -69,6 +75,9 @@ func (u *Updater) EndUpdating() {
 		log.Println("Updater: stop requested, but already stopped")
 	default:
 		close(u.StopChan)
+		if u.cancel != nil {
+			u.cancel()
+		}
 		log.Println("Updater: stop requested, stopping updater")
 	}
 }
@@ -89,6 +98,15 @@ func (u *Updater) RestartUpdating(seconds int) {
 	u.StartUpdating()
 }
 
+// Disable stops automatic updates until the next RestartUpdating.
+func (u *Updater) Disable() {
+	u.EndUpdating()
+
+	u.mu.Lock()
+	u.Enabled = false
+	u.mu.Unlock()
+}
+
 func (u *Updater) GetUpdateTime() int {
 	u.mu.Lock()
 	defer u.mu.Unlock()
@@ -110,10 +128,10 @@ func (u *Updater) IsEnabled() bool {
 	return u.Enabled
 }
 
-func (u *Updater) Update() (int, error) {
+func (u *Updater) Update(ctx context.Context) (int, error) {
 	u.mu.Lock()
 	defer u.mu.Unlock()
 
-	cnt, err := u.CryptoService.UpdateAllCryptos() 
+	cnt, err := u.CryptoService.UpdateAllCryptos(ctx) 
 	return cnt, err
 }
\ No newline at end of file
@@ -0,0 +1,241 @@
+package watchlist
+
+import (
+	"RESTCryptoServer/internal/auth"
+	"RESTCryptoServer/internal/crypto"
+	"RESTCryptoServer/internal/db"
+	"RESTCryptoServer/internal/provider"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type NameJSON struct {
+	Name string `json:"name"`
+}
+
+func watchlistID(r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	return id, err == nil && id > 0
+}
+
+// writeWatchlistError maps the watchlist errors shared by every handler and
+// reports whether err was handled.
+func writeWatchlistError(w http.ResponseWriter, err error) bool {
+	switch err {
+	case nil:
+		return false
+	case db.ErrUnknownWatchlist, db.ErrSymbolNotInWatchlist:
+		log.Println(err)
+		http.Error(w, `Not Found`, http.StatusNotFound)
+	case db.ErrWatchlistNameUsed:
+		log.Println(err)
+		http.Error(w, `Name conflict`, http.StatusConflict)
+	case ErrInvalidName, db.ErrWatchlistFull:
+		log.Println(err)
+		http.Error(w, `Bad Request - `+err.Error(), http.StatusBadRequest)
+	default:
+		return false
+	}
+	return true
+}
+
+func GETWatchlistsHandler(ws *WatchlistService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		watchlists, err := ws.List(auth.Username(r.Context()))
+		if err != nil {
+			log.Println("error during listing watchlists: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(watchlists)
+	}
+}
+
+func POSTWatchlistHandler(ws *WatchlistService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var nameJSON NameJSON
+		if err := json.NewDecoder(r.Body).Decode(&nameJSON); err != nil {
+			log.Println("Error during JSON parsing: ", err)
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		watchlist, err := ws.Create(auth.Username(r.Context()), nameJSON.Name)
+		if writeWatchlistError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during creating watchlist: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		json.NewEncoder(w).Encode(watchlist)
+	}
+}
+
+func GETWatchlistHandler(ws *WatchlistService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := watchlistID(r)
+		if !ok {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		resp, err := ws.Get(auth.Username(r.Context()), id, r.URL.Query().Get("vs"))
+		if err == crypto.ErrUnsupportedCurrency {
+			log.Println(err)
+			http.Error(w, `Bad Request - unsupported currency`, http.StatusBadRequest)
+			return
+		}
+		if writeWatchlistError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during getting watchlist: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+func PUTWatchlistHandler(ws *WatchlistService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := watchlistID(r)
+		if !ok {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		var nameJSON NameJSON
+		if err := json.NewDecoder(r.Body).Decode(&nameJSON); err != nil {
+			log.Println("Error during JSON parsing: ", err)
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		watchlist, err := ws.Rename(auth.Username(r.Context()), id, nameJSON.Name)
+		if writeWatchlistError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during renaming watchlist: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(watchlist)
+	}
+}
+
+func DELETEWatchlistHandler(ws *WatchlistService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := watchlistID(r)
+		if !ok {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		err := ws.Delete(auth.Username(r.Context()), id)
+		if writeWatchlistError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during deleting watchlist: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	}
+}
+
+func POSTWatchlistSymbolHandler(ws *WatchlistService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := watchlistID(r)
+		if !ok {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		var symbolJSON crypto.SymbolJSON
+		if err := json.NewDecoder(r.Body).Decode(&symbolJSON); err != nil {
+			log.Println("Error during JSON parsing: ", err)
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+		if symbolJSON.Symbol == "" {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		watchlist, err := ws.AddSymbol(r.Context(), auth.Username(r.Context()), id, symbolJSON.Symbol, symbolJSON.CoinID)
+		if writeWatchlistError(w, err) {
+			return
+		}
+		if err == crypto.ErrUnknownCoinID || err == crypto.ErrSymbolMismatch || errors.Is(err, provider.ErrCoinNotFound) {
+			log.Println(err)
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+		if provider.WriteHTTPError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during adding symbol to watchlist: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(watchlist)
+	}
+}
+
+func DELETEWatchlistSymbolHandler(ws *WatchlistService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := watchlistID(r)
+		if !ok {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		err := ws.RemoveSymbol(auth.Username(r.Context()), id, chi.URLParam(r, "symbol"))
+		if writeWatchlistError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during removing symbol from watchlist: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	}
+}
@@ -0,0 +1,181 @@
+package watchlist
+
+import (
+	"RESTCryptoServer/internal/crypto"
+	"RESTCryptoServer/internal/db"
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	MaxWatchlistSymbols = 100
+	maxNameLength       = 100
+)
+
+var ErrInvalidName = errors.New("watchlist name must be 1 to 100 characters")
+
+type WatchlistResponse struct {
+	db.Watchlist
+	Currency  string                  `json:"currency"`
+	Cryptos   []crypto.CryptoResponse `json:"cryptos"`
+	Untracked []db.WatchlistItem      `json:"untracked"`
+}
+
+type WatchlistService struct {
+	watchlistDB   *db.WatchlistDB
+	cryptoService *crypto.CryptoService
+}
+
+func NewWatchlistService(watchlistDB *db.WatchlistDB, cryptoService *crypto.CryptoService) *WatchlistService {
+	return &WatchlistService{
+		watchlistDB:   watchlistDB,
+		cryptoService: cryptoService,
+	}
+}
+
+func validName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxNameLength {
+		return "", ErrInvalidName
+	}
+	return name, nil
+}
+
+func (ws *WatchlistService) List(owner string) ([]db.Watchlist, error) {
+	return ws.watchlistDB.List(owner)
+}
+
+func (ws *WatchlistService) Create(owner string, name string) (*db.Watchlist, error) {
+	name, err := validName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	watchlist, err := ws.watchlistDB.Create(owner, name)
+	if err != nil {
+		return nil, err
+	}
+	return &watchlist, nil
+}
+
+// Get returns a watchlist with the current price of its members in vs. Members
+// without a stored price in vs are left out of Cryptos but stay in Symbols.
+// Members no longer tracked, because the coin was deleted, are listed in
+// Untracked; adding them again tracks them as the coin they were added as.
+func (ws *WatchlistService) Get(owner string, id int64, vs string) (*WatchlistResponse, error) {
+	currencies := ws.cryptoService.QuoteCurrencies()
+	vs = strings.ToLower(strings.TrimSpace(vs))
+	if vs == "" {
+		vs = currencies[0]
+	}
+	if !slices.Contains(currencies, vs) {
+		return nil, crypto.ErrUnsupportedCurrency
+	}
+
+	watchlist, err := ws.watchlistDB.Get(owner, id)
+	if err != nil {
+		return nil, err
+	}
+	items, err := ws.watchlistDB.Items(id)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &WatchlistResponse{
+		Watchlist: watchlist,
+		Currency:  vs,
+		Cryptos:   make([]crypto.CryptoResponse, 0, len(items)),
+		Untracked: []db.WatchlistItem{},
+	}
+	for _, item := range items {
+		coin, err := ws.cryptoService.GetCrypto(item.Symbol, vs)
+		if err == crypto.ErrCryptoNotFound {
+			resp.Untracked = append(resp.Untracked, item)
+			continue
+		}
+		if err == crypto.ErrPriceUnavailable {
+			log.Printf("Skipping %s in watchlist %d: %v", item.Symbol, id, err)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		resp.Cryptos = append(resp.Cryptos, *coin)
+	}
+
+	return resp, nil
+}
+
+func (ws *WatchlistService) Rename(owner string, id int64, name string) (*db.Watchlist, error) {
+	name, err := validName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ws.watchlistDB.Rename(owner, id, name); err != nil {
+		return nil, err
+	}
+
+	watchlist, err := ws.watchlistDB.Get(owner, id)
+	if err != nil {
+		return nil, err
+	}
+	return &watchlist, nil
+}
+
+func (ws *WatchlistService) Delete(owner string, id int64) error {
+	return ws.watchlistDB.Delete(owner, id)
+}
+
+// AddSymbol adds a symbol to a watchlist, starting to track it first if needed.
+// coinID optionally picks the asset of an ambiguous symbol, as in POST /crypto;
+// without it a member added before is tracked again as the coin it was added as.
+func (ws *WatchlistService) AddSymbol(ctx context.Context, owner string, id int64, symbol string, coinID string) (*db.Watchlist, error) {
+	symbol = strings.ToLower(strings.TrimSpace(symbol))
+
+	if _, err := ws.watchlistDB.Get(owner, id); err != nil {
+		return nil, err
+	}
+
+	if coinID == "" {
+		items, err := ws.watchlistDB.Items(id)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if item.Symbol == symbol {
+				coinID = item.CoinID
+			}
+		}
+	}
+
+	if err := ws.cryptoService.EnsureTracked(ctx, symbol, coinID); err != nil {
+		return nil, err
+	}
+
+	// Pin the member to the coin that is tracked, which is not coinID when the
+	// symbol was tracked already.
+	trackedID, err := ws.cryptoService.TrackedCoinID(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ws.watchlistDB.AddSymbol(owner, id, symbol, trackedID, MaxWatchlistSymbols); err != nil {
+		return nil, err
+	}
+
+	watchlist, err := ws.watchlistDB.Get(owner, id)
+	if err != nil {
+		return nil, err
+	}
+	return &watchlist, nil
+}
+
+// RemoveSymbol removes a symbol from a watchlist. The coin stays tracked.
+func (ws *WatchlistService) RemoveSymbol(owner string, id int64, symbol string) error {
+	return ws.watchlistDB.RemoveSymbol(owner, id, strings.ToLower(symbol))
+}
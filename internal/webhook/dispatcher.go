@@ -0,0 +1,435 @@
+package webhook
+
+import (
+	"RESTCryptoServer/internal/crypto"
+	"RESTCryptoServer/internal/db"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	mathrand "math/rand"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	EventPriceUpdated = "price.updated"
+	EventAlertFired   = "alert.fired"
+	EventTest         = "webhook.test"
+)
+
+const (
+	// pendingEvents bounds how many events may wait to be queued. A full
+	// buffer drops price batches; the next write sends fresh prices anyway.
+	pendingEvents = 256
+	pollInterval  = 5 * time.Second
+	purgeInterval = time.Hour
+	maxErrorBody  = 512
+	userAgent     = "RESTCryptoServer-Webhooks/1.0"
+)
+
+// Event is the JSON body of every delivery.
+type Event struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// AlertFiredData is the data of an alert.fired event.
+type AlertFiredData struct {
+	Alert db.Alert      `json:"alert"`
+	Event db.AlertEvent `json:"event"`
+}
+
+// PriceUpdatedData is the data of a price.updated event, limited to the
+// symbols of the webhook.
+type PriceUpdatedData struct {
+	Prices []crypto.PriceUpdate `json:"prices"`
+}
+
+// queuedEvent is an event waiting to be fanned out to its webhooks.
+type queuedEvent struct {
+	prices []crypto.PriceUpdate
+	alert  *AlertFiredData
+}
+
+// deliveryQueue is the part of db.WebhookDB the dispatcher works with.
+type deliveryQueue interface {
+	Subscribed(event string, owner string, symbols []string) ([]db.Webhook, error)
+	Enqueue(deliveries []db.WebhookDelivery) error
+	EnqueueClaimed(delivery db.WebhookDelivery, lease time.Duration) (db.WebhookDelivery, error)
+	Claim(limit int, lease time.Duration) ([]db.WebhookDelivery, error)
+	RecordAttempt(id int64, status string, responseStatus int, lastError string, nextAttempt time.Time) (db.WebhookDelivery, error)
+	PurgeDeliveries(retention time.Duration) (int64, error)
+}
+
+// Dispatcher turns price writes and alert firings into deliveries, stores
+// them in the webhook_deliveries queue and sends them with exponential
+// backoff until they succeed or run out of attempts.
+type Dispatcher struct {
+	webhookDB deliveryQueue
+	client    *http.Client
+
+	maxAttempts int
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+	workers     int
+	lease       time.Duration
+	retention   time.Duration
+
+	events    chan queuedEvent
+	wake      chan struct{}
+	lastPurge time.Time
+	stopOnce  sync.Once
+	stopChan  chan struct{}
+}
+
+func NewDispatcher(webhookDB *db.WebhookDB) *Dispatcher {
+	timeout := time.Duration(envInt("WEBHOOK_TIMEOUT_SECONDS", 10)) * time.Second
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+
+	maxAttempts := envInt("WEBHOOK_MAX_ATTEMPTS", 8)
+	if maxAttempts == 0 {
+		maxAttempts = 1
+	}
+
+	workers := envInt("WEBHOOK_WORKERS", 4)
+	if workers == 0 {
+		workers = 1
+	}
+
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if !allowPrivateTargets() {
+		dialer.Control = publicOnly
+	}
+
+	return &Dispatcher{
+		webhookDB: webhookDB,
+		client: &http.Client{
+			Timeout: timeout,
+			// Proxies are not used so the dialer sees the real target address.
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: 5 * time.Second,
+				MaxIdleConnsPerHost: 2,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		maxAttempts: maxAttempts,
+		baseBackoff: time.Duration(envInt("WEBHOOK_RETRY_BASE_SECONDS", 30)) * time.Second,
+		maxBackoff:  time.Hour,
+		workers:     workers,
+		lease:       timeout + 30*time.Second,
+		retention:   time.Duration(envInt("WEBHOOK_DELIVERY_RETENTION_DAYS", 7)) * 24 * time.Hour,
+		events:      make(chan queuedEvent, pendingEvents),
+		wake:        make(chan struct{}, 1),
+		stopChan:    make(chan struct{}),
+	}
+}
+
+func envInt(name string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(name)); err == nil && value >= 0 {
+		return value
+	}
+	return fallback
+}
+
+// SetHTTPClient replaces the client deliveries are sent with, e.g. the one of
+// an httptest server.
+func (d *Dispatcher) SetHTTPClient(client *http.Client) {
+	d.client = client
+}
+
+// PriceUpdated queues a price.updated event. It is a crypto.PriceListener.
+func (d *Dispatcher) PriceUpdated(updates []crypto.PriceUpdate) {
+	select {
+	case d.events <- queuedEvent{prices: updates}:
+	default:
+		log.Printf("Webhook dispatcher: queue full, skipping %d price updates", len(updates))
+	}
+}
+
+// AlertFired queues an alert.fired event. It is an alert.FireListener; firings
+// are rare and recorded, so it waits for room instead of dropping them.
+func (d *Dispatcher) AlertFired(alert db.Alert, event db.AlertEvent) {
+	select {
+	case d.events <- queuedEvent{alert: &AlertFiredData{Alert: alert, Event: event}}:
+	case <-d.stopChan:
+	}
+}
+
+func (d *Dispatcher) Start() {
+	log.Printf("Webhook dispatcher started with %d workers", d.workers)
+
+	go func() {
+		for {
+			select {
+			case event := <-d.events:
+				if err := d.enqueue(event); err != nil {
+					log.Printf("Failed to queue webhook deliveries: %v", err)
+				}
+			case <-d.stopChan:
+				return
+			}
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+
+		for {
+			d.deliverDue()
+
+			select {
+			case <-ticker.C:
+			case <-d.wake:
+			case <-d.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+func (d *Dispatcher) Stop() {
+	d.stopOnce.Do(func() {
+		close(d.stopChan)
+		log.Println("Webhook dispatcher stopped")
+	})
+}
+
+// Wake makes the dispatcher look for due deliveries now.
+func (d *Dispatcher) Wake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+func newEventID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return "evt_" + hex.EncodeToString(b)
+}
+
+// enqueue stores one delivery of event for every webhook subscribed to it.
+func (d *Dispatcher) enqueue(queued queuedEvent) error {
+	var deliveries []db.WebhookDelivery
+
+	switch {
+	case queued.alert != nil:
+		webhooks, err := d.webhookDB.Subscribed(EventAlertFired, queued.alert.Alert.Owner, []string{queued.alert.Alert.Symbol})
+		if err != nil || len(webhooks) == 0 {
+			return err
+		}
+		event := Event{ID: newEventID(), Type: EventAlertFired, CreatedAt: queued.alert.Event.FiredAt, Data: queued.alert}
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		for _, webhook := range webhooks {
+			deliveries = append(deliveries, db.WebhookDelivery{WebhookID: webhook.ID, EventID: event.ID, Event: event.Type, Payload: payload})
+		}
+
+	default:
+		symbols := make([]string, 0, len(queued.prices))
+		for _, update := range queued.prices {
+			symbols = append(symbols, update.Symbol)
+		}
+		webhooks, err := d.webhookDB.Subscribed(EventPriceUpdated, "", symbols)
+		if err != nil || len(webhooks) == 0 {
+			return err
+		}
+
+		event := Event{ID: newEventID(), Type: EventPriceUpdated, CreatedAt: time.Now().UTC()}
+		for _, webhook := range webhooks {
+			prices := queued.prices
+			if len(webhook.Symbols) > 0 {
+				prices = nil
+				for _, update := range queued.prices {
+					if slices.Contains(webhook.Symbols, update.Symbol) {
+						prices = append(prices, update)
+					}
+				}
+			}
+			event.Data = PriceUpdatedData{Prices: prices}
+			payload, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+			deliveries = append(deliveries, db.WebhookDelivery{WebhookID: webhook.ID, EventID: event.ID, Event: event.Type, Payload: payload})
+		}
+	}
+
+	if err := d.webhookDB.Enqueue(deliveries); err != nil {
+		return err
+	}
+	d.Wake()
+	return nil
+}
+
+// deliverDue sends due deliveries until none are left, then purges old ones
+// once per purgeInterval.
+func (d *Dispatcher) deliverDue() {
+	batch := d.workers * 4
+
+	for {
+		deliveries, err := d.webhookDB.Claim(batch, d.lease)
+		if err != nil {
+			log.Printf("Failed to claim webhook deliveries: %v", err)
+			return
+		}
+
+		var wg sync.WaitGroup
+		slots := make(chan struct{}, d.workers)
+		for _, delivery := range deliveries {
+			wg.Add(1)
+			slots <- struct{}{}
+			go func() {
+				defer wg.Done()
+				defer func() { <-slots }()
+				d.attempt(delivery)
+			}()
+		}
+		wg.Wait()
+
+		if len(deliveries) < batch {
+			break
+		}
+		select {
+		case <-d.stopChan:
+			return
+		default:
+		}
+	}
+
+	if d.retention > 0 && time.Since(d.lastPurge) >= purgeInterval {
+		d.lastPurge = time.Now()
+		purged, err := d.webhookDB.PurgeDeliveries(d.retention)
+		if err != nil {
+			log.Printf("Failed to purge webhook deliveries: %v", err)
+		} else if purged > 0 {
+			log.Printf("Purged %d webhook deliveries", purged)
+		}
+	}
+}
+
+// Send posts a claimed delivery to its webhook once and returns the response
+// status, 0 when none was received. Any status other than 2xx is an error.
+func (d *Dispatcher) Send(delivery db.WebhookDelivery) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(EventHeader, delivery.Event)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if text := strings.TrimSpace(string(body)); text != "" {
+			return resp.StatusCode, fmt.Errorf("HTTP %d: %s", resp.StatusCode, text)
+		}
+		return resp.StatusCode, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// attempt sends a claimed delivery and records the outcome: delivered, retried
+// after a backoff, or failed once maxAttempts are used up.
+func (d *Dispatcher) attempt(delivery db.WebhookDelivery) db.WebhookDelivery {
+	responseStatus, err := d.Send(delivery)
+	attempts := delivery.Attempts + 1
+
+	status := db.DeliveryDelivered
+	next := time.Now().UTC()
+	message := ""
+	if err != nil {
+		message = err.Error()
+		status = db.DeliveryFailed
+		if attempts < d.maxAttempts {
+			status = db.DeliveryPending
+			next = next.Add(d.backoff(attempts))
+		}
+		log.Printf("Webhook %d: delivery %d attempt %d failed: %v", delivery.WebhookID, delivery.ID, attempts, err)
+	}
+
+	recorded, recordErr := d.webhookDB.RecordAttempt(delivery.ID, status, responseStatus, message, next)
+	if recordErr != nil {
+		log.Printf("Webhook %d: failed to record delivery %d: %v", delivery.WebhookID, delivery.ID, recordErr)
+		return delivery
+	}
+	return recorded
+}
+
+// backoff doubles the wait from baseBackoff with every failed attempt up to
+// maxBackoff, randomized by up to half so receivers recovering from an outage
+// are not hit by every retry at once.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := d.baseBackoff << (attempts - 1)
+	if wait <= 0 || wait > d.maxBackoff {
+		wait = d.maxBackoff
+	}
+	half := int64(wait / 2)
+	if half <= 0 {
+		return wait
+	}
+	return time.Duration(half + mathrand.Int63n(half+1))
+}
+
+// SendTest queues a webhook.test event for webhook and attempts it right away.
+// A failed first attempt is retried like any other delivery.
+func (d *Dispatcher) SendTest(webhook db.Webhook) (db.WebhookDelivery, error) {
+	event := Event{
+		ID:        newEventID(),
+		Type:      EventTest,
+		CreatedAt: time.Now().UTC(),
+		Data:      map[string]any{"webhook_id": webhook.ID, "message": "Test event"},
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return db.WebhookDelivery{}, err
+	}
+
+	delivery, err := d.webhookDB.EnqueueClaimed(db.WebhookDelivery{
+		WebhookID: webhook.ID,
+		EventID:   event.ID,
+		Event:     event.Type,
+		Payload:   payload,
+	}, d.lease)
+	if err != nil {
+		return db.WebhookDelivery{}, err
+	}
+
+	delivery.URL = webhook.URL
+	delivery.Secret = webhook.Secret
+	return d.attempt(delivery), nil
+}
@@ -0,0 +1,320 @@
+package webhook
+
+import (
+	"RESTCryptoServer/internal/db"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// memoryQueue is an in-memory deliveryQueue that keeps the delivery log of
+// one webhook.
+type memoryQueue struct {
+	mu         sync.Mutex
+	webhook    db.Webhook
+	deliveries []db.WebhookDelivery
+}
+
+func (q *memoryQueue) Subscribed(event string, owner string, symbols []string) ([]db.Webhook, error) {
+	return []db.Webhook{q.webhook}, nil
+}
+
+func (q *memoryQueue) Enqueue(deliveries []db.WebhookDelivery) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now().UTC()
+	for _, delivery := range deliveries {
+		delivery.ID = int64(len(q.deliveries) + 1)
+		delivery.Status = db.DeliveryPending
+		delivery.NextAttemptAt = &now
+		q.deliveries = append(q.deliveries, delivery)
+	}
+	return nil
+}
+
+func (q *memoryQueue) EnqueueClaimed(delivery db.WebhookDelivery, lease time.Duration) (db.WebhookDelivery, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	leased := time.Now().UTC().Add(lease)
+	delivery.ID = int64(len(q.deliveries) + 1)
+	delivery.Status = db.DeliveryPending
+	delivery.NextAttemptAt = &leased
+	q.deliveries = append(q.deliveries, delivery)
+	return delivery, nil
+}
+
+func (q *memoryQueue) Claim(limit int, lease time.Duration) ([]db.WebhookDelivery, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now().UTC()
+	leased := now.Add(lease)
+	var claimed []db.WebhookDelivery
+	for i := range q.deliveries {
+		delivery := &q.deliveries[i]
+		if delivery.Status != db.DeliveryPending || delivery.NextAttemptAt.After(now) || len(claimed) == limit {
+			continue
+		}
+		delivery.NextAttemptAt = &leased
+		claimed = append(claimed, *delivery)
+		claimed[len(claimed)-1].URL = q.webhook.URL
+		claimed[len(claimed)-1].Secret = q.webhook.Secret
+	}
+	return claimed, nil
+}
+
+func (q *memoryQueue) RecordAttempt(id int64, status string, responseStatus int, lastError string, nextAttempt time.Time) (db.WebhookDelivery, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delivery := &q.deliveries[id-1]
+	delivery.Status = status
+	delivery.Attempts++
+	delivery.LastError = lastError
+	delivery.ResponseStatus = nil
+	if responseStatus != 0 {
+		delivery.ResponseStatus = &responseStatus
+	}
+	delivery.NextAttemptAt = nil
+	if status == db.DeliveryPending {
+		delivery.NextAttemptAt = &nextAttempt
+	}
+	if status == db.DeliveryDelivered {
+		delivery.DeliveredAt = &nextAttempt
+	}
+	return *delivery, nil
+}
+
+func (q *memoryQueue) PurgeDeliveries(retention time.Duration) (int64, error) {
+	return 0, nil
+}
+
+func (q *memoryQueue) delivery(id int64) db.WebhookDelivery {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.deliveries[id-1]
+}
+
+// receiver is a webhook endpoint that checks the signature of every request
+// and answers with the next of its statuses, repeating the last one.
+type receiver struct {
+	t        *testing.T
+	secret   string
+	statuses []int
+	calls    atomic.Int32
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	call := int(rc.calls.Add(1))
+
+	payload, _ := io.ReadAll(r.Body)
+	if err := Verify(rc.secret, r.Header.Get(SignatureHeader), r.Header.Get(TimestampHeader), payload, time.Minute); err != nil {
+		rc.t.Errorf("call %d: %v", call, err)
+	}
+	if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+		rc.t.Errorf("call %d: got %s with Content-Type %q, want a JSON POST", call, r.Method, r.Header.Get("Content-Type"))
+	}
+	if r.Header.Get(EventHeader) == "" || r.Header.Get(DeliveryHeader) == "" || r.Header.Get("User-Agent") != userAgent {
+		rc.t.Errorf("call %d: missing event, delivery or user agent header: %v", call, r.Header)
+	}
+
+	status := rc.statuses[min(call, len(rc.statuses))-1]
+	w.WriteHeader(status)
+	if status >= 300 {
+		w.Write([]byte("  receiver is down\n"))
+	}
+}
+
+// newTestDispatcher returns a dispatcher with a backoff of 10 to 80ms
+// delivering to a receiver answering with statuses.
+func newTestDispatcher(t *testing.T, maxAttempts int, statuses ...int) (*Dispatcher, *memoryQueue, *receiver) {
+	t.Helper()
+
+	rc := &receiver{t: t, secret: "whsec_0123456789abcdef", statuses: statuses}
+	server := httptest.NewServer(rc)
+	t.Cleanup(server.Close)
+
+	queue := &memoryQueue{webhook: db.Webhook{ID: 7, URL: server.URL + "/hook", Secret: rc.secret, Enabled: true}}
+	d := NewDispatcher(nil)
+	d.webhookDB = queue
+	d.SetHTTPClient(server.Client())
+	d.maxAttempts = maxAttempts
+	d.baseBackoff = 20 * time.Millisecond
+	d.maxBackoff = 80 * time.Millisecond
+	return d, queue, rc
+}
+
+func TestDispatcherSend(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		wantErr    string
+		wantStatus int
+	}{
+		{"accepted", http.StatusNoContent, "", http.StatusNoContent},
+		{"server error", http.StatusBadGateway, "HTTP 502: receiver is down", http.StatusBadGateway},
+		{"redirect is not followed", http.StatusFound, "HTTP 302: receiver is down", http.StatusFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, queue, rc := newTestDispatcher(t, 3, tt.status)
+
+			status, err := d.Send(db.WebhookDelivery{
+				ID:      42,
+				Event:   EventTest,
+				Payload: []byte(`{"id":"evt_1"}`),
+				URL:     queue.webhook.URL,
+				Secret:  rc.secret,
+			})
+			if status != tt.wantStatus {
+				t.Errorf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if (err == nil && tt.wantErr != "") || (err != nil && err.Error() != tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDispatcherSendTest(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxAttempts  int
+		status       int
+		wantStatus   string
+		wantError    bool
+		wantNextWait bool
+	}{
+		{"delivered", 3, http.StatusOK, db.DeliveryDelivered, false, false},
+		{"retried", 3, http.StatusServiceUnavailable, db.DeliveryPending, true, true},
+		{"failed without retries", 1, http.StatusServiceUnavailable, db.DeliveryFailed, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, queue, rc := newTestDispatcher(t, tt.maxAttempts, tt.status)
+
+			delivery, err := d.SendTest(queue.webhook)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if delivery.Status != tt.wantStatus || delivery.Attempts != 1 {
+				t.Errorf("delivery = %s after %d attempts, want %s after 1", delivery.Status, delivery.Attempts, tt.wantStatus)
+			}
+			if delivery.ResponseStatus == nil || *delivery.ResponseStatus != tt.status {
+				t.Errorf("response status = %v, want %d", delivery.ResponseStatus, tt.status)
+			}
+			if (delivery.LastError != "") != tt.wantError {
+				t.Errorf("last error = %q, want one: %v", delivery.LastError, tt.wantError)
+			}
+			if (delivery.NextAttemptAt != nil) != tt.wantNextWait {
+				t.Errorf("next attempt = %v, want one: %v", delivery.NextAttemptAt, tt.wantNextWait)
+			}
+			if delivery.Event != EventTest || !strings.Contains(string(delivery.Payload), strconv.FormatInt(queue.webhook.ID, 10)) {
+				t.Errorf("delivery = %+v, want a webhook.test event for webhook %d", delivery, queue.webhook.ID)
+			}
+			if got := rc.calls.Load(); got != 1 {
+				t.Errorf("receiver calls = %d, want 1", got)
+			}
+		})
+	}
+}
+
+// TestDispatcherRetries delivers an alert event to a receiver that fails
+// twice and checks that the retries are logged and the third attempt
+// delivers it.
+func TestDispatcherRetries(t *testing.T) {
+	d, queue, rc := newTestDispatcher(t, 5, http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusOK)
+
+	if err := d.enqueue(queuedEvent{alert: &AlertFiredData{Alert: db.Alert{ID: 3, Symbol: "btc"}}}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		d.deliverDue()
+
+		delivery := queue.delivery(1)
+		if delivery.Status != db.DeliveryPending || delivery.Attempts != attempt {
+			t.Fatalf("after attempt %d: delivery = %s after %d attempts, want pending", attempt, delivery.Status, delivery.Attempts)
+		}
+		if !strings.HasPrefix(delivery.LastError, "HTTP 5") || delivery.ResponseStatus == nil {
+			t.Errorf("after attempt %d: last error %q, response status %v", attempt, delivery.LastError, delivery.ResponseStatus)
+		}
+
+		// The retry is not due before its backoff has passed.
+		d.deliverDue()
+		if got := rc.calls.Load(); got != int32(attempt) {
+			t.Errorf("after attempt %d: receiver calls = %d before the backoff passed", attempt, got)
+		}
+		time.Sleep(time.Until(*delivery.NextAttemptAt) + time.Millisecond)
+	}
+
+	d.deliverDue()
+
+	delivery := queue.delivery(1)
+	if delivery.Status != db.DeliveryDelivered || delivery.Attempts != 3 || delivery.LastError != "" {
+		t.Errorf("delivery = %s after %d attempts with error %q, want delivered after 3", delivery.Status, delivery.Attempts, delivery.LastError)
+	}
+	if delivery.Event != EventAlertFired || delivery.WebhookID != queue.webhook.ID {
+		t.Errorf("delivery = %+v, want an alert.fired delivery to webhook %d", delivery, queue.webhook.ID)
+	}
+	if got := rc.calls.Load(); got != 3 {
+		t.Errorf("receiver calls = %d, want 3", got)
+	}
+}
+
+func TestDispatcherGivesUp(t *testing.T) {
+	d, queue, rc := newTestDispatcher(t, 2, http.StatusInternalServerError)
+
+	if err := d.enqueue(queuedEvent{alert: &AlertFiredData{Alert: db.Alert{ID: 3, Symbol: "btc"}}}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	d.deliverDue()
+	time.Sleep(time.Until(*queue.delivery(1).NextAttemptAt) + time.Millisecond)
+	d.deliverDue()
+	d.deliverDue()
+
+	delivery := queue.delivery(1)
+	if delivery.Status != db.DeliveryFailed || delivery.Attempts != 2 || delivery.NextAttemptAt != nil {
+		t.Errorf("delivery = %s after %d attempts, next %v, want failed after 2", delivery.Status, delivery.Attempts, delivery.NextAttemptAt)
+	}
+	if got := rc.calls.Load(); got != 2 {
+		t.Errorf("receiver calls = %d, want 2", got)
+	}
+}
+
+func TestDispatcherBackoff(t *testing.T) {
+	d := &Dispatcher{baseBackoff: 30 * time.Second, maxBackoff: time.Hour}
+
+	tests := []struct {
+		attempts int
+		ceiling  time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, time.Hour},
+		{80, time.Hour},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			got := d.backoff(tt.attempts)
+			if got < tt.ceiling/2 || got > tt.ceiling {
+				t.Fatalf("backoff(%d) = %v, want between %v and %v", tt.attempts, got, tt.ceiling/2, tt.ceiling)
+			}
+		}
+	}
+}
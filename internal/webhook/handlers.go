@@ -0,0 +1,263 @@
+package webhook
+
+import (
+	"RESTCryptoServer/internal/auth"
+	"RESTCryptoServer/internal/db"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func webhookID(r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	return id, err == nil && id > 0
+}
+
+// deliveryLimit reads the limit query parameter of the delivery log.
+func deliveryLimit(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return DefaultDeliveryLimit, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > MaxDeliveryLimit {
+		return 0, ErrInvalidLimit
+	}
+	return limit, nil
+}
+
+// writeWebhookError maps the webhook errors shared by every handler and
+// reports whether err was handled.
+func writeWebhookError(w http.ResponseWriter, err error) bool {
+	switch err {
+	case nil:
+		return false
+	case db.ErrUnknownWebhook, db.ErrUnknownDelivery:
+		log.Println(err)
+		http.Error(w, `Not Found`, http.StatusNotFound)
+	case db.ErrTooManyWebhooks:
+		log.Println(err)
+		http.Error(w, `Bad Request - at most 20 webhooks per user`, http.StatusBadRequest)
+	case ErrInvalidURL, ErrPrivateAddress, ErrInvalidEvents, ErrInvalidSymbols, ErrInvalidSecret, ErrInvalidStatus,
+		ErrInvalidLimit:
+		log.Println(err)
+		http.Error(w, `Bad Request - `+err.Error(), http.StatusBadRequest)
+	case db.ErrDeliveryNotFailed, ErrWebhookDisabled:
+		log.Println(err)
+		http.Error(w, `Conflict - `+err.Error(), http.StatusConflict)
+	default:
+		return false
+	}
+	return true
+}
+
+func GETWebhooksHandler(ws *WebhookService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		webhooks, err := ws.List(auth.Username(r.Context()))
+		if err != nil {
+			log.Println("error during listing webhooks: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(webhooks)
+	}
+}
+
+func POSTWebhookHandler(ws *WebhookService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var webhookJSON WebhookJSON
+		if err := json.NewDecoder(r.Body).Decode(&webhookJSON); err != nil {
+			log.Println("Error during JSON parsing: ", err)
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		webhook, err := ws.Create(auth.Username(r.Context()), webhookJSON)
+		if writeWebhookError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during creating webhook: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		json.NewEncoder(w).Encode(webhook)
+	}
+}
+
+func GETWebhookHandler(ws *WebhookService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := webhookID(r)
+		if !ok {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		webhook, err := ws.Get(auth.Username(r.Context()), id)
+		if writeWebhookError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during getting webhook: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(webhook)
+	}
+}
+
+func PUTWebhookHandler(ws *WebhookService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := webhookID(r)
+		if !ok {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		var webhookJSON WebhookJSON
+		if err := json.NewDecoder(r.Body).Decode(&webhookJSON); err != nil {
+			log.Println("Error during JSON parsing: ", err)
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		webhook, err := ws.Update(auth.Username(r.Context()), id, webhookJSON)
+		if writeWebhookError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during updating webhook: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(webhook)
+	}
+}
+
+func DELETEWebhookHandler(ws *WebhookService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := webhookID(r)
+		if !ok {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		err := ws.Delete(auth.Username(r.Context()), id)
+		if writeWebhookError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during deleting webhook: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	}
+}
+
+func POSTWebhookTestHandler(ws *WebhookService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := webhookID(r)
+		if !ok {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		delivery, err := ws.SendTest(auth.Username(r.Context()), id)
+		if writeWebhookError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during sending webhook test event: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(delivery)
+	}
+}
+
+func GETWebhookDeliveriesHandler(ws *WebhookService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := webhookID(r)
+		if !ok {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		limit, err := deliveryLimit(r)
+		if writeWebhookError(w, err) {
+			return
+		}
+
+		deliveries, err := ws.Deliveries(auth.Username(r.Context()), id, r.URL.Query().Get("status"), limit)
+		if writeWebhookError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during getting webhook deliveries: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		json.NewEncoder(w).Encode(deliveries)
+	}
+}
+
+func POSTWebhookRedeliverHandler(ws *WebhookService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := webhookID(r)
+		if !ok {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+		deliveryID, err := strconv.ParseInt(chi.URLParam(r, "deliveryID"), 10, 64)
+		if err != nil || deliveryID <= 0 {
+			http.Error(w, `Bad Request`, http.StatusBadRequest)
+			return
+		}
+
+		delivery, err := ws.Redeliver(auth.Username(r.Context()), id, deliveryID)
+		if writeWebhookError(w, err) {
+			return
+		}
+		if err != nil {
+			log.Println("error during retrying webhook delivery: ", err)
+			http.Error(w, `Server error`, http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+
+		json.NewEncoder(w).Encode(delivery)
+	}
+}
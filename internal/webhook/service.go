@@ -0,0 +1,232 @@
+package webhook
+
+import (
+	"RESTCryptoServer/internal/db"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
+)
+
+const (
+	MaxWebhooksPerUser    = 20
+	MaxWebhookSymbols     = 50
+	DefaultDeliveryLimit  = 50
+	MaxDeliveryLimit      = 500
+	minSecretLength       = 16
+	maxSecretLength       = 256
+	generatedSecretBytes  = 32
+	generatedSecretPrefix = "whsec_"
+)
+
+var (
+	ErrInvalidEvents   = errors.New("events must list price.updated and/or alert.fired")
+	ErrInvalidSymbols  = errors.New("symbols must list at most 50 symbols")
+	ErrInvalidSecret   = errors.New("secret must be 16 to 256 characters")
+	ErrInvalidStatus   = errors.New("status must be pending, delivered or failed")
+	ErrInvalidLimit    = errors.New("limit must be between 1 and 500")
+	ErrWebhookDisabled = errors.New("webhook is disabled")
+)
+
+// subscribableEvents are the events a webhook can subscribe to. webhook.test
+// is only sent on request.
+var subscribableEvents = []string{EventPriceUpdated, EventAlertFired}
+
+// WebhookJSON is a webhook as entered. Secret is generated when left out on
+// create and kept when left out on update; Enabled defaults to true.
+type WebhookJSON struct {
+	URL     string   `json:"url"`
+	Events  []string `json:"events"`
+	Symbols []string `json:"symbols"`
+	Secret  string   `json:"secret"`
+	Enabled *bool    `json:"enabled"`
+}
+
+type WebhookService struct {
+	webhookDB    *db.WebhookDB
+	dispatcher   *Dispatcher
+	allowPrivate bool
+}
+
+func NewWebhookService(webhookDB *db.WebhookDB, dispatcher *Dispatcher) *WebhookService {
+	return &WebhookService{
+		webhookDB:    webhookDB,
+		dispatcher:   dispatcher,
+		allowPrivate: allowPrivateTargets(),
+	}
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, generatedSecretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return generatedSecretPrefix + hex.EncodeToString(b), nil
+}
+
+// validWebhook checks an entered webhook and turns it into a webhook of owner.
+func (ws *WebhookService) validWebhook(owner string, input WebhookJSON) (db.Webhook, error) {
+	url, err := validURL(input.URL, ws.allowPrivate)
+	if err != nil {
+		return db.Webhook{}, err
+	}
+
+	webhook := db.Webhook{
+		Owner:   owner,
+		URL:     url,
+		Events:  []string{},
+		Symbols: []string{},
+		Secret:  strings.TrimSpace(input.Secret),
+		Enabled: true,
+	}
+	if input.Enabled != nil {
+		webhook.Enabled = *input.Enabled
+	}
+
+	for _, event := range input.Events {
+		event = strings.ToLower(strings.TrimSpace(event))
+		if !slices.Contains(subscribableEvents, event) {
+			return db.Webhook{}, ErrInvalidEvents
+		}
+		if !slices.Contains(webhook.Events, event) {
+			webhook.Events = append(webhook.Events, event)
+		}
+	}
+	if len(webhook.Events) == 0 {
+		return db.Webhook{}, ErrInvalidEvents
+	}
+
+	for _, symbol := range input.Symbols {
+		symbol = strings.ToLower(strings.TrimSpace(symbol))
+		if symbol == "" {
+			return db.Webhook{}, ErrInvalidSymbols
+		}
+		if !slices.Contains(webhook.Symbols, symbol) {
+			webhook.Symbols = append(webhook.Symbols, symbol)
+		}
+	}
+	if len(webhook.Symbols) > MaxWebhookSymbols {
+		return db.Webhook{}, ErrInvalidSymbols
+	}
+
+	if webhook.Secret != "" && (len(webhook.Secret) < minSecretLength || len(webhook.Secret) > maxSecretLength) {
+		return db.Webhook{}, ErrInvalidSecret
+	}
+
+	return webhook, nil
+}
+
+// List returns the webhooks of owner without their secrets.
+func (ws *WebhookService) List(owner string) ([]db.Webhook, error) {
+	webhooks, err := ws.webhookDB.List(owner)
+	if err != nil {
+		return nil, err
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+// Get returns a webhook without its secret.
+func (ws *WebhookService) Get(owner string, id int64) (*db.Webhook, error) {
+	webhook, err := ws.webhookDB.Get(owner, id)
+	if err != nil {
+		return nil, err
+	}
+	webhook.Secret = ""
+	return &webhook, nil
+}
+
+// Create stores a webhook and returns it with its secret, the only time the
+// secret is shown.
+func (ws *WebhookService) Create(owner string, input WebhookJSON) (*db.Webhook, error) {
+	webhook, err := ws.validWebhook(owner, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if webhook.Secret == "" {
+		if webhook.Secret, err = generateSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	webhook, err = ws.webhookDB.Create(webhook, MaxWebhooksPerUser)
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// Update replaces a webhook. A new secret is returned once, like on create.
+func (ws *WebhookService) Update(owner string, id int64, input WebhookJSON) (*db.Webhook, error) {
+	webhook, err := ws.validWebhook(owner, input)
+	if err != nil {
+		return nil, err
+	}
+	webhook.ID = id
+
+	updated, err := ws.webhookDB.Update(webhook)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.Secret == "" {
+		updated.Secret = ""
+	}
+	return &updated, nil
+}
+
+func (ws *WebhookService) Delete(owner string, id int64) error {
+	return ws.webhookDB.Delete(owner, id)
+}
+
+// Deliveries returns the latest deliveries of a webhook, newest first.
+func (ws *WebhookService) Deliveries(owner string, id int64, status string, limit int) ([]db.WebhookDelivery, error) {
+	status = strings.ToLower(strings.TrimSpace(status))
+	if status != "" && status != db.DeliveryPending && status != db.DeliveryDelivered && status != db.DeliveryFailed {
+		return nil, ErrInvalidStatus
+	}
+
+	if _, err := ws.webhookDB.Get(owner, id); err != nil {
+		return nil, err
+	}
+	return ws.webhookDB.Deliveries(id, status, limit)
+}
+
+// Redeliver queues a failed delivery again and wakes the dispatcher.
+func (ws *WebhookService) Redeliver(owner string, id int64, deliveryID int64) (*db.WebhookDelivery, error) {
+	webhook, err := ws.webhookDB.Get(owner, id)
+	if err != nil {
+		return nil, err
+	}
+	if !webhook.Enabled {
+		return nil, ErrWebhookDisabled
+	}
+
+	delivery, err := ws.webhookDB.Redeliver(id, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	ws.dispatcher.Wake()
+	return &delivery, nil
+}
+
+// SendTest sends a webhook.test event to a webhook and returns the delivery
+// with the outcome of its first attempt.
+func (ws *WebhookService) SendTest(owner string, id int64) (*db.WebhookDelivery, error) {
+	webhook, err := ws.webhookDB.Get(owner, id)
+	if err != nil {
+		return nil, err
+	}
+	if !webhook.Enabled {
+		return nil, ErrWebhookDisabled
+	}
+
+	delivery, err := ws.dispatcher.SendTest(webhook)
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
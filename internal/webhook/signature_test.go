@@ -0,0 +1,54 @@
+package webhook
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	got := Sign("whsec_test", 1700000000, []byte(`{"id":"evt_1"}`))
+	want := "sha256=c89214b5b5da833daed6f0b8c5bb6bd58cea9022bd80ccc78230f3942d632925"
+	if got != want {
+		t.Errorf("Sign = %q, want %q", got, want)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	const secret = "whsec_test"
+	payload := []byte(`{"id":"evt_1"}`)
+	now := time.Now().Unix()
+	signed := func(timestamp int64) string { return Sign(secret, timestamp, payload) }
+
+	tests := []struct {
+		name      string
+		secret    string
+		signature string
+		timestamp string
+		payload   []byte
+		tolerance time.Duration
+		wantErr   error
+	}{
+		{"valid", secret, signed(now), strconv.FormatInt(now, 10), payload, 5 * time.Minute, nil},
+		{"old timestamp without tolerance", secret, signed(1700000000), "1700000000", payload, 0, nil},
+		{"missing signature", secret, "", strconv.FormatInt(now, 10), payload, 0, ErrMissingSignature},
+		{"missing timestamp", secret, signed(now), "", payload, 0, ErrMissingSignature},
+		{"no sha256 prefix", secret, signed(now)[len(signaturePrefix):], strconv.FormatInt(now, 10), payload, 0, ErrInvalidSignature},
+		{"invalid timestamp", secret, signed(now), "yesterday", payload, 0, ErrInvalidSignature},
+		{"timestamp not signed", secret, signed(now), strconv.FormatInt(now+1, 10), payload, 0, ErrInvalidSignature},
+		{"tampered payload", secret, signed(now), strconv.FormatInt(now, 10), []byte(`{"id":"evt_2"}`), 0, ErrInvalidSignature},
+		{"wrong secret", "whsec_other", signed(now), strconv.FormatInt(now, 10), payload, 0, ErrInvalidSignature},
+		{"stale timestamp", secret, signed(now - 600), strconv.FormatInt(now-600, 10), payload, 5 * time.Minute, ErrStaleTimestamp},
+		{"future timestamp", secret, signed(now + 600), strconv.FormatInt(now+600, 10), payload, 5 * time.Minute, ErrStaleTimestamp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.signature, tt.timestamp, tt.payload, tt.tolerance)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package webhook
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+)
+
+const maxURLLength = 2048
+
+var (
+	ErrInvalidURL     = errors.New("url must be an absolute http or https URL")
+	ErrPrivateAddress = errors.New("webhook target is a private or loopback address")
+)
+
+// allowPrivateTargets reports whether webhooks may point at loopback and
+// private networks, e.g. a receiver on the same host during development.
+func allowPrivateTargets() bool {
+	return os.Getenv("WEBHOOK_ALLOW_PRIVATE_TARGETS") == "true"
+}
+
+func privateIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast()
+}
+
+// validURL normalizes a webhook URL. Unless allowPrivate is set, literal
+// private addresses and localhost are rejected here; host names resolving to
+// them are rejected when dialing.
+func validURL(raw string, allowPrivate bool) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || len(raw) > maxURLLength {
+		return "", ErrInvalidURL
+	}
+
+	target, err := url.Parse(raw)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Hostname() == "" || target.User != nil {
+		return "", ErrInvalidURL
+	}
+	target.Fragment = ""
+
+	if !allowPrivate {
+		host := strings.ToLower(target.Hostname())
+		if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+			return "", ErrPrivateAddress
+		}
+		if ip := net.ParseIP(host); ip != nil && privateIP(ip) {
+			return "", ErrPrivateAddress
+		}
+	}
+
+	return target.String(), nil
+}
+
+// publicOnly is a net.Dialer control refusing connections to private
+// addresses, so a public host name cannot be pointed at the internal network.
+func publicOnly(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || privateIP(ip) {
+		return ErrPrivateAddress
+	}
+	return nil
+}
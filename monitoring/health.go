@@ -20,9 +20,16 @@ type CheckResult struct {
 	Message string `json:"message,omitempty"`
 }
 
+// NamedCheck is an extra component check reported by HealthHandler. A "degraded"
+// result is reported without failing the overall health status.
+type NamedCheck struct {
+	Name  string
+	Check func() CheckResult
+}
+
 var startTime = time.Now()
 
-func HealthHandler(userDB *db.UserDB, cryptoDB *db.CryptoDB, cache *redis.RedisClient) http.HandlerFunc {
+func HealthHandler(userDB *db.UserDB, cryptoDB *db.CryptoDB, cache *redis.RedisClient, extraChecks ...NamedCheck) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		health := HealthStatus{
 			Timestamp: time.Now(),
@@ -56,6 +63,10 @@ func HealthHandler(userDB *db.UserDB, cryptoDB *db.CryptoDB, cache *redis.RedisC
 		} else {
 			health.Checks["redis"] = CheckResult{Status: "healthy"}
 		}
+
+		for _, extra := range extraChecks {
+			health.Checks[extra.Name] = extra.Check()
+		}
 		
 		health.Status = "healthy"
 		statusCode := http.StatusOK
@@ -66,6 +77,9 @@ func HealthHandler(userDB *db.UserDB, cryptoDB *db.CryptoDB, cache *redis.RedisC
 				statusCode = http.StatusServiceUnavailable
 				break
 			}
+			if check.Status == "degraded" {
+				health.Status = "degraded"
+			}
 		}
 		
 		w.Header().Set("Content-Type", "application/json")
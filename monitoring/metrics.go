@@ -79,6 +79,30 @@ var (
 		},
 		[]string{"cache_type"},
 	)
+
+	CircuitBreakerState = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "provider_circuit_breaker_state",
+			Help: "Price provider circuit breaker state (0 closed, 1 half-open, 2 open)",
+		},
+		[]string{"provider"},
+	)
+
+	CircuitBreakerTransitions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "provider_circuit_breaker_transitions_total",
+			Help: "Total number of price provider circuit breaker state changes",
+		},
+		[]string{"provider", "state"},
+	)
+
+	StalePricesServed = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "stale_prices_served_total",
+			Help: "Total number of responses served from the last known price",
+		},
+		[]string{"symbol"},
+	)
 )
 
 func RecordHTTPRequest(method, endpoint, status string, duration time.Duration) {
@@ -105,4 +129,16 @@ func UpdateCacheMetrics(cacheType string, size int64, hit bool) {
 	} else {
 		CacheMisses.WithLabelValues(cacheType).Inc()
 	}
-}
\ No newline at end of file
+}
+
+func SetCircuitBreakerState(provider string, state int) {
+	CircuitBreakerState.WithLabelValues(provider).Set(float64(state))
+}
+
+func RecordCircuitBreakerTransition(provider, state string) {
+	CircuitBreakerTransitions.WithLabelValues(provider, state).Inc()
+}
+
+func RecordStalePrice(symbol string) {
+	StalePricesServed.WithLabelValues(symbol).Inc()
+}